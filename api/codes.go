@@ -0,0 +1,16 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// HandleCodes lists every code the service reports, with its kind and a description, so
+// that clients can handle each of them.
+// GET /codes
+func HandleCodes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.AllErrorCodes())
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// currencyCode matches an ISO 4217 alphabetic code, once upper-cased.
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// normalizeCurrency trims and upper-cases a currency code.
+func normalizeCurrency(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// validateCurrency validates that s, when set, is a three-letter currency code.
+func validateCurrency(s, label string) error {
+	if strings.TrimSpace(s) == "" || currencyCode.MatchString(normalizeCurrency(s)) {
+		return nil
+	}
+	return invalid(models.CodeInvalidCurrency, label, "%s: must be a three-letter ISO 4217 currency code, got %q", label, s)
+}
+
+// responseCurrency returns the currency of req's amounts: its own currency when set, or
+// else the one currency its goals share. Amounts are never converted, so goals in more than
+// one currency get no currency and a models.CodeMixedCurrencies warning instead.
+func responseCurrency(req models.SplitRequest) (string, []models.Warning) {
+	if c := normalizeCurrency(req.Currency); c != "" {
+		return c, nil
+	}
+	seen := make(map[string]bool)
+	for _, g := range req.Goals {
+		if c := normalizeCurrency(g.Currency); c != "" {
+			seen[c] = true
+		}
+	}
+	currencies := make([]string, 0, len(seen))
+	for c := range seen {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	switch len(currencies) {
+	case 0:
+		return "", nil
+	case 1:
+		return currencies[0], nil
+	}
+	return "", []models.Warning{{
+		Code:    models.CodeMixedCurrencies,
+		Message: fmt.Sprintf("goals are in more than one currency (%s); amounts are not converted between them", strings.Join(currencies, ", ")),
+	}}
+}
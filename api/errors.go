@@ -0,0 +1,23 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// tickerLabel matches the " (ticker)" or " (goalId)" that labels add to field names.
+var tickerLabel = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// invalid returns a *models.ValidationError for the field named by label, e.g. "units
+// (AAPL)", whose Field is the label without the ticker, e.g. "units". Its Message names the
+// field itself; addError sets its Path.
+func invalid(code models.Code, label, format string, args ...any) error {
+	return &models.ValidationError{
+		Code:    code,
+		Field:   strings.TrimSpace(tickerLabel.ReplaceAllString(label, "")),
+		Message: fmt.Sprintf(format, args...),
+	}
+}
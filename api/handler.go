@@ -1,55 +1,341 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/valentinpj/smart-splitter/models"
 	"github.com/valentinpj/smart-splitter/splitter"
 )
 
+// MaxGoalsPerRequest caps the number of goals accepted in a single request.
+// It is set from MAX_GOALS_PER_REQUEST at startup.
+var MaxGoalsPerRequest = 100
+
+// MaxBodyBytes caps the size of a request body, counted after any gzip decompression, so
+// that a small compressed body cannot expand without bound.
+var MaxBodyBytes int64 = 32 << 20
+
+// MaxPortfolioSize caps the number of model items, and of holdings, in a single goal; the
+// repair step is quadratic in them. It is set from MAX_PORTFOLIO_SIZE at startup.
+var MaxPortfolioSize = 500
+
+// API versions accepted in requestVersion. They differ only in the body of a successful
+// /split response: v1 is the array of goal results, v2 the SplitResponse envelope.
+const (
+	APIVersionV1     = "v1"
+	APIVersionV2     = "v2"
+	LatestAPIVersion = APIVersionV2
+)
+
+// v1Deprecated logs the deprecation of APIVersionV1 the first time a request uses it.
+var v1Deprecated sync.Once
+
+// requestVersion returns req's API version, lower-cased, or LatestAPIVersion when absent.
+func requestVersion(req models.SplitRequest) string {
+	if v := strings.ToLower(strings.TrimSpace(req.RequestVersion)); v != "" {
+		return v
+	}
+	return LatestAPIVersion
+}
+
 func HandleSplit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Max-Goals", strconv.Itoa(MaxGoalsPerRequest))
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
 	var req models.SplitRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&req); err != nil {
 		writeError(w, "Invalid request body: "+err.Error(), "Bad Request", http.StatusBadRequest)
 		return
 	}
 
-	amountPrec, unitPrec, err := validateRequest(&req)
-	if err != nil {
-		writeError(w, err.Error(), "Bad Request", http.StatusBadRequest)
+	if req.IdempotencyKey == "" {
+		writeSplit(w, req)
 		return
 	}
 
-	var results []models.GoalResult
-	for _, goal := range req.Goals {
-		switch strings.ToLower(goal.OrderType) {
-		case "investment":
-			results = append(results, splitter.ProcessInvestment(goal, amountPrec, unitPrec))
-		case "redemption":
-			results = append(results, splitter.ProcessRedemption(goal, amountPrec, unitPrec, req.VolatilityBuffer))
-		default:
-			writeError(w, "Unsupported order type: "+goal.OrderType, "Bad Request", http.StatusBadRequest)
+	// A retry with the same key and body replays the earlier response instead of splitting
+	// again; reusing a key for a different body is rejected.
+	bodyHash := sha256.Sum256(body)
+	if c, ok := loadCachedResponse(req.IdempotencyKey); ok {
+		if c.bodyHash != bodyHash {
+			writeCodedError(w, "idempotencyKey: already used for a different request body",
+				"Conflict", models.CodeIdempotencyKeyReused, http.StatusConflict)
 			return
 		}
+		writeCachedResponse(w, c)
+		return
+	}
+	w.Header().Set("X-Idempotency-Cache", "miss")
+	rec := &responseRecorder{ResponseWriter: w}
+	writeSplit(rec, req)
+	if rec.status == http.StatusOK {
+		storeCachedResponse(req.IdempotencyKey, bodyHash, rec)
+	}
+}
+
+// writeSplit validates req, runs the splitter on every goal and writes the response in the
+// shape of req's requestVersion.
+func writeSplit(w http.ResponseWriter, req models.SplitRequest) {
+	resp, err := Split(req)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
+
+	if resp.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", resp.NextCursor)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if requestVersion(req) == APIVersionV1 {
+		w.Header().Set("Deprecation", "true")
+		v1Deprecated.Do(func() {
+			log.Printf("requestVersion %q is deprecated; send %q for the SplitResponse envelope", APIVersionV1, LatestAPIVersion)
+		})
+		json.NewEncoder(w).Encode(resp.Results)
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RequestError is a request-level failure together with the HTTP status it maps to.
+// Every transport reports it in its own way.
+type RequestError struct {
+	StatusCode int
+	Status     string      // e.g. "Bad Request"
+	Code       models.Code // optional machine-readable code
+	Message    string
+	Errors     []models.ValidationError // every problem found, when validation failed
+}
+
+func (e *RequestError) Error() string { return e.Message }
+
+func badRequest(message string) *RequestError {
+	return &RequestError{StatusCode: http.StatusBadRequest, Status: "Bad Request", Message: message}
+}
+
+// validationFailed wraps the errors collected by validateRequest in a 422 RequestError.
+// Message joins them for transports that can only carry a single string.
+func validationFailed(errs []models.ValidationError) *RequestError {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Message
+		if e.Path != "" {
+			msgs[i] = e.Path + ": " + e.Message
+		}
+	}
+	return &RequestError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Status:     "Unprocessable Entity",
+		Code:       models.CodeValidationFailed,
+		Message:    strings.Join(msgs, "; "),
+		Errors:     errs,
+	}
+}
+
+// Split validates req and runs the splitter on every goal, or on the page of goals selected
+// by req.PageSize and req.Cursor. It is the transport-independent core shared by the HTTP
+// and gRPC servers; failures are returned as *RequestError.
+func Split(req models.SplitRequest) (models.SplitResponse, error) {
+	if err := checkRequestSize(req.Goals); err != nil {
+		return models.SplitResponse{}, err
+	}
+
+	amountPrec, unitPrec, verrs := validateRequest(&req, true)
+	if len(verrs) > 0 {
+		return models.SplitResponse{}, validationFailed(verrs)
+	}
+
+	start, end, next := page(req)
+	batch := req
+	batch.Goals = req.Goals[start:end]
+	results, errs := splitter.ProcessBatch(batch, amountPrec, unitPrec)
+	// Goals are reported as if split one after another: the first to fail, or to break
+	// strict mode before it, fails the request.
+	failed := len(results)
+	if len(errs) > 0 {
+		failed = errs[0].Index
+	}
+	if req.StrictMode {
+		for _, result := range results[:failed] {
+			if err := strictModeError(result); err != nil {
+				return models.SplitResponse{}, err
+			}
+		}
+	}
+	if len(errs) > 0 {
+		if errors.Is(errs[0].Err, splitter.ErrUnsupportedOrderType) {
+			return models.SplitResponse{}, badRequest(errs[0].Err.Error())
+		}
+		return models.SplitResponse{}, errs[0]
 	}
+	currency, warnings := responseCurrency(req)
+	if req.Quiet {
+		warnings = nil
+	}
+	return models.SplitResponse{Results: results, NextCursor: next, Currency: currency, Warnings: warnings, ClientReference: req.ClientReference}, nil
+}
 
+// strictModeError returns the first blocking error of a trade in result as a 422
+// RequestError carrying its code, or nil when no trade has one. Errors downgraded to
+// warnings do not count.
+func strictModeError(result models.GoalResult) error {
+	for _, d := range result.TransactionDetails {
+		if e := splitter.BlockingError(d); e != nil {
+			return &RequestError{
+				StatusCode: http.StatusUnprocessableEntity,
+				Status:     "Unprocessable Entity",
+				Code:       e.Code,
+				Message:    fmt.Sprintf("goal %s, ticker %s: %s", result.GoalID, d.Ticker, e.Message),
+			}
+		}
+	}
+	return nil
+}
+
+// HandleDrift reports how far every goal of a /split request body has drifted from its
+// model, without computing any trades. Validation failures are written like those of /split.
+// POST /drift
+func HandleDrift(w http.ResponseWriter, r *http.Request) {
+	var req models.SplitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	resp, err := Drift(req)
+	if err != nil {
+		writeRequestError(w, err)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Drift validates req as Split does, except for each goal's order, which it ignores, and reports
+// every goal's drift from its model on the weights the goal would be split on. Paging does
+// not apply; failures are returned as *RequestError.
+func Drift(req models.SplitRequest) (models.DriftResponse, error) {
+	if err := checkRequestSize(req.Goals); err != nil {
+		return models.DriftResponse{}, err
+	}
+	amountPrec, unitPrec, verrs := validateRequest(&req, false)
+	if len(verrs) > 0 {
+		return models.DriftResponse{}, validationFailed(verrs)
+	}
+
+	opts := splitter.RequestOptions(req)
+	weightPrec, roundWeights := splitter.WeightPrecisions(req)
+	resp := models.DriftResponse{Results: make([]models.DriftReport, len(req.Goals))}
+	for i, goal := range req.Goals {
+		goal = splitter.ApplyGlidePath(goal, opts.Today)
+		if roundWeights >= 0 {
+			goal = splitter.RoundWeights(goal, roundWeights)
+		}
+		if req.RecomputeHoldingValues {
+			goal, _ = splitter.RecomputeHoldingValues(goal, amountPrec)
+		}
+		resp.Results[i] = splitter.GoalDrift(goal, amountPrec, unitPrec, weightPrec, opts)
+	}
+	return resp, nil
+}
+
+// checkRequestSize rejects a request with more than MaxGoalsPerRequest goals, or with a
+// goal too large for checkPortfolioSize.
+func checkRequestSize(goals []models.Goal) error {
+	if len(goals) > MaxGoalsPerRequest {
+		return &RequestError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Status:     "Request Entity Too Large",
+			Code:       models.CodeTooManyGoals,
+			Message:    fmt.Sprintf("goals: at most %d goals are allowed per request, got %d", MaxGoalsPerRequest, len(goals)),
+		}
+	}
+	return checkPortfolioSize(goals)
+}
+
+// checkPortfolioSize rejects the first goal with more than MaxPortfolioSize model items or
+// holdings, before any of it is validated or split.
+func checkPortfolioSize(goals []models.Goal) error {
+	for i, g := range goals {
+		for _, f := range []struct {
+			name string
+			n    int
+		}{
+			{"modelPortfolioDetails", len(g.ModelPortfolioDetails)},
+			{"goalDetails", len(g.GoalDetails)},
+		} {
+			if f.n > MaxPortfolioSize {
+				return &RequestError{
+					StatusCode: http.StatusUnprocessableEntity,
+					Status:     "Unprocessable Entity",
+					Code:       models.CodePortfolioTooLarge,
+					Message:    fmt.Sprintf("goals[%d].%s: at most %d items are allowed per goal, got %d", i, f.name, MaxPortfolioSize, f.n),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeRequestError writes err as an ErrorResponse, using its status when it is a
+// *RequestError and 500 otherwise. Validation failures are written as the JSON array of
+// every ValidationError instead.
+func writeRequestError(w http.ResponseWriter, err error) {
+	var re *RequestError
+	if !errors.As(err, &re) {
+		writeError(w, err.Error(), "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if len(re.Errors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(re.StatusCode)
+		json.NewEncoder(w).Encode(re.Errors)
+		return
+	}
+	writeCodedError(w, re.Message, re.Status, re.Code, re.StatusCode)
 }
 
 func writeError(w http.ResponseWriter, message, errStr string, statusCode int) {
+	writeCodedError(w, message, errStr, "", statusCode)
+}
+
+// writeBodyError writes the error for a request body that could not be read or decoded: a
+// 413 when it is larger than MaxBodyBytes, otherwise a 400.
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeCodedError(w, fmt.Sprintf("request body: at most %d bytes are allowed", tooLarge.Limit),
+			"Request Entity Too Large", models.CodeBodyTooLarge, http.StatusRequestEntityTooLarge)
+		return
+	}
+	writeError(w, "Invalid request body: "+err.Error(), "Bad Request", http.StatusBadRequest)
+}
+
+// writeCodedError writes an ErrorResponse carrying a machine-readable code.
+func writeCodedError(w http.ResponseWriter, message, errStr string, code models.Code, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(models.ErrorResponse{
 		Message:    message,
 		Error:      errStr,
 		StatusCode: statusCode,
+		Code:       code,
 	})
 }
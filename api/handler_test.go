@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestSplitResponseShapeByVersion(t *testing.T) {
+	h := http.HandlerFunc(HandleSplit)
+	for _, version := range []string{"", APIVersionV1, APIVersionV2} {
+		t.Run(version, func(t *testing.T) {
+			req := validRequest()
+			req.RequestVersion = version
+			req.ClientReference = "ref-1"
+			rec := do(t, h, http.MethodPost, "/split", req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+			}
+
+			if version == APIVersionV1 {
+				results := decode[[]models.GoalResult](t, rec)
+				if len(results) != 1 || results[0].GoalID != "g1" {
+					t.Errorf("results = %+v, want goal g1", results)
+				}
+				if got := rec.Header().Get("Deprecation"); got != "true" {
+					t.Errorf("Deprecation %q, want true", got)
+				}
+				return
+			}
+			resp := decode[models.SplitResponse](t, rec)
+			if len(resp.Results) != 1 || resp.Results[0].GoalID != "g1" {
+				t.Errorf("results = %+v, want goal g1", resp.Results)
+			}
+			if resp.ClientReference != "ref-1" {
+				t.Errorf("clientReference %q, want ref-1", resp.ClientReference)
+			}
+			if got := rec.Header().Get("Deprecation"); got != "" {
+				t.Errorf("Deprecation %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestDriftIgnoresTheOrder(t *testing.T) {
+	h := http.HandlerFunc(HandleDrift)
+	goal := func(id string) models.Goal {
+		return testhelpers.NewGoalBuilder(id).
+			WithTicker("A", "0.5", "1").
+			WithTicker("B", "0.5", "1").
+			WithHolding("A", "300", "1").
+			Build()
+	}
+	noOrder, unitInvestment, tooLarge := goal("no-order"), goal("unit-investment"), goal("too-large")
+	noOrder.OrderAmount = ""
+	unitInvestment.OrderAmount, unitInvestment.OrderUnit = "", "5"
+	tooLarge.OrderType, tooLarge.OrderAmount = "Redemption", "1000000"
+
+	rec := do(t, h, http.MethodPost, "/drift", validRequest(noOrder, unitInvestment, tooLarge))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+	}
+	if got := decode[models.DriftResponse](t, rec).Results; len(got) != 3 || got[0].MaxDriftBps != "5000.00" {
+		t.Errorf("results = %+v, want 3 with a max drift of 5000.00 bps", got)
+	}
+
+	bad := goal("bad-weight")
+	bad.ModelPortfolioDetails[0].Weight = "abc"
+	rec = do(t, h, http.MethodPost, "/drift", validRequest(bad))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("bad weight: status %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if errs := decode[[]models.ValidationError](t, rec); len(errs) != 1 || errs[0].Field != "weight" {
+		t.Errorf("bad weight: errors %+v, want one on weight", errs)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// validRequest returns a request that passes validation: goals, or a single 1000 investment
+// split evenly over A and B when none are given.
+func validRequest(goals ...models.Goal) models.SplitRequest {
+	if len(goals) == 0 {
+		goals = []models.Goal{testhelpers.NewGoalBuilder("g1").
+			WithTicker("A", "0.5", "10").
+			WithTicker("B", "0.5", "20").
+			Build()}
+	}
+	return models.SplitRequest{AmountDecimalPrecision: "2", UnitDecimalPrecision: "4", Goals: goals}
+}
+
+// do sends a request to h and returns the recorded response; see newRequest.
+func do(t *testing.T, h http.Handler, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return serve(h, newRequest(t, method, target, body))
+}
+
+// newRequest returns a test request. A body that is not already a string or []byte is
+// encoded as JSON.
+func newRequest(t *testing.T, method, target string, body any) *http.Request {
+	t.Helper()
+	var b []byte
+	switch v := body.(type) {
+	case nil:
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			t.Fatalf("encoding body: %v", err)
+		}
+	}
+	return httptest.NewRequest(method, target, bytes.NewReader(b))
+}
+
+// serve sends r to h and returns the recorded response.
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+// decode decodes rec's body as JSON into a value of type T.
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	return v
+}
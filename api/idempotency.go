@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyTTL is how long a successful /split response is replayed for a retried
+// request carrying the same idempotencyKey.
+const IdempotencyTTL = 10 * time.Minute
+
+type cachedResponse struct {
+	bodyHash [sha256.Size]byte // hash of the request body that produced the response
+	header   http.Header
+	body     []byte
+	expires  time.Time
+}
+
+// idempotencyCache maps idempotencyKey -> *cachedResponse.
+var idempotencyCache sync.Map
+
+// loadCachedResponse returns the live cached response for key, evicting it if it has expired.
+func loadCachedResponse(key string) (*cachedResponse, bool) {
+	v, ok := idempotencyCache.Load(key)
+	if !ok {
+		return nil, false
+	}
+	c := v.(*cachedResponse)
+	if time.Now().After(c.expires) {
+		idempotencyCache.Delete(key)
+		return nil, false
+	}
+	return c, true
+}
+
+// storeCachedResponse caches a successful response for key and evicts expired entries.
+// The body is recorded before any response compression, so the headers describing the
+// encoding and length of what went on the wire are not cached: a replay negotiates its
+// own encoding. Nor is the cache status of the first response.
+func storeCachedResponse(key string, bodyHash [sha256.Size]byte, rec *responseRecorder) {
+	now := time.Now()
+	header := rec.Header().Clone()
+	for _, k := range []string{"Content-Encoding", "Content-Length", "X-Idempotency-Cache"} {
+		header.Del(k)
+	}
+	idempotencyCache.Range(func(k, v any) bool {
+		if now.After(v.(*cachedResponse).expires) {
+			idempotencyCache.Delete(k)
+		}
+		return true
+	})
+	idempotencyCache.Store(key, &cachedResponse{
+		bodyHash: bodyHash,
+		header:   header,
+		body:     rec.body.Bytes(),
+		expires:  now.Add(IdempotencyTTL),
+	})
+}
+
+// writeCachedResponse replays c as a cache hit. The correlation ID is the retry's own, not
+// the one the response was first sent with.
+func writeCachedResponse(w http.ResponseWriter, c *cachedResponse) {
+	for k, v := range c.header {
+		if k == http.CanonicalHeaderKey(CorrelationIDHeader) {
+			continue
+		}
+		w.Header()[k] = v
+	}
+	w.Header().Set("X-Idempotency-Cache", "hit")
+	w.WriteHeader(http.StatusOK)
+	w.Write(c.body)
+}
+
+// responseRecorder passes a response through to the client while keeping a copy, so that
+// it can be cached once it is known to have succeeded.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+func idempotentRequest(key string) models.SplitRequest {
+	req := validRequest()
+	req.IdempotencyKey = key
+	return req
+}
+
+func TestIdempotencyMissThenHit(t *testing.T) {
+	h := http.HandlerFunc(HandleSplit)
+	req := idempotentRequest(t.Name())
+
+	first := do(t, h, http.MethodPost, "/split", req)
+	if first.Code != http.StatusOK || first.Header().Get("X-Idempotency-Cache") != "miss" {
+		t.Fatalf("first: status %d, cache %q, want 200 miss", first.Code, first.Header().Get("X-Idempotency-Cache"))
+	}
+	retry := do(t, h, http.MethodPost, "/split", req)
+	if retry.Code != http.StatusOK || retry.Header().Get("X-Idempotency-Cache") != "hit" {
+		t.Fatalf("retry: status %d, cache %q, want 200 hit", retry.Code, retry.Header().Get("X-Idempotency-Cache"))
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("replayed body %s, want %s", retry.Body, first.Body)
+	}
+	if got := retry.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("replayed Content-Type %q, want application/json", got)
+	}
+}
+
+func TestIdempotencyKeyReusedForAnotherBody(t *testing.T) {
+	h := http.HandlerFunc(HandleSplit)
+	req := idempotentRequest(t.Name())
+	do(t, h, http.MethodPost, "/split", req)
+
+	req.Goals[0].OrderAmount = "2000"
+	rec := do(t, h, http.MethodPost, "/split", req)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if got := decode[models.ErrorResponse](t, rec).Code; got != models.CodeIdempotencyKeyReused {
+		t.Errorf("code %q, want %q", got, models.CodeIdempotencyKeyReused)
+	}
+}
+
+func TestIdempotencyDoesNotCacheFailures(t *testing.T) {
+	h := http.HandlerFunc(HandleSplit)
+	req := idempotentRequest(t.Name())
+	req.Goals[0].OrderAmount = "-1"
+	if rec := do(t, h, http.MethodPost, "/split", req); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if _, ok := loadCachedResponse(req.IdempotencyKey); ok {
+		t.Error("a failed response was cached")
+	}
+}
+
+func TestIdempotencyExpires(t *testing.T) {
+	h := http.HandlerFunc(HandleSplit)
+	req := idempotentRequest(t.Name())
+	do(t, h, http.MethodPost, "/split", req)
+	v, _ := idempotencyCache.Load(req.IdempotencyKey)
+	v.(*cachedResponse).expires = time.Now().Add(-time.Second)
+
+	if rec := do(t, h, http.MethodPost, "/split", req); rec.Header().Get("X-Idempotency-Cache") != "miss" {
+		t.Errorf("cache %q after expiry, want miss", rec.Header().Get("X-Idempotency-Cache"))
+	}
+}
+
+// TestIdempotencyReplayNegotiatesEncoding checks that a response first sent gzipped is
+// replayed in the encoding the retry accepts, and the other way round.
+func TestIdempotencyReplayNegotiatesEncoding(t *testing.T) {
+	h := WithDecompression(http.HandlerFunc(HandleSplit))
+	for _, firstGzip := range []bool{true, false} {
+		req := idempotentRequest(t.Name())
+		if firstGzip {
+			req.IdempotencyKey += "/gzip-first"
+		}
+		send := func(gz bool) (string, http.Header) {
+			r := newRequest(t, http.MethodPost, "/split", req)
+			if gz {
+				r.Header.Set("Accept-Encoding", "gzip")
+			}
+			rec := serve(h, r)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", rec.Code, rec.Body)
+			}
+			body := rec.Body.Bytes()
+			if rec.Header().Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if body, err = io.ReadAll(zr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return string(body), rec.Header()
+		}
+
+		first, _ := send(firstGzip)
+		replay, header := send(!firstGzip)
+		if header.Get("X-Idempotency-Cache") != "hit" {
+			t.Fatalf("gzip first %v: cache %q, want hit", firstGzip, header.Get("X-Idempotency-Cache"))
+		}
+		want := "gzip"
+		if firstGzip {
+			want = ""
+		}
+		if got := header.Get("Content-Encoding"); got != want {
+			t.Errorf("gzip first %v: replay Content-Encoding %q, want %q", firstGzip, got, want)
+		}
+		if replay != first {
+			t.Errorf("gzip first %v: replayed %s, want %s", firstGzip, replay, first)
+		}
+	}
+}
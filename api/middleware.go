@@ -0,0 +1,255 @@
+package api
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"golang.org/x/time/rate"
+)
+
+// CorrelationIDHeader carries a caller's own ID for a request; it is echoed on the response.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// WithCorrelationID returns middleware that echoes the request's X-Correlation-ID header,
+// when it has one, on the response, whatever its status.
+func WithCorrelationID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get(CorrelationIDHeader); id != "" {
+			w.Header().Set(CorrelationIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithAPIKeyAuth returns middleware that rejects requests whose X-API-Key header is not one
+// of keys with a 401 ErrorResponse. With no keys every request is let through, for
+// development.
+func WithAPIKeyAuth(keys []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(keys) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !validAPIKey(r.Header.Get("X-API-Key"), keys) {
+				writeCodedError(w, "missing or invalid X-API-Key header", "Unauthorized", models.CodeUnauthorized, http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validAPIKey reports whether key is one of keys. Every key is compared in constant time,
+// so the response time does not reveal how much of a key was right.
+func validAPIKey(key string, keys []string) bool {
+	ok := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok && key != ""
+}
+
+// RateLimitIdle is how long a client IP's limiter is kept after its last request. Idle
+// limiters are swept at most once per RateLimitIdle, and never before their bucket has
+// refilled, so dropping one does not change what its client is allowed.
+const RateLimitIdle = 10 * time.Minute
+
+// clientLimiter is the token bucket of one client IP, with the time of its last request in
+// Unix nanoseconds.
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+// WithRateLimit returns middleware that limits each client IP to rps requests per second,
+// with bursts of up to burst, by a token bucket. A request over the limit is rejected with a
+// 429 ErrorResponse and a Retry-After header giving the seconds until it would be let
+// through. With rps ≤ 0 every request is let through. A client's limiter is dropped once it
+// has been idle for RateLimitIdle, so the map does not grow with every IP ever seen.
+func WithRateLimit(rps float64, burst int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if rps <= 0 {
+			return next
+		}
+		rl := newRateLimiter(rps, burst, time.Now())
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if retry, ok := rl.reserve(clientIP(r), time.Now()); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retry))
+				writeCodedError(w, "rate limit exceeded; retry later", "Too Many Requests", models.CodeRateLimited, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimiter holds the limiters of WithRateLimit, one per client IP. It is safe for
+// concurrent use.
+type rateLimiter struct {
+	rps       float64
+	burst     int
+	idle      time.Duration
+	limiters  sync.Map // client IP -> *clientLimiter
+	lastSweep atomic.Int64
+}
+
+func newRateLimiter(rps float64, burst int, now time.Time) *rateLimiter {
+	// A limiter idle for longer than it takes to refill its bucket is as good as new.
+	rl := &rateLimiter{
+		rps:   rps,
+		burst: burst,
+		idle:  max(RateLimitIdle, time.Duration(float64(burst)/rps*float64(time.Second))),
+	}
+	rl.lastSweep.Store(now.UnixNano())
+	return rl
+}
+
+// reserve takes a token from ip's bucket at now. When there is none it takes nothing and
+// returns the whole seconds until there would be, at least 1, and false.
+func (rl *rateLimiter) reserve(ip string, now time.Time) (retryAfter int, ok bool) {
+	rl.sweep(now)
+	l, ok := rl.limiters.Load(ip)
+	if !ok {
+		l, _ = rl.limiters.LoadOrStore(ip, &clientLimiter{limiter: rate.NewLimiter(rate.Limit(rl.rps), rl.burst)})
+	}
+	cl := l.(*clientLimiter)
+	cl.lastSeen.Store(now.UnixNano())
+	res := cl.limiter.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); !res.OK() || delay > 0 {
+		res.CancelAt(now)
+		retry := int(math.Ceil(delay.Seconds()))
+		if !res.OK() || retry < 1 {
+			retry = 1
+		}
+		return retry, false
+	}
+	return 0, true
+}
+
+// sweep drops the limiters idle for longer than rl.idle, at most once per rl.idle.
+func (rl *rateLimiter) sweep(now time.Time) {
+	n := now.UnixNano()
+	last := rl.lastSweep.Load()
+	if n-last <= int64(rl.idle) || !rl.lastSweep.CompareAndSwap(last, n) {
+		return
+	}
+	rl.limiters.Range(func(ip, l any) bool {
+		if n-l.(*clientLimiter).lastSeen.Load() > int64(rl.idle) {
+			rl.limiters.Delete(ip)
+		}
+		return true
+	})
+}
+
+// clientIP returns the IP of r's client from its RemoteAddr, or RemoteAddr itself when it
+// has no port. Proxy headers are not trusted.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// WithDecompression returns next with gzip request and response bodies. A request sent with
+// Content-Encoding gzip is decompressed before next reads it; one in any other encoding is
+// rejected with a 415 ErrorResponse, and one that is not gzip at all with a 400. Whatever
+// the encoding, next can read at most MaxBodyBytes of the body, decompressed; see
+// writeBodyError. The response is compressed when the request's Accept-Encoding allows gzip.
+func WithDecompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch enc := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); enc {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			body, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeError(w, "Invalid request body: "+err.Error(), "Bad Request", http.StatusBadRequest)
+				return
+			}
+			defer body.Close()
+			r.Body = body
+			r.ContentLength = -1
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+		default:
+			writeCodedError(w, fmt.Sprintf("unsupported Content-Encoding %q; send gzip or identity", enc),
+				"Unsupported Media Type", models.CodeUnsupportedEncoding, http.StatusUnsupportedMediaType)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header lists gzip without q=0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if c := strings.ToLower(strings.TrimSpace(coding)); c != "gzip" && c != "x-gzip" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses what is written to it. The gzip stream is started by the
+// first WriteHeader or Write of a response that may have a body, and must be ended by Close.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	zw *gzip.Writer
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if status != http.StatusNoContent && status != http.StatusNotModified {
+		g.start()
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	g.start()
+	return g.zw.Write(b)
+}
+
+func (g *gzipResponseWriter) start() {
+	if g.zw != nil {
+		return
+	}
+	g.Header().Set("Content-Encoding", "gzip")
+	g.Header().Del("Content-Length")
+	g.zw = gzip.NewWriter(g.ResponseWriter)
+}
+
+// Close flushes the gzip stream, if one was started.
+func (g *gzipResponseWriter) Close() error {
+	if g.zw == nil {
+		return nil
+	}
+	return g.zw.Close()
+}
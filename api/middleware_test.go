@@ -0,0 +1,275 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// okHandler answers every request with 200 and no body.
+var okHandler = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+func requestFrom(t *testing.T, remoteAddr string) *http.Request {
+	t.Helper()
+	r := newRequest(t, http.MethodPost, "/split", nil)
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func TestRateLimitRejectsOverTheBurst(t *testing.T) {
+	h := WithRateLimit(1, 2)(okHandler)
+	for i := range 2 {
+		if rec := serve(h, requestFrom(t, "192.0.2.1:1000")); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+	rec := serve(h, requestFrom(t, "192.0.2.1:2000"))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := decode[models.ErrorResponse](t, rec).Code; got != models.CodeRateLimited {
+		t.Errorf("code %q, want %q", got, models.CodeRateLimited)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After %q, want 1", got)
+	}
+
+	if rec := serve(h, requestFrom(t, "192.0.2.2:1000")); rec.Code != http.StatusOK {
+		t.Errorf("another IP: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRateLimitOffWithoutRPS(t *testing.T) {
+	h := WithRateLimit(0, 0)(okHandler)
+	for i := range 10 {
+		if rec := serve(h, requestFrom(t, "192.0.2.1:1000")); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	t0 := time.Now()
+	rl := newRateLimiter(0.25, 1, t0)
+	if _, ok := rl.reserve("a", t0); !ok {
+		t.Fatal("first request rejected")
+	}
+	tests := []struct {
+		at        time.Duration
+		wantRetry int
+	}{
+		{0, 4},
+		{time.Second, 3},
+		{3500 * time.Millisecond, 1},
+	}
+	for _, tt := range tests {
+		retry, ok := rl.reserve("a", t0.Add(tt.at))
+		if ok || retry != tt.wantRetry {
+			t.Errorf("at %v: retry %d, ok %v, want %d, false", tt.at, retry, ok, tt.wantRetry)
+		}
+	}
+	if _, ok := rl.reserve("a", t0.Add(4*time.Second)); !ok {
+		t.Error("request rejected once the bucket refilled")
+	}
+}
+
+func TestRateLimitSweepsIdleLimiters(t *testing.T) {
+	t0 := time.Now()
+	rl := newRateLimiter(1, 1, t0)
+	rl.reserve("idle", t0)
+	rl.reserve("busy", t0)
+
+	rl.reserve("busy", t0.Add(RateLimitIdle/2))
+	if _, ok := rl.limiters.Load("idle"); !ok {
+		t.Fatal("limiter swept before RateLimitIdle")
+	}
+	rl.reserve("busy", t0.Add(RateLimitIdle+time.Second))
+	if _, ok := rl.limiters.Load("idle"); ok {
+		t.Error("limiter idle for longer than RateLimitIdle kept")
+	}
+	if _, ok := rl.limiters.Load("busy"); !ok {
+		t.Error("active limiter swept")
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		key  string // X-API-Key sent; "" sends none
+		want int
+	}{
+		{"valid key", []string{"k1", "k2"}, "k2", http.StatusOK},
+		{"missing key", []string{"k1"}, "", http.StatusUnauthorized},
+		{"wrong key", []string{"k1"}, "k3", http.StatusUnauthorized},
+		{"prefix of a key", []string{"k1"}, "k", http.StatusUnauthorized},
+		{"no keys configured", nil, "", http.StatusOK},
+		{"no keys configured, key sent", nil, "k1", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(t, http.MethodPost, "/split", nil)
+			if tt.key != "" {
+				r.Header.Set("X-API-Key", tt.key)
+			}
+			rec := serve(WithAPIKeyAuth(tt.keys)(okHandler), r)
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized {
+				if got := decode[models.ErrorResponse](t, rec).Code; got != models.CodeUnauthorized {
+					t.Errorf("code %q, want %q", got, models.CodeUnauthorized)
+				}
+			}
+		})
+	}
+}
+
+func TestValidAPIKeyRejectsEmpty(t *testing.T) {
+	// An empty key must not match even a configured empty key.
+	if validAPIKey("", []string{"", "k1"}) {
+		t.Error(`validAPIKey("") = true, want false`)
+	}
+	if !validAPIKey("k1", []string{"", "k1"}) {
+		t.Error(`validAPIKey("k1") = false, want true`)
+	}
+}
+
+// echoHandler answers with the request body it reads.
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	w.Write(body)
+})
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressionRequestBodies(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     int
+		wantCode models.Code
+	}{
+		{"identity", "", []byte("hello"), http.StatusOK, ""},
+		{"explicit identity", "identity", []byte("hello"), http.StatusOK, ""},
+		{"gzip", "gzip", gzipped(t, "hello"), http.StatusOK, ""},
+		{"x-gzip", "X-Gzip", gzipped(t, "hello"), http.StatusOK, ""},
+		{"not gzip", "gzip", []byte("hello"), http.StatusBadRequest, ""},
+		{"deflate", "deflate", []byte("hello"), http.StatusUnsupportedMediaType, models.CodeUnsupportedEncoding},
+		{"br", "br", []byte("hello"), http.StatusUnsupportedMediaType, models.CodeUnsupportedEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRequest(t, http.MethodPost, "/split", tt.body)
+			r.Header.Set("Content-Encoding", tt.encoding)
+			rec := serve(WithDecompression(echoHandler), r)
+			if rec.Code != tt.want {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != "hello" {
+				t.Errorf("handler read %q, want hello", rec.Body)
+			}
+			if tt.wantCode != "" {
+				if got := decode[models.ErrorResponse](t, rec).Code; got != tt.wantCode {
+					t.Errorf("code %q, want %q", got, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+// TestDecompressionCapsTheDecompressedBody checks that a small gzip body expanding past
+// MaxBodyBytes is rejected.
+func TestDecompressionCapsTheDecompressedBody(t *testing.T) {
+	defer func(n int64) { MaxBodyBytes = n }(MaxBodyBytes)
+	MaxBodyBytes = 4 << 10
+
+	body := gzipped(t, strings.Repeat(" ", 1<<20))
+	if int64(len(body)) > MaxBodyBytes {
+		t.Fatalf("compressed body of %d bytes is above the cap", len(body))
+	}
+	r := newRequest(t, http.MethodPost, "/split", body)
+	r.Header.Set("Content-Encoding", "gzip")
+	rec := serve(WithDecompression(echoHandler), r)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if got := decode[models.ErrorResponse](t, rec).Code; got != models.CodeBodyTooLarge {
+		t.Errorf("code %q, want %q", got, models.CodeBodyTooLarge)
+	}
+}
+
+func TestDecompressionCompressesResponses(t *testing.T) {
+	r := newRequest(t, http.MethodPost, "/split", "hello")
+	r.Header.Set("Accept-Encoding", "br, gzip;q=0.5")
+	rec := serve(WithDecompression(echoHandler), r)
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding %q, want gzip", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary %q, want Accept-Encoding", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := io.ReadAll(zr); err != nil || string(body) != "hello" {
+		t.Errorf("decompressed %q, %v, want hello", body, err)
+	}
+
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	r = newRequest(t, http.MethodPost, "/split", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	rec = serve(WithDecompression(noContent), r)
+	if got := rec.Header().Get("Content-Encoding"); got != "" || rec.Body.Len() != 0 {
+		t.Errorf("204: Content-Encoding %q and %d body bytes, want neither", got, rec.Body.Len())
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"GZIP", true},
+		{"x-gzip", true},
+		{"deflate, br", false},
+		{"deflate, gzip", true},
+		{"gzip;q=0.5", true},
+		{"gzip; q=1", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0", false},
+		{"gzip;q=0.0", false},
+		{"gzip;q=0.001", true},
+		{"*", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsGzip(tt.header); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// encodeCursor returns the cursor that resumes a paginated request at goal index i.
+func encodeCursor(i int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(i)))
+}
+
+// decodeCursor returns the goal index encoded in cursor.
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cursor))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(b))
+}
+
+// validatePage checks pageSize and cursor against the number of goals in the request.
+func validatePage(req models.SplitRequest, errs *[]models.ValidationError) {
+	if strings.TrimSpace(req.PageSize) != "" {
+		if n, err := strconv.Atoi(strings.TrimSpace(req.PageSize)); err != nil {
+			addError(errs, "", invalid(models.CodeInvalidInteger, "pageSize", "pageSize: must be a positive integer"))
+		} else if n < 1 {
+			addError(errs, "", invalid(models.CodeMustBePositive, "pageSize", "pageSize: must be a positive integer"))
+		}
+	}
+	if strings.TrimSpace(req.Cursor) != "" {
+		if i, err := decodeCursor(req.Cursor); err != nil || i < 0 || i >= len(req.Goals) {
+			addError(errs, "", invalid(models.CodeInvalidCursor, "cursor", "cursor: must be a cursor returned for an earlier page of this request"))
+		}
+	}
+}
+
+// page returns the half-open range of goals to split for req, and the cursor that resumes
+// after it ("" once the last goal is included). req must have passed validatePage.
+func page(req models.SplitRequest) (start, end int, next string) {
+	end = len(req.Goals)
+	if strings.TrimSpace(req.Cursor) != "" {
+		start, _ = decodeCursor(req.Cursor)
+	}
+	if size, err := strconv.Atoi(strings.TrimSpace(req.PageSize)); err == nil && start+size < end {
+		end = start + size
+		next = encodeCursor(end)
+	}
+	return start, end, next
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// SessionTTL is how long a session survives without being created, patched or split.
+const SessionTTL = 5 * time.Minute
+
+type session struct {
+	req     models.SplitRequest
+	expires time.Time
+}
+
+// sessions maps token -> *session. Sessions are opt-in; /split stays stateless.
+var sessions sync.Map
+
+// sessionMu serialises every write to a stored session, PATCH merges and the TTL refresh of
+// a split alike, so that no write stores a request older than the one it replaces.
+var sessionMu sync.Mutex
+
+type sessionResponse struct {
+	Token     string              `json:"token"`
+	ExpiresAt string              `json:"expiresAt"`
+	Request   models.SplitRequest `json:"request"`
+}
+
+// HandleCreateSession stores a (possibly partial) SplitRequest and returns its token.
+// POST /sessions
+func HandleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req models.SplitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	sweepSessions()
+
+	token, err := newToken()
+	if err != nil {
+		writeError(w, "Could not create session", "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	s := &session{req: req, expires: time.Now().Add(SessionTTL)}
+	sessions.Store(token, s)
+	writeSession(w, http.StatusCreated, token, s)
+}
+
+// HandlePatchSession merges a partial SplitRequest into the stored one.
+// PATCH /sessions/{token}
+//
+// The body is a JSON merge patch (RFC 7396) with one extension: arrays of objects keyed by
+// goalId or ticker are merged element-by-element, so a single goal or model item can be
+// added or updated without resending the rest. An element marked for deletion, e.g.
+// {"ticker": "AGG", "_delete": true}, is removed.
+func HandlePatchSession(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	var patch any
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	s, ok := loadSession(token)
+	if !ok {
+		writeError(w, "Session not found or expired", "Not Found", http.StatusNotFound)
+		return
+	}
+
+	current, err := toJSONValue(s.req)
+	if err != nil {
+		writeError(w, "Could not read session", "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	merged, err := json.Marshal(mergePatch(current, patch))
+	if err != nil {
+		writeError(w, "Could not apply patch", "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	var req models.SplitRequest
+	if err := json.Unmarshal(merged, &req); err != nil {
+		writeError(w, "Invalid patch: "+err.Error(), "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	updated := &session{req: req, expires: time.Now().Add(SessionTTL)}
+	sessions.Store(token, updated)
+	writeSession(w, http.StatusOK, token, updated)
+}
+
+// HandleSessionSplit computes the split from the stored request.
+// POST /sessions/{token}/split
+func HandleSessionSplit(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	sessionMu.Lock()
+	s, ok := loadSession(token)
+	if ok {
+		s = &session{req: s.req, expires: time.Now().Add(SessionTTL)}
+		sessions.Store(token, s)
+	}
+	sessionMu.Unlock()
+	if !ok {
+		writeError(w, "Session not found or expired", "Not Found", http.StatusNotFound)
+		return
+	}
+	writeSplit(w, s.req)
+}
+
+// loadSession returns the live session for token, evicting it if it has expired.
+func loadSession(token string) (*session, bool) {
+	v, ok := sessions.Load(token)
+	if !ok {
+		return nil, false
+	}
+	s := v.(*session)
+	if time.Now().After(s.expires) {
+		sessions.Delete(token)
+		return nil, false
+	}
+	return s, true
+}
+
+// sweepSessions evicts every expired session.
+func sweepSessions() {
+	now := time.Now()
+	sessions.Range(func(k, v any) bool {
+		if now.After(v.(*session).expires) {
+			sessions.Delete(k)
+		}
+		return true
+	})
+}
+
+func writeSession(w http.ResponseWriter, statusCode int, token string, s *session) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(sessionResponse{
+		Token:     token,
+		ExpiresAt: s.expires.UTC().Format(time.RFC3339),
+		Request:   s.req,
+	})
+}
+
+// newToken returns a random RFC 4122 version 4 UUID.
+func newToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// toJSONValue round-trips v through JSON into generic maps and slices.
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+// mergePatch applies patch onto target following RFC 7396, except that arrays of objects
+// identified by goalId or ticker are merged by that id instead of being replaced.
+func mergePatch(target, patch any) any {
+	switch p := patch.(type) {
+	case map[string]any:
+		t, ok := target.(map[string]any)
+		if !ok {
+			t = make(map[string]any)
+		}
+		for k, v := range p {
+			if v == nil {
+				delete(t, k)
+				continue
+			}
+			t[k] = mergePatch(t[k], v)
+		}
+		return t
+	case []any:
+		t, ok := target.([]any)
+		if !ok {
+			return p
+		}
+		return mergeKeyedArray(t, p)
+	default:
+		return patch
+	}
+}
+
+// mergeKeyedArray merges patch elements into target by their goalId/ticker. Elements
+// without an id replace the whole array, as plain merge patch would. An element carrying
+// "_delete": true is removed from the result.
+func mergeKeyedArray(target, patch []any) any {
+	out := make([]any, len(target))
+	copy(out, target)
+	for _, pe := range patch {
+		key, id := elementID(pe)
+		if key == "" {
+			return patch
+		}
+		idx := -1
+		for i, te := range out {
+			if k, tid := elementID(te); k == key && tid == id {
+				idx = i
+				break
+			}
+		}
+		del, _ := pe.(map[string]any)["_delete"].(bool)
+		switch {
+		case del && idx >= 0:
+			out = append(out[:idx], out[idx+1:]...)
+		case del:
+		case idx >= 0:
+			out[idx] = mergePatch(out[idx], pe)
+		default:
+			fresh := mergePatch(nil, pe).(map[string]any)
+			delete(fresh, "_delete")
+			out = append(out, fresh)
+		}
+	}
+	return out
+}
+
+// elementID returns the identifying key ("goalId" or "ticker") and its value for an
+// array element, or "" if the element has none.
+func elementID(v any) (key, id string) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return "", ""
+	}
+	for _, k := range []string{"goalId", "ticker"} {
+		if s, ok := m[k].(string); ok && s != "" {
+			return k, s
+		}
+	}
+	return "", ""
+}
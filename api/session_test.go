@@ -0,0 +1,170 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func sessionMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sessions", HandleCreateSession)
+	mux.HandleFunc("PATCH /sessions/{token}", HandlePatchSession)
+	mux.HandleFunc("POST /sessions/{token}/split", HandleSessionSplit)
+	return mux
+}
+
+// createSession stores req and returns its token.
+func createSession(t *testing.T, mux http.Handler, req any) string {
+	t.Helper()
+	rec := do(t, mux, http.MethodPost, "/sessions", req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, body %s", rec.Code, rec.Body)
+	}
+	return decode[sessionResponse](t, rec).Token
+}
+
+func TestSessionCreateAndSplit(t *testing.T) {
+	mux := sessionMux()
+	token := createSession(t, mux, validRequest())
+
+	rec := do(t, mux, http.MethodPost, "/sessions/"+token+"/split", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("split: status %d, body %s", rec.Code, rec.Body)
+	}
+	resp := decode[models.SplitResponse](t, rec)
+	if len(resp.Results) != 1 || resp.Results[0].GoalID != "g1" {
+		t.Errorf("results = %+v, want goal g1", resp.Results)
+	}
+
+	if rec := do(t, mux, http.MethodPost, "/sessions/no-such-token/split", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("split of an unknown token: status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestSessionPatchMergesByID(t *testing.T) {
+	mux := sessionMux()
+	req := validRequest(
+		testhelpers.NewGoalBuilder("g1").WithTicker("A", "0.5", "10").WithTicker("B", "0.5", "20").Build(),
+		testhelpers.NewGoalBuilder("g2").WithTicker("A", "1", "10").Build(),
+	)
+	token := createSession(t, mux, req)
+
+	patch := `{
+		"volatilityBuffer": "0.05",
+		"goals": [
+			{"goalId": "g1", "orderAmount": "500", "modelPortfolioDetails": [{"ticker": "B", "_delete": true}]},
+			{"goalId": "g2", "_delete": true},
+			{"goalId": "g3", "orderAmount": "200", "orderType": "Investment", "modelPortfolioId": "mp-g3",
+			 "modelPortfolioDetails": [{"ticker": "C", "weight": "1", "marketPrice": "5"}]}
+		]
+	}`
+	rec := do(t, mux, http.MethodPatch, "/sessions/"+token, patch)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("patch: status %d, body %s", rec.Code, rec.Body)
+	}
+	got := decode[sessionResponse](t, rec).Request
+
+	if got.VolatilityBuffer != "0.05" {
+		t.Errorf("volatilityBuffer = %q, want 0.05", got.VolatilityBuffer)
+	}
+	if got.AmountDecimalPrecision != "2" {
+		t.Errorf("amountDecimalPrecision = %q, want the stored 2", got.AmountDecimalPrecision)
+	}
+	var ids []string
+	for _, g := range got.Goals {
+		ids = append(ids, g.GoalID)
+	}
+	if fmt.Sprint(ids) != "[g1 g3]" {
+		t.Fatalf("goals = %v, want [g1 g3]", ids)
+	}
+	g1 := got.Goals[0]
+	if g1.OrderAmount != "500" {
+		t.Errorf("g1 orderAmount = %q, want 500", g1.OrderAmount)
+	}
+	if len(g1.ModelPortfolioDetails) != 1 || g1.ModelPortfolioDetails[0].Ticker != "A" {
+		t.Errorf("g1 model = %+v, want A alone", g1.ModelPortfolioDetails)
+	}
+	if g1.ModelPortfolioDetails[0].Weight != "0.5" {
+		t.Errorf("g1 A weight = %q, want the stored 0.5", g1.ModelPortfolioDetails[0].Weight)
+	}
+
+	if rec := do(t, mux, http.MethodPatch, "/sessions/no-such-token", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("patch of an unknown token: status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestSessionExpires(t *testing.T) {
+	mux := sessionMux()
+	token := createSession(t, mux, validRequest())
+	v, _ := sessions.Load(token)
+	v.(*session).expires = time.Now().Add(-time.Second)
+
+	if rec := do(t, mux, http.MethodPost, "/sessions/"+token+"/split", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("split of an expired session: status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if _, ok := sessions.Load(token); ok {
+		t.Error("expired session still stored")
+	}
+}
+
+func TestSessionSplitRefreshesExpiry(t *testing.T) {
+	mux := sessionMux()
+	token := createSession(t, mux, validRequest())
+	v, _ := sessions.Load(token)
+	v.(*session).expires = time.Now().Add(time.Second)
+
+	do(t, mux, http.MethodPost, "/sessions/"+token+"/split", nil)
+	v, _ = sessions.Load(token)
+	if left := time.Until(v.(*session).expires); left < SessionTTL-time.Minute {
+		t.Errorf("session expires in %v after a split, want about %v", left, SessionTTL)
+	}
+}
+
+// TestSessionSplitKeepsConcurrentPatches checks that a split's TTL refresh never stores the
+// request it read over a PATCH that landed in between.
+func TestSessionSplitKeepsConcurrentPatches(t *testing.T) {
+	mux := sessionMux()
+	token := createSession(t, mux, validRequest())
+
+	const patches = 200
+	var patching, splitting sync.WaitGroup
+	done := make(chan struct{})
+	for range 4 {
+		splitting.Add(1)
+		go func() {
+			defer splitting.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					do(t, mux, http.MethodPost, "/sessions/"+token+"/split", nil)
+				}
+			}
+		}()
+	}
+	for i := range patches {
+		patching.Add(1)
+		go func() {
+			defer patching.Done()
+			patch := fmt.Sprintf(`{"goals": [{"goalId": "p%d", "orderAmount": "100"}]}`, i)
+			if rec := do(t, mux, http.MethodPatch, "/sessions/"+token, patch); rec.Code != http.StatusOK {
+				t.Errorf("patch %d: status %d, body %s", i, rec.Code, rec.Body)
+			}
+		}()
+	}
+	patching.Wait()
+	close(done)
+	splitting.Wait()
+
+	v, _ := sessions.Load(token)
+	if got := len(v.(*session).req.Goals); got != patches+1 {
+		t.Errorf("session has %d goals, want %d: a split overwrote a patch", got, patches+1)
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/splitter"
+)
+
+// HandleUnits answers how many units a buy of an amount of one product would receive,
+// without splitting an order. Validation failures are written like those of /split.
+// POST /units
+func HandleUnits(w http.ResponseWriter, r *http.Request) {
+	var req models.UnitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+	unitPrec, errs := validateUnitsRequest(req)
+	if len(errs) > 0 {
+		writeRequestError(w, validationFailed(errs))
+		return
+	}
+
+	amount, _ := decimal.NewFromString(strings.TrimSpace(req.Amount))
+	units := splitter.GetExpectedUnits(req.Ticker, amount, req.ModelItem, unitPrec)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.UnitsResponse{
+		Ticker: req.Ticker,
+		Amount: req.Amount,
+		Units:  units.StringFixed(int32(unitPrec)),
+	})
+}
+
+// validateUnitsRequest checks the fields of req that GetExpectedUnits uses and returns its
+// unit precision.
+func validateUnitsRequest(req models.UnitsRequest) (unitPrec int, errs []models.ValidationError) {
+	if strings.TrimSpace(req.Ticker) == "" {
+		addError(&errs, "", invalid(models.CodeRequired, "ticker", "ticker must not be empty"))
+	}
+	unitPrec, err := parseNonNegInt(req.UnitDecimalPrecision, "unitDecimalPrecision")
+	addError(&errs, "", err)
+	addError(&errs, "", validateAmountField(req.Amount, "amount", true, unlimitedPrec))
+	addError(&errs, "", validatePriceField(req.MarketPrice, "marketPrice"))
+	addError(&errs, "", validateOptionalRateField(req.TransactionFee, "transactionFee"))
+	if strings.TrimSpace(req.ExchangeRate) != "" {
+		addError(&errs, "", validatePriceField(req.ExchangeRate, "exchangeRate"))
+	}
+	return unitPrec, errs
+}
@@ -1,12 +1,18 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/shopspring/decimal"
 	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/splitter"
 )
 
 var (
@@ -14,89 +20,375 @@ var (
 	decOne  = decimal.NewFromInt(1)
 )
 
-// validateRequest validates all fields in the incoming request.
-// On success it returns the parsed amountDecimalPrecision and unitDecimalPrecision.
-func validateRequest(req *models.SplitRequest) (amountPrec, unitPrec int, err error) {
-	amountPrec, err = parseNonNegInt(req.AmountDecimalPrecision, "amountDecimalPrecision")
+// validateRequest validates all fields in the incoming request and returns every problem
+// found rather than stopping at the first. It also returns the parsed
+// amountDecimalPrecision and unitDecimalPrecision; when either is invalid, the decimal-place
+// checks that depend on it are skipped. Without checkOrder, each goal's order, by
+// orderAmount, orderUnit or redemptionPercent, is not checked, as for /drift.
+func validateRequest(req *models.SplitRequest, checkOrder bool) (amountPrec, unitPrec int, errs []models.ValidationError) {
+	amountPrec, err := parseNonNegInt(req.AmountDecimalPrecision, "amountDecimalPrecision")
 	if err != nil {
-		return
+		addError(&errs, "", err)
+		amountPrec = unlimitedPrec
 	}
 	unitPrec, err = parseNonNegInt(req.UnitDecimalPrecision, "unitDecimalPrecision")
 	if err != nil {
-		return
+		addError(&errs, "", err)
+		unitPrec = unlimitedPrec
 	}
 	if req.VolatilityBuffer != "" {
-		if err = validateRateField(req.VolatilityBuffer, "volatilityBuffer"); err != nil {
-			return
+		addError(&errs, "", validateRateField(req.VolatilityBuffer, "volatilityBuffer"))
+	}
+	addError(&errs, "", validateOptionalAmountField(req.MinTradeAmt, "minTradeAmt", amountPrec))
+	addError(&errs, "", validateOptionalAmountField(req.ConsolidationThreshold, "consolidationThreshold", amountPrec))
+	switch strings.ToLower(strings.TrimSpace(req.ResidualPolicy)) {
+	case "", splitter.ResidualLargestRemainder, splitter.ResidualRoundRobin, splitter.ResidualHighestWeight:
+	default:
+		addError(&errs, "", invalid(models.CodeInvalidOption, "residualPolicy", "residualPolicy: must be one of %q, %q or %q",
+			splitter.ResidualLargestRemainder, splitter.ResidualRoundRobin, splitter.ResidualHighestWeight))
+	}
+	switch strings.ToLower(strings.TrimSpace(req.RepairObjective)) {
+	case "", splitter.RepairMostFixed, splitter.RepairMaxInvested, splitter.RepairMinDrift:
+	default:
+		addError(&errs, "", invalid(models.CodeInvalidOption, "repairObjective", "repairObjective: must be one of %q, %q or %q",
+			splitter.RepairMostFixed, splitter.RepairMaxInvested, splitter.RepairMinDrift))
+	}
+	if !splitter.ValidRepairStrategy(req.RepairStrategy) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "repairStrategy", "repairStrategy: must be %q or %q",
+			splitter.RepairStrategyGreedy, splitter.RepairStrategyProportional))
+	}
+	if !splitter.ValidMinHoldingPolicy(req.MinHoldingPolicy) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "minHoldingPolicy", "minHoldingPolicy: must be one of %q, %q or %q",
+			splitter.MinHoldingFlag, splitter.MinHoldingSellAll, splitter.MinHoldingReduce))
+	} else if p := splitter.MinHoldingPolicy(*req); req.AutoFullRedemption && p != splitter.MinHoldingSellAll {
+		addError(&errs, "", invalid(models.CodeConflictingFields, "autoFullRedemption", "autoFullRedemption: cannot be combined with minHoldingPolicy %q", p))
+	}
+	if !splitter.ValidProceedsMode(req.ProceedsMode) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "proceedsMode", "proceedsMode: must be one of %q, %q or %q",
+			splitter.ProceedsGross, splitter.ProceedsNet, splitter.ProceedsNetAfterTax))
+	} else if mode := strings.ToLower(strings.TrimSpace(req.ProceedsMode)); mode != "" && mode != splitter.ProceedsGross && req.RebuildBigRedemption {
+		addError(&errs, "", invalid(models.CodeConflictingFields, "proceedsMode", "proceedsMode: %q cannot be combined with rebuildBigRedemption", mode))
+	}
+	switch strings.ToLower(strings.TrimSpace(req.RequestVersion)) {
+	case "", APIVersionV1, APIVersionV2:
+	default:
+		addError(&errs, "", invalid(models.CodeInvalidOption, "requestVersion", "requestVersion: must be %q or %q", APIVersionV1, APIVersionV2))
+	}
+	if !splitter.ValidMissingPricePolicy(req.MissingPricePolicy) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "missingPricePolicy", "missingPricePolicy: must be %q or %q", splitter.MissingPriceExclude, splitter.MissingPriceManual))
+	}
+	if req.AllowMissingPrice && req.RebuildBigRedemption {
+		addError(&errs, "", invalid(models.CodeConflictingFields, "allowMissingPrice, rebuildBigRedemption", "allowMissingPrice, rebuildBigRedemption: cannot be combined, since the BUY leg needs a price for every product"))
+	}
+	if !splitter.ValidMinimumBasis(req.RedemptionMinimumBasis) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "redemptionMinimumBasis", "redemptionMinimumBasis: must be %q or %q", splitter.MinimumBasisGross, splitter.MinimumBasisNet))
+	}
+	addError(&errs, "", validateOptionalRateField(req.ZeroOutMaxWeight, "zeroOutMaxWeight"))
+	addError(&errs, "", validateOptionalRateField(req.MinWeightDeviationTrigger, "minWeightDeviationTrigger"))
+	if !splitter.ValidPhase1Order(req.Phase1Order) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "phase1Order", "phase1Order: must be one of %q, %q or %q",
+			splitter.Phase1SmallestFirst, splitter.Phase1LargestFirst, splitter.Phase1ModelOrder))
+	}
+	if !splitter.ValidLotSelection(req.LotSelection) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "lotSelection", "lotSelection: must be one of %q, %q, %q or %q",
+			splitter.LotFIFO, splitter.LotLIFO, splitter.LotHIFO, splitter.LotMinGain))
+	}
+	if !splitter.ValidTieBreak(req.TieBreak) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "tieBreak", "tieBreak: must be one of %q, %q or %q",
+			splitter.TieBreakModelOrder, splitter.TieBreakTicker, splitter.TieBreakLargestWeight))
+	}
+	if !splitter.ValidStrategy(req.AllocationStrategy) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "allocationStrategy", "allocationStrategy: must be one of %q, %q or %q",
+			splitter.StrategyShortfall, splitter.StrategyProRata, splitter.StrategyEqual))
+	} else if s := strings.ToLower(strings.TrimSpace(req.AllocationStrategy)); req.IgnoreCurrentHoldings && s != "" && s != splitter.StrategyProRata {
+		addError(&errs, "", invalid(models.CodeConflictingFields, "allocationStrategy, ignoreCurrentHoldings", "allocationStrategy, ignoreCurrentHoldings: %q cannot be combined; ignoreCurrentHoldings always splits pro-rata", s))
+	}
+	if !splitter.ValidAllocationMode(req.AllocationMode) {
+		addError(&errs, "", invalid(models.CodeInvalidOption, "allocationMode", "allocationMode: must be %q or %q",
+			splitter.StrategyShortfall, splitter.StrategyProRata))
+	} else if m := strings.ToLower(strings.TrimSpace(req.AllocationMode)); req.IgnoreCurrentHoldings && m == splitter.StrategyShortfall {
+		addError(&errs, "", invalid(models.CodeConflictingFields, "allocationMode, ignoreCurrentHoldings", "allocationMode, ignoreCurrentHoldings: %q cannot be combined; ignoreCurrentHoldings always splits pro-rata", m))
+	}
+	addError(&errs, "", validateClientReference(req.ClientReference, "clientReference"))
+	if len(req.WhatIfAmounts) > MaxWhatIfAmounts {
+		addError(&errs, "", invalid(models.CodeTooManyItems, "whatIfAmounts", "whatIfAmounts: at most %d amounts are allowed, got %d", MaxWhatIfAmounts, len(req.WhatIfAmounts)))
+	}
+	for i, s := range req.WhatIfAmounts {
+		addError(&errs, "", validateAmountField(s, fmt.Sprintf("whatIfAmounts[%d]", i), true, amountPrec))
+	}
+	codes := make([]string, 0, len(req.SeverityOverrides))
+	for code := range req.SeverityOverrides {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.TrimSpace(code) == "" {
+			addError(&errs, "", invalid(models.CodeRequired, "severityOverrides", "severityOverrides: codes must not be empty"))
+		} else if !splitter.ValidSeverity(req.SeverityOverrides[code]) {
+			addError(&errs, "", invalid(models.CodeInvalidOption, "severityOverrides ("+code+")", "severityOverrides (%s): must be %q or %q",
+				code, splitter.SeverityError, splitter.SeverityWarning))
 		}
 	}
 	if len(req.Goals) == 0 {
-		err = fmt.Errorf("goals must not be empty")
-		return
+		addError(&errs, "", invalid(models.CodeRequired, "goals", "goals must not be empty"))
+	}
+	validatePage(*req, &errs)
+	if strings.TrimSpace(req.MaxPriceAgeDays) != "" {
+		_, err := parseNonNegInt(req.MaxPriceAgeDays, "maxPriceAgeDays")
+		addError(&errs, "", err)
+	}
+	// Goals are only checked against a valid request currency.
+	currency := ""
+	if err := validateCurrency(req.Currency, "currency"); err != nil {
+		addError(&errs, "", err)
+	} else {
+		currency = normalizeCurrency(req.Currency)
+	}
+	if strings.TrimSpace(req.WeightDecimalPrecision) != "" {
+		_, err := parseNonNegInt(req.WeightDecimalPrecision, "weightDecimalPrecision")
+		addError(&errs, "", err)
+	}
+	// Goals are checked on their weights as they will be split: rounded to a valid
+	// weightPrecision.
+	weightPrec := -1
+	if strings.TrimSpace(req.WeightPrecision) != "" {
+		p, err := parseNonNegInt(req.WeightPrecision, "weightPrecision")
+		addError(&errs, "", err)
+		if err == nil {
+			weightPrec = p
+		}
 	}
-	for _, goal := range req.Goals {
-		if err = validateGoal(goal, amountPrec, unitPrec); err != nil {
-			return
+	tolErr := validateFullRedemptionTolerance(req.FullRedemptionTolerance)
+	addError(&errs, "", tolErr)
+	shrinkErr := validateOptionalShareField(req.ShrinkageCoefficient, "shrinkageCoefficient")
+	addError(&errs, "", shrinkErr)
+	for i, goal := range req.Goals {
+		path := fmt.Sprintf("goals[%d]", i)
+		if weightPrec >= 0 {
+			goal = splitter.RoundWeights(goal, weightPrec)
+		}
+		validateGoal(goal, amountPrec, unitPrec, req.AllowMissingPrice, checkOrder, currency, path, &errs)
+		for j, h := range goal.GoalDetails {
+			validateLots(h, unitPrec, req.ScaleLotUnits, fmt.Sprintf("%s.goalDetails[%d]", path, j), &errs)
+		}
+		if shrinkErr == nil && strings.TrimSpace(req.ShrinkageCoefficient) != "" && !req.NormalizeWeights {
+			addError(&errs, path, validateBlendedWeights(goal, req.ShrinkageCoefficient))
+		}
+		if s := strings.ToLower(strings.TrimSpace(goal.AllocationStrategy)); req.IgnoreCurrentHoldings && splitter.ValidStrategy(s) && s != "" && s != splitter.StrategyProRata {
+			addError(&errs, path, invalid(models.CodeConflictingFields, "allocationStrategy, ignoreCurrentHoldings", "allocationStrategy, ignoreCurrentHoldings (%s): %q cannot be combined; ignoreCurrentHoldings always splits pro-rata", goal.GoalID, s))
+		}
+		if req.RebuildBigRedemption && strings.TrimSpace(goal.CashBalance) != "" {
+			addError(&errs, path, invalid(models.CodeConflictingFields, "cashBalance, rebuildBigRedemption", "cashBalance, rebuildBigRedemption (%s): cannot be combined", goal.GoalID))
+		}
+		if mode := strings.ToLower(strings.TrimSpace(req.ProceedsMode)); mode != "" && mode != splitter.ProceedsGross {
+			if splitter.HasRedemptionPercents(goal) {
+				addError(&errs, path, invalid(models.CodeConflictingFields, "proceedsMode, redemptionPercent", "proceedsMode, redemptionPercent (%s): a redemption by percentage sells by gross value only", goal.GoalID))
+			}
+			if goal.CashOut {
+				addError(&errs, path, invalid(models.CodeConflictingFields, "cashOut, proceedsMode", "cashOut, proceedsMode (%s): a cash-out sells by gross value only", goal.GoalID))
+			}
+		}
+		if tolErr == nil && strings.EqualFold(strings.TrimSpace(goal.OrderType), "redemption") {
+			addError(&errs, path, validateToleranceBuffer(goal, *req))
 		}
 	}
 	return
 }
 
-func validateGoal(g models.Goal, amtP, unitP int) error {
-	if strings.TrimSpace(g.GoalID) == "" {
-		return fmt.Errorf("goalId must not be empty")
-	}
-	if strings.TrimSpace(g.ModelPortfolioID) == "" {
-		return fmt.Errorf("modelPortfolioId must not be empty")
-	}
-	if strings.TrimSpace(g.OrderType) == "" {
-		return fmt.Errorf("orderType must not be empty")
-	}
-	if err := validateAmountField(g.OrderAmount, "orderAmount", true, amtP); err != nil {
-		return err
+// MaxWhatIfAmounts caps whatIfAmounts, since every amount re-runs each investment goal.
+const MaxWhatIfAmounts = 20
+
+// MaxClientReferenceLength caps clientReference, in characters.
+const MaxClientReferenceLength = 256
+
+// unlimitedPrec stands in for a precision that failed to parse, so that no decimal-place
+// error is reported against it.
+const unlimitedPrec = math.MaxInt32
+
+// addError appends err to errs at path, with its code and field when it is a
+// *models.ValidationError; a nil err is ignored.
+func addError(errs *[]models.ValidationError, path string, err error) {
+	if err == nil {
+		return
 	}
-	if strings.ToLower(g.OrderType) == "redemption" && len(g.GoalDetails) == 0 {
-		return fmt.Errorf("goalDetails must not be empty for redemption orders")
+	ve := models.ValidationError{Message: err.Error()}
+	if v := (*models.ValidationError)(nil); errors.As(err, &v) {
+		ve = *v
 	}
-	for _, h := range g.GoalDetails {
-		if err := validateHolding(h, amtP, unitP); err != nil {
-			return err
+	ve.Path = path
+	*errs = append(*errs, ve)
+}
+
+// validateGoal validates g, and its order when checkOrder is set; currency is the request's
+// currency, upper-cased, or empty.
+func validateGoal(g models.Goal, amtP, unitP int, allowMissingPrice, checkOrder bool, currency, path string, errs *[]models.ValidationError) {
+	if strings.TrimSpace(g.GoalID) == "" {
+		addError(errs, path, invalid(models.CodeRequired, "goalId", "goalId must not be empty"))
+	}
+	if strings.TrimSpace(g.ModelPortfolioID) == "" && !g.CashOut {
+		addError(errs, path, invalid(models.CodeRequired, "modelPortfolioId", "modelPortfolioId must not be empty"))
+	}
+	switch strings.ToLower(strings.TrimSpace(g.OrderType)) {
+	case "":
+		addError(errs, path, invalid(models.CodeRequired, "orderType", "orderType must not be empty"))
+	case "investment", "redemption":
+	default:
+		addError(errs, path, invalid(models.CodeInvalidOption, "orderType", "Unsupported order type: %s", g.OrderType))
+	}
+	addError(errs, path, validateClientReference(g.ClientReference, "clientReference ("+g.GoalID+")"))
+	if err := validateCurrency(g.Currency, "currency ("+g.GoalID+")"); err != nil {
+		addError(errs, path, err)
+	} else if c := normalizeCurrency(g.Currency); c != "" && currency != "" && c != currency {
+		addError(errs, path, invalid(models.CodeCurrencyMismatch, "currency", "currency (%s): %s does not match the request currency %s", g.GoalID, c, currency))
+	}
+	unitOrder := strings.TrimSpace(g.OrderUnit) != ""
+	percents := splitter.HasRedemptionPercents(g)
+	var orderAmountErr error
+	switch {
+	case !checkOrder:
+	case g.CashOut && strings.ToLower(g.OrderType) != "redemption":
+		orderAmountErr = invalid(models.CodeUnsupportedOrderType, "cashOut", "cashOut: only supported for redemption orders")
+	case g.CashOut && unitOrder:
+		orderAmountErr = invalid(models.CodeConflictingFields, "cashOut, orderUnit", "cashOut, orderUnit (%s): a cash-out is capped by orderAmount only", g.GoalID)
+	case g.CashOut && percents:
+		orderAmountErr = invalid(models.CodeConflictingFields, "cashOut, redemptionPercent", "cashOut, redemptionPercent (%s): a cash-out sells every holding whole", g.GoalID)
+	case g.CashOut && strings.TrimSpace(g.OrderAmount) == "":
+	case percents && (unitOrder || strings.TrimSpace(g.OrderAmount) != ""):
+		orderAmountErr = invalid(models.CodeConflictingFields, "orderAmount, redemptionPercent", "orderAmount, redemptionPercent (%s): an order is given either by orderAmount or orderUnit, or by the holdings' redemptionPercent, not both", g.GoalID)
+	case percents && strings.ToLower(g.OrderType) != "redemption":
+		orderAmountErr = invalid(models.CodeUnsupportedOrderType, "redemptionPercent", "redemptionPercent: only supported for redemption orders")
+	case percents:
+	case unitOrder && strings.TrimSpace(g.OrderAmount) != "":
+		orderAmountErr = invalid(models.CodeConflictingFields, "orderAmount, orderUnit", "orderAmount, orderUnit: exactly one of them must be set")
+	case unitOrder && strings.ToLower(g.OrderType) != "redemption":
+		orderAmountErr = invalid(models.CodeUnsupportedOrderType, "orderUnit", "orderUnit: only supported for redemption orders")
+	case unitOrder:
+		orderAmountErr = validateAmountField(g.OrderUnit, "orderUnit", true, unitP)
+	default:
+		orderAmountErr = validateAmountField(g.OrderAmount, "orderAmount", true, amtP)
+	}
+	addError(errs, path, orderAmountErr)
+	addError(errs, path, validateOptionalAmountField(g.MinTradeAmt, "minTradeAmt ("+g.GoalID+")", amtP))
+	if !splitter.ValidStrategy(g.AllocationStrategy) {
+		addError(errs, path, invalid(models.CodeInvalidOption, "allocationStrategy", "allocationStrategy (%s): must be one of %q, %q or %q",
+			g.GoalID, splitter.StrategyShortfall, splitter.StrategyProRata, splitter.StrategyEqual))
+	}
+	capErr := validateOptionalShareField(g.MaxRedemptionPct, "maxRedemptionPct ("+g.GoalID+")")
+	addError(errs, path, capErr)
+	addError(errs, path, validateOptionalRateField(g.VolatilityBuffer, "volatilityBuffer ("+g.GoalID+")"))
+	cashErr := validateCashBalance(g, amtP)
+	addError(errs, path, cashErr)
+	validateGlidePath(g, path, errs)
+	if strings.TrimSpace(g.MaxTrades) != "" {
+		const msg = "maxTrades: must be a positive integer (at least one trade is needed to place the order)"
+		if n, err := strconv.Atoi(strings.TrimSpace(g.MaxTrades)); err != nil {
+			addError(errs, path, invalid(models.CodeInvalidInteger, "maxTrades", msg))
+		} else if n < 1 {
+			addError(errs, path, invalid(models.CodeMustBePositive, "maxTrades", msg))
 		}
 	}
-	if strings.ToLower(g.OrderType) == "redemption" {
-		goalValue := decZero
+	if strings.ToLower(g.OrderType) == "redemption" && len(g.GoalDetails) == 0 {
+		addError(errs, path, invalid(models.CodeRequired, "goalDetails", "goalDetails must not be empty for redemption orders"))
+	}
+	// allowMissingPrice only applies to redemptions; see splitter.MissingPrice.
+	noPriceOK := allowMissingPrice && strings.EqualFold(strings.TrimSpace(g.OrderType), "redemption")
+	holdingsValid := true
+	for i, h := range g.GoalDetails {
+		before := len(*errs)
+		validateHolding(h, amtP, unitP, noPriceOK, fmt.Sprintf("%s.goalDetails[%d]", path, i), errs)
+		holdingsValid = holdingsValid && len(*errs) == before
+	}
+	// The goal-value check is only meaningful once the amounts it sums are valid.
+	if checkOrder && strings.ToLower(g.OrderType) == "redemption" && len(g.GoalDetails) > 0 && holdingsValid && orderAmountErr == nil {
+		goalValue, goalUnits := decZero, decZero
 		for _, h := range g.GoalDetails {
 			v, _ := decimal.NewFromString(h.Value)
-			goalValue = goalValue.Add(v)
+			u, _ := decimal.NewFromString(h.Units)
+			goalValue, goalUnits = goalValue.Add(v), goalUnits.Add(u)
 		}
-		orderAmount, _ := decimal.NewFromString(g.OrderAmount)
-		if orderAmount.GreaterThan(goalValue) {
-			return fmt.Errorf("orderAmount (%s) cannot be greater than the total goal value (%s)", g.OrderAmount, goalValue.String())
+		if unitOrder {
+			orderUnits, _ := decimal.NewFromString(strings.TrimSpace(g.OrderUnit))
+			if orderUnits.GreaterThan(goalUnits) {
+				addError(errs, path, invalid(models.CodeExceedsHoldings, "orderUnit", "orderUnit (%s) cannot be greater than the total units held (%s)", g.OrderUnit, goalUnits.String()))
+			}
+		} else {
+			orderAmount, _ := decimal.NewFromString(g.OrderAmount)
+			if cash, _ := decimal.NewFromString(strings.TrimSpace(g.CashBalance)); cashErr == nil {
+				goalValue = goalValue.Add(cash)
+			}
+			if orderAmount.GreaterThan(goalValue) {
+				addError(errs, path, invalid(models.CodeExceedsHoldings, "orderAmount", "orderAmount (%s) cannot be greater than the total goal value (%s)", g.OrderAmount, goalValue.String()))
+			}
+			addError(errs, path, validateRedemptionCap(g, capErr))
 		}
 	}
-	if len(g.ModelPortfolioDetails) == 0 {
-		return fmt.Errorf("modelPortfolioDetails must not be empty")
+	if len(g.ModelPortfolioDetails) == 0 && !g.CashOut {
+		addError(errs, path, invalid(models.CodeRequired, "modelPortfolioDetails", "modelPortfolioDetails must not be empty"))
 	}
-	for _, mp := range g.ModelPortfolioDetails {
-		if err := validateModelItem(mp, amtP, unitP); err != nil {
-			return err
-		}
+	floorsValid := true
+	totalFloor := decZero
+	for i, mp := range g.ModelPortfolioDetails {
+		validateModelItem(mp, amtP, unitP, noPriceOK, fmt.Sprintf("%s.modelPortfolioDetails[%d]", path, i), errs)
+		// Invalid floors are reported by validateModelItem; the sum is only checked without them.
+		floorsValid = floorsValid && validateOptionalRateField(mp.AllocationFloor, "allocationFloor") == nil
+		floor, _ := decimal.NewFromString(strings.TrimSpace(mp.AllocationFloor))
+		totalFloor = totalFloor.Add(floor)
+	}
+	if floorsValid && totalFloor.GreaterThan(decOne) {
+		addError(errs, path, invalid(models.CodeFloorSumExceedsOne, "allocationFloor", "allocationFloor (%s): must sum to at most 1, got %s", g.GoalID, totalFloor.String()))
 	}
-	return nil
 }
 
-func validateHolding(h models.Holding, amtP, unitP int) error {
+func validateHolding(h models.Holding, amtP, unitP int, noPriceOK bool, path string, errs *[]models.ValidationError) {
 	if strings.TrimSpace(h.Ticker) == "" {
-		return fmt.Errorf("goalDetails: ticker must not be empty")
+		addError(errs, path, invalid(models.CodeRequired, "ticker", "goalDetails: ticker must not be empty"))
+	}
+	unitsErr := validateAmountField(h.Units, "units ("+h.Ticker+")", false, unitP)
+	addError(errs, path, unitsErr)
+	if !noPriceOK || !splitter.MissingPrice(h.MarketPrice) {
+		addError(errs, path, validatePriceField(h.MarketPrice, "marketPrice ("+h.Ticker+")"))
+	}
+	valueErr := validateAmountField(h.Value, "value ("+h.Ticker+")", false, amtP)
+	addError(errs, path, valueErr)
+	pendingValueErr := validateOptionalSignedAmountField(h.PendingValue, "pendingValue ("+h.Ticker+")", amtP)
+	addError(errs, path, pendingValueErr)
+	pendingUnitsErr := validateOptionalSignedAmountField(h.PendingUnits, "pendingUnits ("+h.Ticker+")", unitP)
+	addError(errs, path, pendingUnitsErr)
+	if valueErr == nil && pendingValueErr == nil {
+		value, _ := decimal.NewFromString(strings.TrimSpace(h.Value))
+		pendingValue, _ := decimal.NewFromString(strings.TrimSpace(h.PendingValue))
+		if value.Add(pendingValue).IsNegative() {
+			addError(errs, path, invalid(models.CodePendingExceedsHeld, "pendingValue", "pendingValue (%s): pending sells cannot exceed the current value", h.Ticker))
+		}
+	}
+	if unitsErr == nil && pendingUnitsErr == nil {
+		units, _ := decimal.NewFromString(strings.TrimSpace(h.Units))
+		pendingUnits, _ := decimal.NewFromString(strings.TrimSpace(h.PendingUnits))
+		if units.Add(pendingUnits).IsNegative() {
+			addError(errs, path, invalid(models.CodePendingExceedsHeld, "pendingUnits", "pendingUnits (%s): pending sells cannot exceed the current units", h.Ticker))
+		}
 	}
-	if err := validateAmountField(h.Units, "units ("+h.Ticker+")", false, unitP); err != nil {
-		return err
+	if s := strings.TrimSpace(h.RedemptionPercent); s != "" {
+		const msg = "redemptionPercent (%s): must be a number > 0 and <= 1"
+		if p, err := decimal.NewFromString(s); err != nil {
+			addError(errs, path, invalid(models.CodeInvalidDecimal, "redemptionPercent", msg, h.Ticker))
+		} else if !p.IsPositive() || p.GreaterThan(decOne) {
+			addError(errs, path, invalid(models.CodePercentOutOfRange, "redemptionPercent", msg, h.Ticker))
+		}
 	}
-	if err := validatePriceField(h.MarketPrice, "marketPrice ("+h.Ticker+")"); err != nil {
-		return err
+	if strings.TrimSpace(h.HoldingSince) != "" {
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(h.HoldingSince)); err != nil {
+			addError(errs, path, invalid(models.CodeInvalidDate, "holdingSince", "holdingSince (%s): must be a date in YYYY-MM-DD format", h.Ticker))
+		}
 	}
-	if err := validateAmountField(h.Value, "value ("+h.Ticker+")", false, amtP); err != nil {
-		return err
+	blockedErr := validateOptionalAmountField(h.BlockedUnits, "blockedUnits ("+h.Ticker+")", unitP)
+	addError(errs, path, blockedErr)
+	if unitsErr == nil && blockedErr == nil {
+		units, _ := decimal.NewFromString(strings.TrimSpace(h.Units))
+		blocked, _ := decimal.NewFromString(strings.TrimSpace(h.BlockedUnits))
+		if blocked.GreaterThan(units) {
+			addError(errs, path, invalid(models.CodeBlockedExceedsHeld, "blockedUnits", "blockedUnits (%s): cannot exceed units", h.Ticker))
+		}
 	}
 	for _, f := range []struct{ v, name string }{
 		{h.MinInitialInvestmentAmt, "minInitialInvestmentAmt (" + h.Ticker + ")"},
@@ -104,9 +396,7 @@ func validateHolding(h models.Holding, amtP, unitP int) error {
 		{h.MinRedemptionAmt, "minRedemptionAmt (" + h.Ticker + ")"},
 		{h.MinHoldingAmt, "minHoldingAmt (" + h.Ticker + ")"},
 	} {
-		if err := validateOptionalAmountField(f.v, f.name, amtP); err != nil {
-			return err
-		}
+		addError(errs, path, validateOptionalAmountField(f.v, f.name, amtP))
 	}
 	for _, f := range []struct{ v, name string }{
 		{h.MinInitialInvestmentUnits, "minInitialInvestmentUnits (" + h.Ticker + ")"},
@@ -114,23 +404,64 @@ func validateHolding(h models.Holding, amtP, unitP int) error {
 		{h.MinRedemptionUnits, "minRedemptionUnits (" + h.Ticker + ")"},
 		{h.MinHoldingUnits, "minHoldingUnits (" + h.Ticker + ")"},
 	} {
-		if err := validateOptionalAmountField(f.v, f.name, unitP); err != nil {
-			return err
+		addError(errs, path, validateOptionalAmountField(f.v, f.name, unitP))
+	}
+	addError(errs, path, validateOptionalRateField(h.TransactionFee, "transactionFee ("+h.Ticker+")"))
+	addError(errs, path, validateOptionalRateField(h.RedemptionFee, "redemptionFee ("+h.Ticker+")"))
+	addError(errs, path, validateOptionalRateField(h.WithholdingTaxRate, "withholdingTaxRate ("+h.Ticker+")"))
+	if strings.TrimSpace(h.AverageCost) != "" && strings.TrimSpace(h.TotalCost) != "" {
+		addError(errs, path, invalid(models.CodeConflictingFields, "averageCost, totalCost", "averageCost, totalCost (%s): at most one of them may be set", h.Ticker))
+	}
+	if len(h.Lots) > 0 && (strings.TrimSpace(h.AverageCost) != "" || strings.TrimSpace(h.TotalCost) != "") {
+		addError(errs, path, invalid(models.CodeConflictingFields, "lots", "lots (%s): cannot be combined with averageCost or totalCost", h.Ticker))
+	}
+	addError(errs, path, validateOptionalAmountField(h.AverageCost, "averageCost ("+h.Ticker+")", unlimitedPrec))
+	addError(errs, path, validateOptionalAmountField(h.TotalCost, "totalCost ("+h.Ticker+")", amtP))
+}
+
+// validateLots checks the lots of h. Unless scale is set, which scales them in the splitter,
+// their units must add up to the holding's units within one smallest unit per lot.
+func validateLots(h models.Holding, unitP int, scale bool, path string, errs *[]models.ValidationError) {
+	total := decimal.Zero
+	valid := true
+	for k, l := range h.Lots {
+		name := fmt.Sprintf("lots[%d] (%s)", k, h.Ticker)
+		unitsErr := validateAmountField(l.Units, name+".units", false, unitP)
+		addError(errs, path, unitsErr)
+		addError(errs, path, validateAmountField(l.CostPerUnit, name+".costPerUnit", false, unlimitedPrec))
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(l.AcquisitionDate)); err != nil {
+			addError(errs, path, invalid(models.CodeInvalidDate, name+".acquisitionDate", "%s.acquisitionDate: must be a date in YYYY-MM-DD format", name))
 		}
+		units, _ := decimal.NewFromString(strings.TrimSpace(l.Units))
+		total = total.Add(units)
+		valid = valid && unitsErr == nil
+	}
+	units, err := decimal.NewFromString(strings.TrimSpace(h.Units))
+	if len(h.Lots) == 0 || scale || !valid || err != nil || unitP == unlimitedPrec {
+		return
+	}
+	tolerance := decimal.New(int64(len(h.Lots)), -int32(unitP))
+	if total.Sub(units).Abs().GreaterThan(tolerance) {
+		addError(errs, path, invalid(models.CodeLotUnitsMismatch, "lots", "lots (%s): units add up to %s, not the holding's %s; set scaleLotUnits to scale them",
+			h.Ticker, total.String(), units.String()))
 	}
-	return validateOptionalRateField(h.TransactionFee, "transactionFee ("+h.Ticker+")")
 }
 
-func validateModelItem(mp models.ModelItem, amtP, unitP int) error {
+func validateModelItem(mp models.ModelItem, amtP, unitP int, noPriceOK bool, path string, errs *[]models.ValidationError) {
 	if strings.TrimSpace(mp.Ticker) == "" {
-		return fmt.Errorf("modelPortfolioDetails: ticker must not be empty")
+		addError(errs, path, invalid(models.CodeRequired, "ticker", "modelPortfolioDetails: ticker must not be empty"))
 	}
 	w, err := decimal.NewFromString(mp.Weight)
-	if err != nil || w.LessThan(decZero) || w.GreaterThan(decOne) {
-		return fmt.Errorf("weight (%s): must be a number between 0 and 1", mp.Ticker)
+	if err != nil {
+		addError(errs, path, invalid(models.CodeInvalidDecimal, "weight", "weight (%s): must be a number between 0 and 1", mp.Ticker))
+	} else if w.LessThan(decZero) || w.GreaterThan(decOne) {
+		addError(errs, path, invalid(models.CodeWeightOutOfRange, "weight", "weight (%s): must be a number between 0 and 1", mp.Ticker))
+	}
+	if !noPriceOK || !splitter.MissingPrice(mp.MarketPrice) {
+		addError(errs, path, validatePriceField(mp.MarketPrice, "marketPrice ("+mp.Ticker+")"))
 	}
-	if err := validatePriceField(mp.MarketPrice, "marketPrice ("+mp.Ticker+")"); err != nil {
-		return err
+	if strings.TrimSpace(mp.ExchangeRate) != "" {
+		addError(errs, path, validatePriceField(mp.ExchangeRate, "exchangeRate ("+mp.Ticker+")"))
 	}
 	for _, f := range []struct{ v, name string }{
 		{mp.MinInitialInvestmentAmt, "minInitialInvestmentAmt (" + mp.Ticker + ")"},
@@ -138,9 +469,7 @@ func validateModelItem(mp models.ModelItem, amtP, unitP int) error {
 		{mp.MinRedemptionAmt, "minRedemptionAmt (" + mp.Ticker + ")"},
 		{mp.MinHoldingAmt, "minHoldingAmt (" + mp.Ticker + ")"},
 	} {
-		if err := validateOptionalAmountField(f.v, f.name, amtP); err != nil {
-			return err
-		}
+		addError(errs, path, validateOptionalAmountField(f.v, f.name, amtP))
 	}
 	for _, f := range []struct{ v, name string }{
 		{mp.MinInitialInvestmentUnits, "minInitialInvestmentUnits (" + mp.Ticker + ")"},
@@ -148,11 +477,180 @@ func validateModelItem(mp models.ModelItem, amtP, unitP int) error {
 		{mp.MinRedemptionUnits, "minRedemptionUnits (" + mp.Ticker + ")"},
 		{mp.MinHoldingUnits, "minHoldingUnits (" + mp.Ticker + ")"},
 	} {
-		if err := validateOptionalAmountField(f.v, f.name, unitP); err != nil {
-			return err
+		addError(errs, path, validateOptionalAmountField(f.v, f.name, unitP))
+	}
+	if strings.TrimSpace(mp.Priority) != "" {
+		_, err := parseNonNegInt(mp.Priority, "priority ("+mp.Ticker+")")
+		addError(errs, path, err)
+	}
+	addError(errs, path, validateOptionalRateField(mp.TransactionFee, "transactionFee ("+mp.Ticker+")"))
+	addError(errs, path, validateOptionalRateField(mp.RedemptionFee, "redemptionFee ("+mp.Ticker+")"))
+	addError(errs, path, validateOptionalRateField(mp.WithholdingTaxRate, "withholdingTaxRate ("+mp.Ticker+")"))
+	addError(errs, path, validateOptionalRateField(mp.AllocationFloor, "allocationFloor ("+mp.Ticker+")"))
+	switch strings.ToLower(strings.TrimSpace(mp.AssetClass)) {
+	case "", splitter.AssetClassEquity, splitter.AssetClassBond:
+	default:
+		addError(errs, path, invalid(models.CodeInvalidOption, "assetClass", "assetClass (%s): must be %q or %q", mp.Ticker, splitter.AssetClassEquity, splitter.AssetClassBond))
+	}
+	if strings.TrimSpace(mp.MinHoldingPeriodDays) != "" {
+		_, err := parseNonNegInt(mp.MinHoldingPeriodDays, "minHoldingPeriodDays ("+mp.Ticker+")")
+		addError(errs, path, err)
+	}
+	if strings.TrimSpace(mp.PriceDate) != "" {
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(mp.PriceDate)); err != nil {
+			addError(errs, path, invalid(models.CodeInvalidDate, "priceDate", "priceDate (%s): must be a date in YYYY-MM-DD format", mp.Ticker))
+		}
+	}
+}
+
+// weightSumTolerance is how far the weights blended by shrinkageCoefficient may sum from 1.
+var weightSumTolerance = decimal.New(1, -4)
+
+// validateBlendedWeights checks that g's model weights still sum to 1 once blended toward
+// equal weight. Blending preserves the sum only when the weights already sum to 1, so
+// weights that do not are rejected unless normalizeWeights is set. Invalid weights are
+// reported by validateModelItem and skipped here.
+func validateBlendedWeights(g models.Goal, shrinkage string) error {
+	shrink, _ := decimal.NewFromString(strings.TrimSpace(shrinkage))
+	total := decZero
+	positive := 0
+	for _, mp := range g.ModelPortfolioDetails {
+		w, err := decimal.NewFromString(mp.Weight)
+		if err != nil || w.IsNegative() {
+			return nil
+		}
+		total = total.Add(w)
+		if w.IsPositive() {
+			positive++
+		}
+	}
+	if positive == 0 {
+		return nil
+	}
+	blended := decOne.Sub(shrink).Mul(total).Add(shrink)
+	if blended.Sub(decOne).Abs().GreaterThan(weightSumTolerance) {
+		return invalid(models.CodeWeightSumMismatch, "modelPortfolioDetails", "modelPortfolioDetails (%s): weights blended by shrinkageCoefficient sum to %s, not 1; send weights that sum to 1 or set normalizeWeights",
+			g.GoalID, blended.String())
+	}
+	return nil
+}
+
+// validateGlidePath checks g's targetDate and, when it is set, its glidePath: at least one
+// point, each with a whole number of years and equity and bond weights between 0 and 1.
+func validateGlidePath(g models.Goal, path string, errs *[]models.ValidationError) {
+	if strings.TrimSpace(g.TargetDate) == "" {
+		return
+	}
+	if _, err := time.Parse("2006-01-02", strings.TrimSpace(g.TargetDate)); err != nil {
+		addError(errs, path, invalid(models.CodeInvalidDate, "targetDate", "targetDate (%s): must be a date in YYYY-MM-DD format", g.GoalID))
+	}
+	if len(g.GlidePath) == 0 {
+		addError(errs, path, invalid(models.CodeRequired, "glidePath", "glidePath (%s): must not be empty when targetDate is set", g.GoalID))
+	}
+	for k, p := range g.GlidePath {
+		name := fmt.Sprintf("glidePath[%d]", k)
+		_, err := parseNonNegInt(p.YearsToTarget, name+".yearsToTarget")
+		addError(errs, path, err)
+		for _, f := range []struct{ v, name string }{
+			{p.EquityWeight, name + ".equityWeight"},
+			{p.BondWeight, name + ".bondWeight"},
+		} {
+			w, err := decimal.NewFromString(strings.TrimSpace(f.v))
+			if err != nil {
+				addError(errs, path, invalid(models.CodeInvalidDecimal, f.name, "%s: must be a number between 0 and 1", f.name))
+			} else if w.LessThan(decZero) || w.GreaterThan(decOne) {
+				addError(errs, path, invalid(models.CodeWeightOutOfRange, f.name, "%s: must be a number between 0 and 1", f.name))
+			}
+		}
+	}
+}
+
+// validateRedemptionCap checks that a redemption sized by orderAmount asks for no more than
+// maxRedemptionPct of its holdings' value. The cash drawn first is not capped, so it is
+// taken off the order before the comparison, as the splitter does. capErr is the result of
+// validating maxRedemptionPct; the check is skipped when it is invalid or absent.
+func validateRedemptionCap(g models.Goal, capErr error) error {
+	if capErr != nil || strings.TrimSpace(g.MaxRedemptionPct) == "" || g.CashOut {
+		return nil
+	}
+	capPct, _ := decimal.NewFromString(strings.TrimSpace(g.MaxRedemptionPct))
+	holdingsValue := decZero
+	for _, h := range g.GoalDetails {
+		if v, _ := decimal.NewFromString(h.Value); v.IsPositive() {
+			holdingsValue = holdingsValue.Add(v)
 		}
 	}
-	return validateOptionalRateField(mp.TransactionFee, "transactionFee ("+mp.Ticker+")")
+	orderAmount, _ := decimal.NewFromString(g.OrderAmount)
+	if cash, err := decimal.NewFromString(strings.TrimSpace(g.CashBalance)); err == nil && cash.IsPositive() {
+		orderAmount = decimal.Max(orderAmount.Sub(cash), decZero)
+	}
+	if limit := holdingsValue.Mul(capPct); orderAmount.GreaterThan(limit) {
+		return invalid(models.CodeExceedsRedemptionCap, "orderAmount", "orderAmount (%s) cannot be greater than maxRedemptionPct of the holdings' value (%s)", g.OrderAmount, limit.String())
+	}
+	return nil
+}
+
+// validateCashBalance validates g's cashBalance: an optional amount, only on redemptions
+// sized by orderAmount.
+func validateCashBalance(g models.Goal, amtP int) error {
+	if strings.TrimSpace(g.CashBalance) == "" {
+		return nil
+	}
+	if !strings.EqualFold(strings.TrimSpace(g.OrderType), "redemption") {
+		return invalid(models.CodeUnsupportedOrderType, "cashBalance", "cashBalance: only supported for redemption orders")
+	}
+	if strings.TrimSpace(g.OrderUnit) != "" {
+		return invalid(models.CodeConflictingFields, "cashBalance, orderUnit", "cashBalance, orderUnit (%s): cash cannot be drawn on an order in units", g.GoalID)
+	}
+	if g.CashOut {
+		return invalid(models.CodeConflictingFields, "cashBalance, cashOut", "cashBalance, cashOut (%s): cannot be combined", g.GoalID)
+	}
+	if splitter.HasRedemptionPercents(g) {
+		return invalid(models.CodeConflictingFields, "cashBalance, redemptionPercent", "cashBalance, redemptionPercent (%s): cash cannot be drawn on an order by redemptionPercent", g.GoalID)
+	}
+	return validateAmountField(g.CashBalance, "cashBalance ("+g.GoalID+")", false, amtP)
+}
+
+// validateFullRedemptionTolerance validates that s, when set, is a decimal in (0, 1].
+func validateFullRedemptionTolerance(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	const msg = "fullRedemptionTolerance: must be a number > 0 and <= 1"
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return invalid(models.CodeInvalidDecimal, "fullRedemptionTolerance", msg)
+	}
+	if !d.IsPositive() || d.GreaterThan(decOne) {
+		return invalid(models.CodeToleranceOutOfRange, "fullRedemptionTolerance", msg)
+	}
+	return nil
+}
+
+// validateToleranceBuffer rejects a fullRedemptionTolerance below 1 − volatilityBuffer for
+// goal, the goal's own buffer taking priority. The tolerance band would otherwise reach
+// below the Big Redemption band and turn Small Redemptions into Full ones.
+func validateToleranceBuffer(goal models.Goal, req models.SplitRequest) error {
+	tol, _ := decimal.NewFromString(strings.TrimSpace(req.FullRedemptionTolerance))
+	s := req.VolatilityBuffer
+	if strings.TrimSpace(goal.VolatilityBuffer) != "" {
+		s = goal.VolatilityBuffer
+	}
+	buf, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil || !buf.IsPositive() || !tol.IsPositive() || tol.GreaterThanOrEqual(decOne.Sub(buf)) {
+		return nil
+	}
+	return invalid(models.CodeConflictingFields, "fullRedemptionTolerance, volatilityBuffer ("+goal.GoalID+")",
+		"fullRedemptionTolerance, volatilityBuffer (%s): fullRedemptionTolerance must be at least 1 - volatilityBuffer (%s)", goal.GoalID, decOne.Sub(buf))
+}
+
+// validateClientReference checks an optional clientReference named by label. Only its
+// length is limited; it is otherwise echoed verbatim.
+func validateClientReference(s, label string) error {
+	if n := utf8.RuneCountInString(s); n > MaxClientReferenceLength {
+		return invalid(models.CodeTooLong, label, "%s: must be at most %d characters, got %d", label, MaxClientReferenceLength, n)
+	}
+	return nil
 }
 
 // validateAmountField validates a decimal amount or unit quantity.
@@ -162,16 +660,16 @@ func validateAmountField(s, field string, mustBePositive bool, maxPrec int) erro
 	s = strings.TrimSpace(s)
 	d, err := decimal.NewFromString(s)
 	if err != nil {
-		return fmt.Errorf("%s: must be a valid decimal number", field)
+		return invalid(models.CodeInvalidDecimal, field, "%s: must be a valid decimal number", field)
 	}
 	if mustBePositive && !d.IsPositive() {
-		return fmt.Errorf("%s: must be greater than 0", field)
+		return invalid(models.CodeMustBePositive, field, "%s: must be greater than 0", field)
 	}
 	if !mustBePositive && d.IsNegative() {
-		return fmt.Errorf("%s: must be >= 0", field)
+		return invalid(models.CodeMustBeNonNegative, field, "%s: must be >= 0", field)
 	}
 	if places := decimalPlaces(s); places > maxPrec {
-		return fmt.Errorf("%s: must have at most %d decimal place(s)", field, maxPrec)
+		return invalid(models.CodeExceedsPrecision, field, "%s: must have at most %d decimal place(s)", field, maxPrec)
 	}
 	return nil
 }
@@ -179,8 +677,11 @@ func validateAmountField(s, field string, mustBePositive bool, maxPrec int) erro
 // validatePriceField validates that s is a strictly positive decimal (no precision constraint).
 func validatePriceField(s, field string) error {
 	d, err := decimal.NewFromString(strings.TrimSpace(s))
-	if err != nil || !d.IsPositive() {
-		return fmt.Errorf("%s: must be a number greater than 0", field)
+	if err != nil {
+		return invalid(models.CodeInvalidDecimal, field, "%s: must be a number greater than 0", field)
+	}
+	if !d.IsPositive() {
+		return invalid(models.CodeMustBePositive, field, "%s: must be a number greater than 0", field)
 	}
 	return nil
 }
@@ -188,8 +689,11 @@ func validatePriceField(s, field string) error {
 // validateRateField validates that s is a decimal in [0, 1) — used for fees and volatilityBuffer.
 func validateRateField(s, field string) error {
 	d, err := decimal.NewFromString(strings.TrimSpace(s))
-	if err != nil || d.IsNegative() || d.GreaterThanOrEqual(decOne) {
-		return fmt.Errorf("%s: must be a number >= 0 and < 1", field)
+	if err != nil {
+		return invalid(models.CodeInvalidDecimal, field, "%s: must be a number >= 0 and < 1", field)
+	}
+	if d.IsNegative() || d.GreaterThanOrEqual(decOne) {
+		return invalid(models.CodeRateOutOfRange, field, "%s: must be a number >= 0 and < 1", field)
 	}
 	return nil
 }
@@ -203,6 +707,23 @@ func validateOptionalAmountField(s, field string, maxPrec int) error {
 	return validateAmountField(s, field, false, maxPrec)
 }
 
+// validateOptionalSignedAmountField validates a decimal of either sign with at most maxPrec
+// decimal places, treating an empty field as valid (defaults to 0). Used for pending
+// settlement fields, where a negative value is a pending sell.
+func validateOptionalSignedAmountField(s, field string, maxPrec int) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if _, err := decimal.NewFromString(s); err != nil {
+		return invalid(models.CodeInvalidDecimal, field, "%s: must be a valid decimal number", field)
+	}
+	if places := decimalPlaces(s); places > maxPrec {
+		return invalid(models.CodeExceedsPrecision, field, "%s: must have at most %d decimal place(s)", field, maxPrec)
+	}
+	return nil
+}
+
 // validateOptionalRateField validates a decimal in [0, 1), but treats an empty or absent
 // field as valid (defaults to 0).
 func validateOptionalRateField(s, field string) error {
@@ -212,11 +733,30 @@ func validateOptionalRateField(s, field string) error {
 	return validateRateField(s, field)
 }
 
+// validateOptionalShareField validates an optional share of a whole, a decimal in [0, 1]
+// where 1 is all of it; an empty field is valid.
+func validateOptionalShareField(s, field string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return invalid(models.CodeInvalidDecimal, field, "%s: must be a number between 0 and 1", field)
+	}
+	if d.IsNegative() || d.GreaterThan(decOne) {
+		return invalid(models.CodeWeightOutOfRange, field, "%s: must be a number between 0 and 1", field)
+	}
+	return nil
+}
+
 // parseNonNegInt parses s as a non-negative integer.
 func parseNonNegInt(s, field string) (int, error) {
 	n, err := strconv.Atoi(strings.TrimSpace(s))
-	if err != nil || n < 0 {
-		return 0, fmt.Errorf("%s: must be a non-negative integer", field)
+	if err != nil {
+		return 0, invalid(models.CodeInvalidInteger, field, "%s: must be a non-negative integer", field)
+	}
+	if n < 0 {
+		return 0, invalid(models.CodeMustBeNonNegative, field, "%s: must be a non-negative integer", field)
 	}
 	return n, nil
 }
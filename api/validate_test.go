@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// TestValidationReportsEveryError checks that a request with several bad fields gets a
+// 422 listing each of them, in request order, with its path, field and code.
+func TestValidationReportsEveryError(t *testing.T) {
+	req := validRequest(testhelpers.NewGoalBuilder("").
+		WithOrderAmount("-5").
+		WithTicker("A", "0.5", "10").
+		WithTicker("B", "abc", "20").
+		WithHolding("A", "10", "10").
+		Build())
+	req.TieBreak = "random"
+	req.Goals[0].GoalDetails[0].Units = "x"
+
+	rec := do(t, http.HandlerFunc(HandleSplit), http.MethodPost, "/split", req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, want %d; body %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+	}
+	got := decode[[]models.ValidationError](t, rec)
+	want := []models.ValidationError{
+		{Path: "", Field: "tieBreak", Code: models.CodeInvalidOption},
+		{Path: "goals[0]", Field: "goalId", Code: models.CodeRequired},
+		{Path: "goals[0]", Field: "orderAmount", Code: models.CodeMustBePositive},
+		{Path: "goals[0].goalDetails[0]", Field: "units", Code: models.CodeInvalidDecimal},
+		{Path: "goals[0].modelPortfolioDetails[1]", Field: "weight", Code: models.CodeInvalidDecimal},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d errors, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Path != w.Path || g.Field != w.Field || g.Code != w.Code {
+			t.Errorf("error %d = {%q %q %s}, want {%q %q %s}", i, g.Path, g.Field, g.Code, w.Path, w.Field, w.Code)
+		}
+		if g.Message == "" {
+			t.Errorf("error %d has no message", i)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+// Command schema prints a JSON Schema for the /split request or response body.
+//
+//	go run ./cmd/schema                 # request schema (models.SplitRequest)
+//	go run ./cmd/schema -type response  # response schema ([]models.GoalResult)
+//
+// The schema is derived from the models package by reflection. Descriptions and
+// constraints live in the fields map below; numeric values travel as strings, so their
+// minimum/maximum describe the number the string must parse to.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// field describes one JSON property, keyed in fields by "<GoType>.<jsonName>".
+type field struct {
+	doc      string
+	numeric  bool     // a decimal or integer carried as a string
+	integer  bool     // numeric and must be a whole number
+	min, max *float64 // bounds on the parsed number
+	exclMax  bool     // max is exclusive
+	emptyOK  bool     // numeric, or an empty string
+	enum     []string
+	required bool
+}
+
+func f(v float64) *float64 { return &v }
+
+const (
+	amtDoc  = " At most amountDecimalPrecision decimal places."
+	unitDoc = " At most unitDecimalPrecision decimal places."
+)
+
+var minFields = map[string]string{
+	"minInitialInvestmentAmt":   "Minimum first-time purchase amount (net)." + amtDoc,
+	"minInitialInvestmentUnits": "Minimum first-time purchase units (net)." + unitDoc,
+	"minTopupAmt":               "Minimum subsequent purchase amount (net)." + amtDoc,
+	"minTopupUnits":             "Minimum subsequent purchase units (net)." + unitDoc,
+	"minRedemptionAmt":          "Minimum redemption amount (gross)." + amtDoc,
+	"minRedemptionUnits":        "Minimum redemption units." + unitDoc,
+	"minHoldingAmt":             "Minimum position value after a partial redemption or a buy." + amtDoc,
+	"minHoldingUnits":           "Minimum position units after a partial redemption or a buy." + unitDoc,
+}
+
+var fields = map[string]field{
+	"SplitRequest.amountDecimalPrecision":    {doc: "Number of decimal places for all monetary amounts.", numeric: true, integer: true, min: f(0), required: true},
+	"SplitRequest.unitDecimalPrecision":      {doc: "Number of decimal places for all unit quantities.", numeric: true, integer: true, min: f(0), required: true},
+	"SplitRequest.volatilityBuffer":          {doc: "Classifies redemptions as Small/Big Redemption.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"SplitRequest.consolidationThreshold":    {doc: "Redemptions: sell every position worth less than this in full, whatever its model weight, flagged CONSOLIDATED." + amtDoc, numeric: true, min: f(0)},
+	"SplitRequest.minTradeAmt":               {doc: "Global minimum trade size; smaller trades are dropped and reallocated." + amtDoc, numeric: true, min: f(0)},
+	"SplitRequest.zeroOnMinViolation":        {doc: "Zero and redistribute investment products whose minimum cannot be met."},
+	"SplitRequest.residualPolicy":            {doc: "How the repair step and the redemption truncation residual hand out leftover units.", enum: []string{"largest_remainder", "round_robin", "highest_weight"}},
+	"SplitRequest.allocationStrategy":        {doc: "Investment ideal computation.", enum: []string{"shortfall", "pro_rata", "equal"}},
+	"SplitRequest.allocationMode":            {doc: "Alias of allocationStrategy limited to shortfall and pro_rata; used when allocationStrategy is empty.", enum: []string{"shortfall", "pro_rata"}},
+	"SplitRequest.includeEffectiveWeights":   {doc: "Add effectiveWeights to investment results."},
+	"SplitRequest.lockCurrentHoldings":       {doc: "Investments: split the order over the model products already held, in proportion to their value, so that no held position's share falls; products not yet held are not bought."},
+	"SplitRequest.ignoreCurrentHoldings":     {doc: "Investments: split every goal pro-rata as if it held nothing, so initial-investment minimums apply to every product."},
+	"SplitRequest.recomputeHoldingValues":    {doc: "Recompute every holding's value as units × marketPrice before splitting."},
+	"SplitRequest.clientReference":           {doc: "The caller's own reference for the request, echoed on the response. At most 256 characters."},
+	"SplitRequest.currency":                  {doc: "Three-letter ISO 4217 code of every amount in the request; goals with a currency must match it."},
+	"SplitRequest.includeWeights":            {doc: "Add modelWeight, preTradeWeight, postTradeWeight and drift to every transaction detail."},
+	"SplitRequest.quiet":                     {doc: "Leave out every warning, on the response and on its goals."},
+	"SplitRequest.includeHolds":              {doc: "Report a BUY or SELL of zero as a HOLD with a holdReason."},
+	"SplitRequest.weightDecimalPrecision":    {doc: "Decimal places of the includeWeights fields; defaults to 6.", numeric: true, integer: true, min: f(0)},
+	"SplitRequest.weightPrecision":           {doc: "Decimal places each model weight is rounded to before it is checked and split on; unset uses weights as sent.", numeric: true, integer: true, min: f(0)},
+	"SplitRequest.includePostTrade":          {doc: "Add postTradeHoldings and postTradeTotal, the goal as it would stand after the trades, to every result."},
+	"SplitRequest.repairObjective":           {doc: "How the investment repair step chooses which violations to fix.", enum: []string{"most_fixed", "max_invested", "min_drift"}},
+	"SplitRequest.minProgressGuard":          {doc: "Never zero a product in the repair step if that moves it away from its model weight."},
+	"SplitRequest.repairStrategy":            {doc: "How the investment repair step takes bumps back from the products funding them.", enum: []string{"greedy", "proportional"}},
+	"SplitRequest.tieBreak":                  {doc: "Order of repair zero-out candidates that tie on priority and required gross.", enum: []string{"model_order", "ticker", "largest_weight"}},
+	"SplitRequest.zeroOutMaxWeight":          {doc: "Products above this model weight are never zeroed by the repair step.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"SplitRequest.idempotencyKey":            {doc: "Client-chosen key; an identical retry within 10 minutes replays the earlier /split response."},
+	"SplitRequest.normalizeWeights":          {doc: "Rescale each goal's model weights to sum to 1 before splitting."},
+	"SplitRequest.shrinkageCoefficient":      {doc: "Blend model weights toward equal weight: w' = (1 − s) × w + s / N.", numeric: true, min: f(0), max: f(1)},
+	"SplitRequest.minWeightDeviationTrigger": {doc: "Investments: products whose current weight is within this of their model weight are not traded.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"SplitRequest.autoFullRedemption":        {doc: "Redemptions: shorthand for minHoldingPolicy sell_all."},
+	"SplitRequest.minHoldingPolicy":          {doc: "Redemptions: what to do with a sell that would leave less than the holding minimum.", enum: []string{"flag", "sell_all", "reduce"}},
+	"SplitRequest.proceedsMode":              {doc: "Redemptions: size sells on their gross value, their proceeds after the fee, or their proceeds after fee and withholding tax.", enum: []string{"gross", "net", "net_after_tax"}},
+	"SplitRequest.redemptionMinimumBasis":    {doc: "Redemptions: check minRedemptionAmt against the gross sell or its proceeds after the fee.", enum: []string{"gross", "net"}},
+	"SplitRequest.allowMissingPrice":         {doc: "Redemptions: accept holdings and model items without a marketPrice, handled per missingPricePolicy."},
+	"SplitRequest.missingPricePolicy":        {doc: "Redemptions with allowMissingPrice: leave products without a price unsold, or sell them as manual instructions.", enum: []string{"exclude", "manual"}},
+	"SplitRequest.rebuildBigRedemption":      {doc: "Redemptions: split a Big Redemption as sells covering the order plus the volatility buffer and buys reinvesting the buffer to model."},
+	"SplitRequest.whatIfAmounts":             {doc: "Alternative order amounts to re-run every investment goal at (at most 20); summarised in whatIfResults."},
+	"SplitRequest.pageSize":                  {doc: "Split at most this many goals per call; X-Next-Cursor resumes after them.", numeric: true, integer: true, min: f(1)},
+	"SplitRequest.cursor":                    {doc: "X-Next-Cursor from the previous page of the same request."},
+	"SplitRequest.requestVersion":            {doc: "Shape of the /split response: v1 returns the array of goal results (deprecated), v2 the SplitResponse envelope. Defaults to the latest, v2.", enum: []string{"v1", "v2"}},
+	"SplitRequest.sortOutputByTicker":        {doc: "Order each goal's transactionDetails by ticker instead of by model or holding order."},
+	"SplitRequest.phase1Order":               {doc: "Redemptions: order in which zero-weight and absent products are sold in Phase 1.", enum: []string{"smallest_first", "largest_first", "model_order"}},
+	"SplitRequest.fullRedemptionTolerance":   {doc: "Redemptions: an order of at least this fraction of the goal value (> 0) sells all of it.", numeric: true, min: f(0), max: f(1)},
+	"SplitRequest.lotSelection":              {doc: "Redemptions: order in which a sell takes units from its holding's lots.", enum: []string{"fifo", "lifo", "hifo", "min_gain"}},
+	"SplitRequest.scaleLotUnits":             {doc: "Scale each holding's lots to add up to its units instead of rejecting lots that do not."},
+	"SplitRequest.maxPriceAgeDays":           {doc: "Flag trades priced from a model item whose priceDate is more than this many days old with STALE_PRICE.", numeric: true, integer: true, min: f(0)},
+	"SplitRequest.severityOverrides":         {doc: "Severity of each trade error code, \"error\" (the default) or \"warning\"; strict mode only fails on errors."},
+	"SplitRequest.strictMode":                {doc: "Fail the whole request with 422 on the first trade flagged with an error."},
+	"SplitRequest.explain":                   {doc: "Add a trace to every transaction detail and an invariant self-check to every goal."},
+	"SplitRequest.goals":                     {doc: "Goals to process, each independently.", required: true},
+
+	"Goal.goalId":                {doc: "Unique identifier for the goal.", required: true},
+	"Goal.goalDetails":           {doc: "Current holdings. Required and non-empty for redemptions."},
+	"Goal.orderAmount":           {doc: "Gross amount to invest or redeem. Required unless orderUnit or a holding's redemptionPercent is set." + amtDoc, numeric: true, min: f(0)},
+	"Goal.orderUnit":             {doc: "Redemption only: units to redeem instead of orderAmount, converted at the goal's average unit price." + unitDoc, numeric: true, min: f(0)},
+	"Goal.orderType":             {doc: "Type of order (matched case-insensitively).", enum: []string{"Investment", "Redemption"}, required: true},
+	"Goal.modelPortfolioId":      {doc: "Identifier of the attached model portfolio. Required unless cashOut is set."},
+	"Goal.modelPortfolioDetails": {doc: "Target model portfolio. Required unless cashOut is set."},
+	"Goal.maxTrades":             {doc: "Investment only: maximum number of products to buy.", numeric: true, integer: true, min: f(1)},
+	"Goal.minTradeAmt":           {doc: "Overrides the request-level minTradeAmt." + amtDoc, numeric: true, min: f(0)},
+	"Goal.maxRedemptionPct":      {doc: "Redemption only: cap on the share of goal value redeemed; a larger orderAmount is rejected.", numeric: true, min: f(0), max: f(1)},
+	"Goal.keepUnmodeled":         {doc: "Redemption only: keep every holding with a zero or absent model weight instead of selling it in Phase 1."},
+	"Goal.volatilityBuffer":      {doc: "Overrides the request-level volatilityBuffer for this goal.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"Goal.clientReference":       {doc: "The caller's own reference for the goal, echoed on its result. At most 256 characters."},
+	"Goal.currency":              {doc: "Three-letter ISO 4217 code of the goal's amounts; must match the request currency when both are set."},
+	"Goal.cashOut":               {doc: "Redemption only: sell the holdings whole, largest first, up to orderAmount or all of them without one, ignoring model weights."},
+	"Goal.cashBalance":           {doc: "Redemption only: uninvested cash in the goal, drawn before any holding is sold.", numeric: true, min: f(0)},
+	"Goal.targetDate":            {doc: "Date the goal's glide path leads to, YYYY-MM-DD; requires glidePath."},
+	"Goal.glidePath":             {doc: "Equity and bond weights by years to targetDate, interpolated linearly."},
+	"Goal.allocationStrategy":    {doc: "Overrides the request-level allocationStrategy.", enum: []string{"shortfall", "pro_rata", "equal"}},
+
+	"Holding.ticker":               {doc: "Product identifier.", required: true},
+	"Holding.units":                {doc: "Current units held." + unitDoc, numeric: true, min: f(0), required: true},
+	"Holding.marketPrice":          {doc: "Current market price per unit (> 0).", numeric: true, min: f(0), required: true},
+	"Holding.value":                {doc: "Current market value." + amtDoc, numeric: true, min: f(0), required: true},
+	"Holding.pendingValue":         {doc: "Value of unsettled orders: positive for pending buys, negative for pending sells." + amtDoc, numeric: true},
+	"Holding.blockedUnits":         {doc: "Redemption only: units that cannot be sold, e.g. unsettled or frozen by a corporate action. At most units." + unitDoc, numeric: true, min: f(0)},
+	"Holding.pendingUnits":         {doc: "Units of unsettled orders, same sign convention as pendingValue." + unitDoc, numeric: true},
+	"Holding.transactionFee":       {doc: "Fee rate applied by the broker.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"Holding.redemptionFee":        {doc: "Fee rate applied by the broker on sells; defaults to transactionFee.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"Holding.withholdingTaxRate":   {doc: "Tax rate withheld from the proceeds of sells, after the fee.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"Holding.averageCost":          {doc: "Cost per unit of the position; enables estimatedRealizedGain. At most one of averageCost and totalCost.", numeric: true, min: f(0)},
+	"Holding.totalCost":            {doc: "Total cost of the position; enables estimatedRealizedGain. At most one of averageCost and totalCost.", numeric: true, min: f(0)},
+	"Holding.keep":                 {doc: "Redemption only: keep this holding instead of selling it in Phase 1 when its model weight is zero or absent."},
+	"Holding.forceInitialMinimums": {doc: "Investment only: check a buy against the initial-investment minimums rather than the top-up minimums, as for a new position."},
+	"Holding.holdingSince":         {doc: "Redemption only: date the position was bought, YYYY-MM-DD; checked against the model item's minHoldingPeriodDays."},
+	"Holding.redemptionPercent":    {doc: "Redemption only: share of this holding to sell, > 0, in place of the goal's orderAmount.", numeric: true, min: f(0), max: f(1)},
+	"Holding.lots":                 {doc: "The position broken down by purchase; their units add up to units. Cannot be combined with averageCost or totalCost."},
+
+	"GlidePathPoint.yearsToTarget": {doc: "Years before targetDate this point applies at.", numeric: true, integer: true, min: f(0), required: true},
+	"GlidePathPoint.equityWeight":  {doc: "Total weight of the goal's equity model items at this point.", numeric: true, min: f(0), max: f(1), required: true},
+	"GlidePathPoint.bondWeight":    {doc: "Total weight of the goal's bond model items at this point.", numeric: true, min: f(0), max: f(1), required: true},
+
+	"Lot.units":           {doc: "Units bought in this lot." + unitDoc, numeric: true, min: f(0), required: true},
+	"Lot.costPerUnit":     {doc: "Price paid per unit.", numeric: true, min: f(0), required: true},
+	"Lot.acquisitionDate": {doc: "Date the lot was bought, YYYY-MM-DD.", required: true},
+
+	"ModelItem.ticker":               {doc: "Product identifier.", required: true},
+	"ModelItem.weight":               {doc: "Target portfolio weight.", numeric: true, min: f(0), max: f(1), required: true},
+	"ModelItem.marketPrice":          {doc: "Market price per unit (> 0), in the product's local currency.", numeric: true, min: f(0), required: true},
+	"ModelItem.exchangeRate":         {doc: "Local-currency units per base-currency unit (> 0). Defaults to 1.", numeric: true, min: f(0)},
+	"ModelItem.transactionFee":       {doc: "Fee rate applied by the broker.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"ModelItem.redemptionFee":        {doc: "Fee rate applied by the broker on sells; defaults to transactionFee.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"ModelItem.withholdingTaxRate":   {doc: "Tax rate withheld from the proceeds of sells, after the fee.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"ModelItem.protectFromZeroOut":   {doc: "Never zero this product in the repair step; its safe slack can still be used."},
+	"ModelItem.allocationFloor":      {doc: "Investments: share of orderAmount reserved for this product regardless of shortfall.", numeric: true, min: f(0), max: f(1), exclMax: true},
+	"ModelItem.priceDate":            {doc: "Date marketPrice was observed, YYYY-MM-DD; checked against maxPriceAgeDays."},
+	"ModelItem.minHoldingPeriodDays": {doc: "Redemption only: lock-up in days; a holding bought fewer days ago than this is not sold and is flagged HOLDING_PERIOD_VIOLATION.", numeric: true, integer: true, min: f(0)},
+	"ModelItem.assetClass":           {doc: "Asset class whose weight follows the goal's glidePath.", enum: []string{"equity", "bond"}},
+	"ModelItem.blockedFromBuy":       {doc: "Investments: never buy the product and give its share to the others, flagged BUY_BLOCKED. Redemptions sell it as usual."},
+	"ModelItem.priority":             {doc: "Preference rank for discretionary repair-step choices; higher wins.", numeric: true, integer: true, min: f(0)},
+
+	"SplitResponse.results":            {doc: "One result per goal split, in request order.", required: true},
+	"SplitResponse.currency":           {doc: "Currency of every amount: the request's, or the one its goals share."},
+	"SplitResponse.warnings":           {doc: "Problems that did not stop the request, e.g. MIXED_CURRENCIES."},
+	"Warning.code":                     {doc: "Machine-readable warning code.", required: true},
+	"Warning.message":                  {doc: "Human-readable description.", required: true},
+	"Warning.ticker":                   {doc: "Goal warnings: the product the warning is about, when there is one."},
+	"GoalResult.warnings":              {doc: "Non-fatal anomalies in the goal and its trades: WEIGHT_SUM, HOLDING_NOT_IN_MODEL, HOLDING_VALUE_RECOMPUTED, STALE_PRICE or HIGH_FEE. Omitted with quiet."},
+	"SplitResponse.clientReference":    {doc: "The request's clientReference, echoed verbatim."},
+	"GoalResult.clientReference":       {doc: "The goal's clientReference, echoed verbatim."},
+	"SplitResponse.nextCursor":         {doc: "Set when pageSize left goals unsplit; send it as cursor for the next page."},
+	"GoalResult.goalId":                {doc: "Goal identifier from the request.", required: true},
+	"GoalResult.transactionType":       {doc: "Classification of the order.", enum: []string{"Investment", "Partial Redemption", "Full Redemption", "Small Redemption", "Big Redemption"}, required: true},
+	"GoalResult.modelPortfolioId":      {doc: "The goal's modelPortfolioId, echoed as sent."},
+	"GoalResult.orderType":             {doc: "The goal's orderType, echoed as sent.", required: true},
+	"GoalResult.orderAmount":           {doc: "The goal's orderAmount, echoed as sent; see effectiveOrderAmount for the amount actually split.", numeric: true},
+	"GoalResult.transactionDetails":    {doc: "One trade per product.", required: true},
+	"GoalResult.totalPortfolioValue":   {doc: "Redemptions only: total sellable goal value, at amountDecimalPrecision.", numeric: true, min: f(0)},
+	"GoalResult.effectiveOrderAmount":  {doc: "Redemptions with maxRedemptionPct or orderUnit: the amount actually redeemed.", numeric: true, min: f(0)},
+	"GoalResult.unfulfilledAmount":     {doc: "The part of the order no sell, or for an investment no buy below its giveback ceiling, had room for.", numeric: true, min: f(0)},
+	"GoalResult.fullRedemptionExcess":  {doc: "Redemptions within fullRedemptionTolerance: how much more than the order was sold.", numeric: true, min: f(0)},
+	"GoalResult.estimatedTax":          {doc: "Redemptions with withholding tax: total estimatedTax of the sells.", numeric: true, min: f(0)},
+	"GoalResult.estimatedRealizedGain": {doc: "Redemptions: total estimatedRealizedGain of the sells that have one.", numeric: true},
+	"GoalResult.netAfterTax":           {doc: "Redemptions with withholding tax: total netAfterTax of the sells.", numeric: true, min: f(0)},
+	"GoalResult.tradeCount":            {doc: "Number of transactionDetails with a non-zero value.", required: true},
+	"GoalResult.errorCount":            {doc: "Number of transactionDetails flagged with an error.", required: true},
+	"GoalResult.violationSummary":      {doc: "Transaction details flagged with a minimum violation, in detail order."},
+	"GoalResult.skippedProducts":       {doc: "Model items and holdings that received no trade for a structural reason, in model then holding order."},
+	"SkippedProduct.ticker":            {doc: "Product identifier.", required: true},
+	"SkippedProduct.code":              {doc: "The error code of a zero trade, or ZERO_WEIGHT, NOT_IN_MODEL, NOT_HELD or KEPT for a product with no transaction detail.", required: true},
+	"SkippedProduct.message":           {doc: "Human-readable reason.", required: true},
+	"GoalResult.summary":               {doc: "Totals of the goal's trades at amountDecimalPrecision."},
+	"GoalSummary.totalGrossValue":      {doc: "Sum of the value of every BUY and SELL.", numeric: true, min: f(0), required: true},
+	"GoalSummary.totalNetValue":        {doc: "totalGrossValue less totalEstimatedFees.", numeric: true, min: f(0), required: true},
+	"GoalSummary.totalEstimatedFees":   {doc: "Sum of each trade's value times its fee, rounded up.", numeric: true, min: f(0), required: true},
+	"GoalSummary.unallocatedAmount":    {doc: "What the trades leave of the order, never negative.", numeric: true, min: f(0), required: true},
+	"GoalSummary.tradeCount":           {doc: "Same as GoalResult.tradeCount.", required: true},
+	"GoalSummary.violationCount":       {doc: "Number of violationSummary entries.", required: true},
+	"GoalSummary.totalGrossProceeds":   {doc: "Redemptions: the SELLs plus any cash drawn.", numeric: true, min: f(0)},
+	"GoalSummary.totalNetProceeds":     {doc: "Redemptions: totalGrossProceeds less the fees on the SELLs.", numeric: true, min: f(0)},
+	"ViolationRecord.ticker":           {doc: "Product identifier.", required: true},
+	"ViolationRecord.errorCode":        {doc: "The detail's error code.", enum: []string{"MIN_INVESTMENT_VIOLATION", "MIN_TOPUP_VIOLATION", "MIN_HOLDING_VIOLATION", "MIN_REDEMPTION_VIOLATION", "BELOW_MIN_TRADE"}, required: true},
+	"ViolationRecord.requiredAmt":      {doc: "Minimum amount the trade was checked against, as sent; absent when the minimum is in units only.", numeric: true},
+	"ViolationRecord.allocatedAmt":     {doc: "The trade's value.", numeric: true, required: true},
+	"GoalResult.effectiveWeights":      {doc: "Achieved versus model weights (includeEffectiveWeights)."},
+	"EffectiveWeight.ticker":           {doc: "Product identifier, one per product with weight > 0.", required: true},
+	"EffectiveWeight.modelWeight":      {doc: "Model weight as split on, after normalizeWeights or shrinkageCoefficient, to 6 decimal places.", numeric: true, min: f(0), max: f(1), required: true},
+	"EffectiveWeight.achievedWeight":   {doc: "value / orderAmount, to 6 decimal places.", numeric: true, min: f(0), required: true},
+	"EffectiveWeight.absDeviation":     {doc: "|achievedWeight − modelWeight|, to 6 decimal places.", numeric: true, min: f(0), required: true},
+	"GoalResult.postTradeHoldings":     {doc: "Every product of the goal after the trades (includePostTrade)."},
+	"GoalResult.postTradeTotal":        {doc: "Projected goal value after the trades (includePostTrade).", numeric: true, min: f(0)},
+	"PostTradeHolding.ticker":          {doc: "Product identifier, or CASH for the goal's remaining cashBalance.", required: true},
+	"PostTradeHolding.value":           {doc: "Projected value after the trades, net of buy fees, at amountDecimalPrecision.", numeric: true, min: f(0), required: true},
+	"PostTradeHolding.units":           {doc: "Projected units at unitDecimalPrecision; empty for cash, or when a sell was placed by value only.", numeric: true, emptyOK: true, required: true},
+	"PostTradeHolding.weight":          {doc: "Share of postTradeTotal, to 6 decimal places; the weights sum to 1.", numeric: true, min: f(0), max: f(1), required: true},
+	"GoalResult.whatIfResults":         {doc: "Investments only: one summary per whatIfAmounts entry."},
+	"GoalResult.error":                 {doc: "Goal-level problem, e.g. NOTHING_TO_ALLOCATE when no product has weight > 0."},
+	"GoalResult.debug":                 {doc: "Diagnostics, present when repairObjective is sent or explain is true."},
+
+	"GoalDebug.repairObjective": {doc: "Repair objective actually applied.", enum: []string{"most_fixed", "max_invested", "min_drift"}},
+	"GoalDebug.selfCheck":       {doc: "Invariant check of this result, present when explain is true."},
+
+	"SelfCheck.passed":     {doc: "True when the result meets every invariant.", required: true},
+	"SelfCheck.violations": {doc: "Each invariant the result fails."},
+
+	"InvariantViolation.invariant": {doc: "Failed invariant.", enum: []string{"TOTAL_MISMATCH", "NEGATIVE_AMOUNT", "UNITS_EXCEED_VALUE", "VALUE_EXCEEDS_UNITS", "OVERSELL", "FALSE_VIOLATION", "MISSED_VIOLATION"}, required: true},
+	"InvariantViolation.ticker":    {doc: "Product concerned; empty for goal-wide invariants."},
+	"InvariantViolation.message":   {doc: "Human-readable description.", required: true},
+
+	"WhatIfResult.orderAmount":      {doc: "The alternative order amount, at amountDecimalPrecision.", numeric: true, min: f(0), required: true},
+	"WhatIfResult.violations":       {doc: "Number of trades flagged with an error.", required: true},
+	"WhatIfResult.unallocated":      {doc: "orderAmount minus the sum of trade values.", numeric: true, min: f(0), required: true},
+	"WhatIfResult.effectiveWeights": {doc: "Achieved versus model weights at this amount.", required: true},
+
+	"TransactionDetail.ticker":                {doc: "Product identifier.", required: true},
+	"TransactionDetail.direction":             {doc: "Trade direction; CASH_OUT for cash drawn from the goal's cashBalance, HOLD with includeHolds for a product not traded.", enum: []string{"BUY", "SELL", "CASH_OUT", "HOLD"}, required: true},
+	"TransactionDetail.value":                 {doc: "Gross order amount, at amountDecimalPrecision.", numeric: true, min: f(0), required: true},
+	"TransactionDetail.units":                 {doc: "value / marketPrice, truncated to unitDecimalPrecision; every unit held on a full redemption; empty on a manual instruction by value or a CASH_OUT.", numeric: true, emptyOK: true, min: f(0), required: true},
+	"TransactionDetail.netProceeds":           {doc: "Redemptions with proceedsMode net: proceeds after the fee the sell was sized for.", numeric: true, min: f(0)},
+	"TransactionDetail.estimatedTax":          {doc: "Redemptions with withholding tax: tax withheld from the sell's proceeds after the fee.", numeric: true, min: f(0)},
+	"TransactionDetail.lots":                  {doc: "Redemption sells of holdings with lots: the units taken from each lot, in the order lotSelection sold them."},
+	"TransactionDetail.estimatedRealizedGain": {doc: "Redemption sells of holdings with a cost basis: (price − averageCost) × units; negative for a loss.", numeric: true},
+	"TransactionDetail.netAfterTax":           {doc: "Redemptions with withholding tax: proceeds after the fee and the tax.", numeric: true, min: f(0)},
+	"TransactionDetail.fullRedemption":        {doc: "True on a sell that closes the position; value and units are then exactly the holding's."},
+	"TransactionDetail.price":                 {doc: "marketPrice the units were derived from: the model item's, or the holding's for a redemption sell of a product without a positive model weight. Absent on CASH_OUT.", numeric: true, emptyOK: true},
+	"TransactionDetail.modelWeight":           {doc: "includeWeights: the product's model weight as split on, 0 outside the model.", numeric: true},
+	"TransactionDetail.preTradeWeight":        {doc: "includeWeights: the product's share of the goal before the trades.", numeric: true},
+	"TransactionDetail.postTradeWeight":       {doc: "includeWeights: the product's share of the goal after the trades.", numeric: true},
+	"TransactionDetail.drift":                 {doc: "includeWeights: postTradeWeight less modelWeight.", numeric: true},
+	"TransactionDetail.manualInstruction":     {doc: "Redemptions with allowMissingPrice and missingPricePolicy manual: the field a sell of a product without a price is placed by.", enum: []string{"units", "value"}},
+	"TransactionDetail.holdReason":            {doc: "includeHolds: why a HOLD was not traded; the code of its error when it has one, else AT_WEIGHT or ZEROED."},
+	"TransactionDetail.error":                 {doc: "Present when a minimum requirement is violated (flag-and-keep)."},
+	"TransactionDetail.errors":                {doc: "Present with error: every rule the trade breaks, in the order they were checked; error is the first."},
+	"TransactionDetail.trace":                 {doc: "Intermediate figures behind value, present when explain is true."},
+
+	"LotSale.acquisitionDate": {doc: "acquisitionDate of the lot sold from.", required: true},
+	"LotSale.units":           {doc: "Units taken from the lot, at unitDecimalPrecision.", numeric: true, min: f(0), required: true},
+	"LotSale.costPerUnit":     {doc: "costPerUnit of the lot.", numeric: true, min: f(0), required: true},
+	"LotSale.realizedGain":    {doc: "(price − costPerUnit) × units, at amountDecimalPrecision; negative for a loss.", numeric: true, required: true},
+
+	"Trace.phase":             {doc: "Redemptions only: the phase that produced the sale.", enum: []string{"1", "2"}},
+	"Trace.currentValue":      {doc: "Current holding value, at amountDecimalPrecision.", numeric: true, required: true},
+	"Trace.ideal":             {doc: "Unrounded ideal amount (investment) or overweight amount (redemption phase 2).", numeric: true},
+	"Trace.feeAdjustedGross":  {doc: "Investments only: ideal / (1 − transactionFee), before scaling.", numeric: true},
+	"Trace.grossBeforeRepair": {doc: "Amount handed to the repair step, after the minimum trade check.", numeric: true},
+	"Trace.repairAction":      {doc: "How the repair step changed the amount.", enum: []string{"bumped", "zeroed", "reduced"}},
+	"Trace.repairAdjustment":  {doc: "Signed change made by the repair step.", numeric: true},
+	"Trace.final":             {doc: "Final value, equal to TransactionDetail.value.", numeric: true, min: f(0), required: true},
+
+	"TradeError.message":        {doc: "Human-readable description.", required: true},
+	"TradeError.code":           {doc: "Machine-readable code, e.g. MIN_TOPUP_VIOLATION.", required: true},
+	"TradeError.requiredAmount": {doc: "Breached amount minimum: the minimum, as sent.", numeric: true, min: f(0)},
+	"TradeError.requiredUnits":  {doc: "Breached units minimum: the minimum, as sent.", numeric: true, min: f(0)},
+	"TradeError.actualAmount":   {doc: "Breached amount minimum: the amount checked against it, at amountDecimalPrecision.", numeric: true, min: f(0)},
+	"TradeError.actualUnits":    {doc: "Breached units minimum: the units checked against it, at unitDecimalPrecision.", numeric: true, min: f(0)},
+	"TradeError.shortfall":      {doc: "Breached minimum: how far the amount or units checked fall short of it, rounded up.", numeric: true, min: f(0)},
+	"TradeError.severity":       {doc: "error for a hard block, warning when severityOverrides downgraded the code.", enum: []string{"error", "warning"}},
+}
+
+func init() {
+	for name, doc := range minFields {
+		for _, typ := range []string{"Holding", "ModelItem"} {
+			fields[typ+"."+name] = field{doc: doc, numeric: true, min: f(0)}
+		}
+	}
+}
+
+func main() {
+	kind := flag.String("type", "request", `schema to print: "request" or "response"`)
+	flag.Parse()
+
+	var root reflect.Type
+	var title string
+	switch *kind {
+	case "request":
+		root, title = reflect.TypeOf(models.SplitRequest{}), "SplitRequest"
+	case "response":
+		root, title = reflect.TypeOf(models.SplitResponse{}), "SplitResponse"
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -type %q\n", *kind)
+		os.Exit(2)
+	}
+
+	defs := make(map[string]any)
+	schema := schemaFor(root, defs)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = title
+	schema["$defs"] = defs
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(schema); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// schemaFor returns the schema for t, registering struct types in defs and referring
+// to them by $ref.
+func schemaFor(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), defs)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Struct:
+		if _, seen := defs[t.Name()]; !seen {
+			defs[t.Name()] = nil // reserve the name before recursing
+			defs[t.Name()] = structSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func structSchema(t reflect.Type, defs map[string]any) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		prop := schemaFor(sf.Type, defs)
+		meta := fields[t.Name()+"."+name]
+		if meta.doc != "" {
+			prop = withDoc(prop, meta.doc)
+		}
+		if meta.numeric {
+			prop["pattern"] = `^-?\d+(\.\d+)?$`
+			if meta.integer {
+				prop["pattern"] = `^-?\d+$`
+			}
+			if meta.emptyOK {
+				prop["pattern"] = `^(-?\d+(\.\d+)?)?$`
+			}
+			if meta.min != nil {
+				prop["minimum"] = *meta.min
+			}
+			if meta.max != nil {
+				if meta.exclMax {
+					prop["exclusiveMaximum"] = *meta.max
+				} else {
+					prop["maximum"] = *meta.max
+				}
+			}
+		}
+		if len(meta.enum) > 0 {
+			prop["enum"] = meta.enum
+		}
+		if meta.required {
+			required = append(required, name)
+		}
+		props[name] = prop
+	}
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// withDoc attaches a description, wrapping $ref schemas so the reference stays intact.
+func withDoc(s map[string]any, doc string) map[string]any {
+	if _, isRef := s["$ref"]; isRef {
+		return map[string]any{"allOf": []any{s}, "description": doc}
+	}
+	s["description"] = doc
+	return s
+}
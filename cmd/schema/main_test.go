@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// TestEveryFieldIsDocumented fails when a field of the request or response has no entry
+// in fields, so that a new field cannot ship without a description in the schema.
+func TestEveryFieldIsDocumented(t *testing.T) {
+	seen := make(map[reflect.Type]bool)
+	var missing []string
+	var walk func(reflect.Type)
+	walk = func(typ reflect.Type) {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			walk(typ.Elem())
+			return
+		case reflect.Struct:
+		default:
+			return
+		}
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if name == "-" || !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			if fields[typ.Name()+"."+name].doc == "" {
+				missing = append(missing, typ.Name()+"."+name)
+			}
+			walk(sf.Type)
+		}
+	}
+	walk(reflect.TypeOf(models.SplitRequest{}))
+	walk(reflect.TypeOf(models.SplitResponse{}))
+	sort.Strings(missing)
+	for _, m := range missing {
+		t.Errorf("%s has no description in fields", m)
+	}
+}
@@ -0,0 +1,83 @@
+// Command split runs the splitter on a /split request body without the HTTP server and
+// prints the SplitResponse.
+//
+//	go run ./cmd/split request.json
+//	cat request.json | go run ./cmd/split -pretty | jq .
+//	go run ./cmd/split -amount-prec 2 -unit-prec 4 request.json
+//
+// The request is read from the file named by the first argument, or from stdin when there
+// is none. It goes through api.Split, as over HTTP and gRPC, so it is validated and paged
+// before its goals are split by splitter.ProcessBatch. The response is always the v2
+// envelope, whatever requestVersion says. A request that fails is reported on stderr,
+// validation errors as their JSON array, and the command exits with status 1.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/valentinpj/smart-splitter/api"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+func main() {
+	pretty := flag.Bool("pretty", false, "indent the JSON output")
+	amountPrec := flag.Int("amount-prec", -1, "override the request's amountDecimalPrecision; -1 keeps it")
+	unitPrec := flag.Int("unit-prec", -1, "override the request's unitDecimalPrecision; -1 keeps it")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: split [flags] [request.json]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	in := io.Reader(os.Stdin)
+	if path := flag.Arg(0); path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		in = file
+	}
+	var req models.SplitRequest
+	if err := json.NewDecoder(in).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid request body: "+err.Error())
+		os.Exit(1)
+	}
+	if *amountPrec >= 0 {
+		req.AmountDecimalPrecision = strconv.Itoa(*amountPrec)
+	}
+	if *unitPrec >= 0 {
+		req.UnitDecimalPrecision = strconv.Itoa(*unitPrec)
+	}
+
+	resp, err := api.Split(req)
+	if err != nil {
+		var re *api.RequestError
+		if errors.As(err, &re) && len(re.Errors) > 0 {
+			json.NewEncoder(os.Stderr).Encode(re.Errors)
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	if *pretty {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,381 @@
+// Package grpc exposes the splitter over gRPC. It shares validation and splitting with
+// the HTTP API through api.Split; this package only converts between the protobuf
+// messages in splitterpb and the models types.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/valentinpj/smart-splitter/api"
+	"github.com/valentinpj/smart-splitter/grpc/splitterpb"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// SplitterServer implements splitterpb.SplitterServer.
+type SplitterServer struct {
+	splitterpb.UnimplementedSplitterServer
+}
+
+// NewServer returns a gRPC server with the Splitter service registered.
+func NewServer(opts ...gogrpc.ServerOption) *gogrpc.Server {
+	s := gogrpc.NewServer(opts...)
+	splitterpb.RegisterSplitterServer(s, &SplitterServer{})
+	return s
+}
+
+// Split runs the same validation and splitting as POST /split.
+func (SplitterServer) Split(_ context.Context, in *splitterpb.SplitRequest) (*splitterpb.SplitResponse, error) {
+	resp, err := api.Split(requestFromProto(in))
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	out := &splitterpb.SplitResponse{
+		Results:         make([]*splitterpb.GoalResultProto, len(resp.Results)),
+		NextCursor:      resp.NextCursor,
+		Currency:        resp.Currency,
+		ClientReference: resp.ClientReference,
+	}
+	for i, r := range resp.Results {
+		out.Results[i] = goalResultToProto(r)
+	}
+	for _, w := range resp.Warnings {
+		out.Warnings = append(out.Warnings, &splitterpb.WarningProto{Code: string(w.Code), Message: w.Message, Ticker: w.Ticker})
+	}
+	return out, nil
+}
+
+// statusFromError maps an api.RequestError onto the closest gRPC status code.
+func statusFromError(err error) error {
+	var re *api.RequestError
+	if !errors.As(err, &re) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code := codes.InvalidArgument
+	switch re.StatusCode {
+	case http.StatusRequestEntityTooLarge:
+		code = codes.ResourceExhausted
+	case http.StatusInternalServerError:
+		code = codes.Internal
+	}
+	return status.Error(code, re.Message)
+}
+
+func requestFromProto(in *splitterpb.SplitRequest) models.SplitRequest {
+	req := models.SplitRequest{
+		AmountDecimalPrecision:    in.GetAmountDecimalPrecision(),
+		UnitDecimalPrecision:      in.GetUnitDecimalPrecision(),
+		VolatilityBuffer:          in.GetVolatilityBuffer(),
+		MinTradeAmt:               in.GetMinTradeAmt(),
+		ConsolidationThreshold:    in.GetConsolidationThreshold(),
+		ZeroOnMinViolation:        in.GetZeroOnMinViolation(),
+		ResidualPolicy:            in.GetResidualPolicy(),
+		AllocationStrategy:        in.GetAllocationStrategy(),
+		AllocationMode:            in.GetAllocationMode(),
+		IncludeEffectiveWeights:   in.GetIncludeEffectiveWeights(),
+		LockCurrentHoldings:       in.GetLockCurrentHoldings(),
+		IgnoreCurrentHoldings:     in.GetIgnoreCurrentHoldings(),
+		IncludePostTrade:          in.GetIncludePostTrade(),
+		IncludeWeights:            in.GetIncludeWeights(),
+		IncludeHolds:              in.GetIncludeHolds(),
+		Quiet:                     in.GetQuiet(),
+		WeightDecimalPrecision:    in.GetWeightDecimalPrecision(),
+		WeightPrecision:           in.GetWeightPrecision(),
+		Currency:                  in.GetCurrency(),
+		RecomputeHoldingValues:    in.GetRecomputeHoldingValues(),
+		ClientReference:           in.GetClientReference(),
+		RepairObjective:           in.GetRepairObjective(),
+		RepairStrategy:            in.GetRepairStrategy(),
+		PageSize:                  in.GetPageSize(),
+		Cursor:                    in.GetCursor(),
+		MinProgressGuard:          in.GetMinProgressGuard(),
+		TieBreak:                  in.GetTieBreak(),
+		ZeroOutMaxWeight:          in.GetZeroOutMaxWeight(),
+		IdempotencyKey:            in.GetIdempotencyKey(),
+		NormalizeWeights:          in.GetNormalizeWeights(),
+		ShrinkageCoefficient:      in.GetShrinkageCoefficient(),
+		MinWeightDeviationTrigger: in.GetMinWeightDeviationTrigger(),
+		Phase1Order:               in.GetPhase1Order(),
+		FullRedemptionTolerance:   in.GetFullRedemptionTolerance(),
+		AllowMissingPrice:         in.GetAllowMissingPrice(),
+		MissingPricePolicy:        in.GetMissingPricePolicy(),
+		RequestVersion:            in.GetRequestVersion(),
+		LotSelection:              in.GetLotSelection(),
+		ScaleLotUnits:             in.GetScaleLotUnits(),
+		MaxPriceAgeDays:           in.GetMaxPriceAgeDays(),
+		AutoFullRedemption:        in.GetAutoFullRedemption(),
+		MinHoldingPolicy:          in.GetMinHoldingPolicy(),
+		RebuildBigRedemption:      in.GetRebuildBigRedemption(),
+		ProceedsMode:              in.GetProceedsMode(),
+		RedemptionMinimumBasis:    in.GetRedemptionMinimumBasis(),
+		SortOutputByTicker:        in.GetSortOutputByTicker(),
+		StrictMode:                in.GetStrictMode(),
+		SeverityOverrides:         in.GetSeverityOverrides(),
+		Explain:                   in.GetExplain(),
+		WhatIfAmounts:             in.GetWhatIfAmounts(),
+		Goals:                     make([]models.Goal, len(in.GetGoals())),
+	}
+	for i, g := range in.GetGoals() {
+		req.Goals[i] = goalFromProto(g)
+	}
+	return req
+}
+
+func goalFromProto(g *splitterpb.GoalProto) models.Goal {
+	goal := models.Goal{
+		GoalID:             g.GetGoalId(),
+		OrderAmount:        g.GetOrderAmount(),
+		OrderUnit:          g.GetOrderUnit(),
+		OrderType:          g.GetOrderType(),
+		ModelPortfolioID:   g.GetModelPortfolioId(),
+		MaxTrades:          g.GetMaxTrades(),
+		MinTradeAmt:        g.GetMinTradeAmt(),
+		AllocationStrategy: g.GetAllocationStrategy(),
+		MaxRedemptionPct:   g.GetMaxRedemptionPct(),
+		KeepUnmodeled:      g.GetKeepUnmodeled(),
+		VolatilityBuffer:   g.GetVolatilityBuffer(),
+		CashBalance:        g.GetCashBalance(),
+		CashOut:            g.GetCashOut(),
+		Currency:           g.GetCurrency(),
+		TargetDate:         g.GetTargetDate(),
+		ClientReference:    g.GetClientReference(),
+	}
+	for _, p := range g.GetGlidePath() {
+		goal.GlidePath = append(goal.GlidePath, models.GlidePathPoint{
+			YearsToTarget: p.GetYearsToTarget(),
+			EquityWeight:  p.GetEquityWeight(),
+			BondWeight:    p.GetBondWeight(),
+		})
+	}
+	for _, h := range g.GetGoalDetails() {
+		goal.GoalDetails = append(goal.GoalDetails, models.Holding{
+			Ticker:                    h.GetTicker(),
+			Units:                     h.GetUnits(),
+			MarketPrice:               h.GetMarketPrice(),
+			Value:                     h.GetValue(),
+			PendingValue:              h.GetPendingValue(),
+			PendingUnits:              h.GetPendingUnits(),
+			BlockedUnits:              h.GetBlockedUnits(),
+			RedemptionPercent:         h.GetRedemptionPercent(),
+			HoldingSince:              h.GetHoldingSince(),
+			MinInitialInvestmentAmt:   h.GetMinInitialInvestmentAmt(),
+			MinInitialInvestmentUnits: h.GetMinInitialInvestmentUnits(),
+			MinTopupAmt:               h.GetMinTopupAmt(),
+			MinTopupUnits:             h.GetMinTopupUnits(),
+			MinRedemptionAmt:          h.GetMinRedemptionAmt(),
+			MinRedemptionUnits:        h.GetMinRedemptionUnits(),
+			MinHoldingAmt:             h.GetMinHoldingAmt(),
+			MinHoldingUnits:           h.GetMinHoldingUnits(),
+			TransactionFee:            h.GetTransactionFee(),
+			RedemptionFee:             h.GetRedemptionFee(),
+			WithholdingTaxRate:        h.GetWithholdingTaxRate(),
+			AverageCost:               h.GetAverageCost(),
+			TotalCost:                 h.GetTotalCost(),
+			Lots:                      lotsFromProto(h.GetLots()),
+			Keep:                      h.GetKeep(),
+			ForceInitialMinimums:      h.GetForceInitialMinimums(),
+		})
+	}
+	for _, mp := range g.GetModelPortfolioDetails() {
+		goal.ModelPortfolioDetails = append(goal.ModelPortfolioDetails, models.ModelItem{
+			Ticker:                    mp.GetTicker(),
+			Weight:                    mp.GetWeight(),
+			MarketPrice:               mp.GetMarketPrice(),
+			ExchangeRate:              mp.GetExchangeRate(),
+			MinInitialInvestmentAmt:   mp.GetMinInitialInvestmentAmt(),
+			MinInitialInvestmentUnits: mp.GetMinInitialInvestmentUnits(),
+			MinTopupAmt:               mp.GetMinTopupAmt(),
+			MinTopupUnits:             mp.GetMinTopupUnits(),
+			MinRedemptionAmt:          mp.GetMinRedemptionAmt(),
+			MinRedemptionUnits:        mp.GetMinRedemptionUnits(),
+			MinHoldingAmt:             mp.GetMinHoldingAmt(),
+			MinHoldingUnits:           mp.GetMinHoldingUnits(),
+			TransactionFee:            mp.GetTransactionFee(),
+			RedemptionFee:             mp.GetRedemptionFee(),
+			WithholdingTaxRate:        mp.GetWithholdingTaxRate(),
+			PriceDate:                 mp.GetPriceDate(),
+			AssetClass:                mp.GetAssetClass(),
+			MinHoldingPeriodDays:      mp.GetMinHoldingPeriodDays(),
+			BlockedFromBuy:            mp.GetBlockedFromBuy(),
+			Priority:                  mp.GetPriority(),
+			ProtectFromZeroOut:        mp.GetProtectFromZeroOut(),
+			AllocationFloor:           mp.GetAllocationFloor(),
+		})
+	}
+	return goal
+}
+
+func lotsFromProto(in []*splitterpb.LotProto) []models.Lot {
+	var lots []models.Lot
+	for _, l := range in {
+		lots = append(lots, models.Lot{
+			Units:           l.GetUnits(),
+			CostPerUnit:     l.GetCostPerUnit(),
+			AcquisitionDate: l.GetAcquisitionDate(),
+		})
+	}
+	return lots
+}
+
+func goalResultToProto(r models.GoalResult) *splitterpb.GoalResultProto {
+	out := &splitterpb.GoalResultProto{
+		GoalId:                r.GoalID,
+		ClientReference:       r.ClientReference,
+		TransactionType:       r.TransactionType,
+		ModelPortfolioId:      r.ModelPortfolioID,
+		OrderType:             r.OrderType,
+		OrderAmount:           r.OrderAmount,
+		TotalPortfolioValue:   r.TotalPortfolioValue,
+		EffectiveOrderAmount:  r.EffectiveOrderAmount,
+		UnfulfilledAmount:     r.UnfulfilledAmount,
+		FullRedemptionExcess:  r.FullRedemptionExcess,
+		PostTradeTotal:        r.PostTradeTotal,
+		TradeCount:            int32(r.TradeCount),
+		ErrorCount:            int32(r.ErrorCount),
+		EstimatedTax:          r.EstimatedTax,
+		NetAfterTax:           r.NetAfterTax,
+		EstimatedRealizedGain: r.EstimatedRealizedGain,
+	}
+	for _, d := range r.TransactionDetails {
+		td := &splitterpb.TransactionDetailProto{
+			Ticker:                d.Ticker,
+			Direction:             d.Direction,
+			Value:                 d.Value,
+			Units:                 d.Units,
+			FullRedemption:        d.FullRedemption,
+			ManualInstruction:     d.ManualInstruction,
+			HoldReason:            d.HoldReason,
+			ModelWeight:           d.ModelWeight,
+			PreTradeWeight:        d.PreTradeWeight,
+			PostTradeWeight:       d.PostTradeWeight,
+			Drift:                 d.Drift,
+			Price:                 d.Price,
+			NetProceeds:           d.NetProceeds,
+			EstimatedTax:          d.EstimatedTax,
+			NetAfterTax:           d.NetAfterTax,
+			EstimatedRealizedGain: d.EstimatedRealizedGain,
+		}
+		for _, l := range d.Lots {
+			td.Lots = append(td.Lots, &splitterpb.LotSaleProto{
+				AcquisitionDate: l.AcquisitionDate,
+				Units:           l.Units,
+				CostPerUnit:     l.CostPerUnit,
+				RealizedGain:    l.RealizedGain,
+			})
+		}
+		if d.Error != nil {
+			td.Error = tradeErrorToProto(*d.Error)
+		}
+		for _, e := range d.Errors {
+			td.Errors = append(td.Errors, tradeErrorToProto(e))
+		}
+		if t := d.Trace; t != nil {
+			td.Trace = &splitterpb.TraceProto{
+				Phase:             t.Phase,
+				CurrentValue:      t.CurrentValue,
+				Ideal:             t.Ideal,
+				FeeAdjustedGross:  t.FeeAdjustedGross,
+				GrossBeforeRepair: t.GrossBeforeRepair,
+				RepairAction:      t.RepairAction,
+				RepairAdjustment:  t.RepairAdjustment,
+				Final:             t.Final,
+			}
+		}
+		out.TransactionDetails = append(out.TransactionDetails, td)
+	}
+	out.EffectiveWeights = effectiveWeightsToProto(r.EffectiveWeights)
+	for _, wi := range r.WhatIfResults {
+		out.WhatIfResults = append(out.WhatIfResults, &splitterpb.WhatIfResultProto{
+			OrderAmount:      wi.OrderAmount,
+			Violations:       int32(wi.Violations),
+			Unallocated:      wi.Unallocated,
+			EffectiveWeights: effectiveWeightsToProto(wi.EffectiveWeights),
+		})
+	}
+	if s := r.Summary; s != nil {
+		out.Summary = &splitterpb.GoalSummaryProto{
+			TotalGrossValue:    s.TotalGrossValue,
+			TotalNetValue:      s.TotalNetValue,
+			TotalEstimatedFees: s.TotalEstimatedFees,
+			UnallocatedAmount:  s.UnallocatedAmount,
+			TradeCount:         int32(s.TradeCount),
+			ViolationCount:     int32(s.ViolationCount),
+			TotalGrossProceeds: s.TotalGrossProceeds,
+			TotalNetProceeds:   s.TotalNetProceeds,
+		}
+	}
+	for _, w := range r.Warnings {
+		out.Warnings = append(out.Warnings, &splitterpb.WarningProto{Code: string(w.Code), Message: w.Message, Ticker: w.Ticker})
+	}
+	for _, s := range r.SkippedProducts {
+		out.SkippedProducts = append(out.SkippedProducts, &splitterpb.SkippedProductProto{
+			Ticker:  s.Ticker,
+			Code:    s.Code,
+			Message: s.Message,
+		})
+	}
+	for _, v := range r.ViolationSummary {
+		out.ViolationSummary = append(out.ViolationSummary, &splitterpb.ViolationRecordProto{
+			Ticker:       v.Ticker,
+			ErrorCode:    string(v.ErrorCode),
+			RequiredAmt:  v.RequiredAmt,
+			AllocatedAmt: v.AllocatedAmt,
+		})
+	}
+	for _, p := range r.PostTradeHoldings {
+		out.PostTradeHoldings = append(out.PostTradeHoldings, &splitterpb.PostTradeHoldingProto{
+			Ticker: p.Ticker,
+			Value:  p.Value,
+			Units:  p.Units,
+			Weight: p.Weight,
+		})
+	}
+	if r.Error != nil {
+		out.Error = tradeErrorToProto(*r.Error)
+	}
+	if r.Debug != nil {
+		out.Debug = &splitterpb.GoalDebugProto{RepairObjective: r.Debug.RepairObjective}
+		if sc := r.Debug.SelfCheck; sc != nil {
+			out.Debug.SelfCheck = &splitterpb.SelfCheckProto{Passed: sc.Passed}
+			for _, v := range sc.Violations {
+				out.Debug.SelfCheck.Violations = append(out.Debug.SelfCheck.Violations, &splitterpb.InvariantViolationProto{
+					Invariant: v.Invariant,
+					Ticker:    v.Ticker,
+					Message:   v.Message,
+				})
+			}
+		}
+	}
+	return out
+}
+
+func tradeErrorToProto(e models.TradeError) *splitterpb.TradeErrorProto {
+	return &splitterpb.TradeErrorProto{
+		Message:        e.Message,
+		Code:           string(e.Code),
+		RequiredAmount: e.RequiredAmount,
+		RequiredUnits:  e.RequiredUnits,
+		ActualAmount:   e.ActualAmount,
+		ActualUnits:    e.ActualUnits,
+		Shortfall:      e.Shortfall,
+		Severity:       e.Severity,
+	}
+}
+
+func effectiveWeightsToProto(ews []models.EffectiveWeight) []*splitterpb.EffectiveWeightProto {
+	var out []*splitterpb.EffectiveWeightProto
+	for _, ew := range ews {
+		out = append(out, &splitterpb.EffectiveWeightProto{
+			Ticker:         ew.Ticker,
+			ModelWeight:    ew.ModelWeight,
+			AchievedWeight: ew.AchievedWeight,
+			AbsDeviation:   ew.AbsDeviation,
+		})
+	}
+	return out
+}
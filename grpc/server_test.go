@@ -0,0 +1,193 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/valentinpj/smart-splitter/grpc/splitterpb"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// The proto messages mirror the JSON types field-for-field, so a message with every field
+// set must encode, through protojson, to the same JSON as the models value it maps to. A
+// field added to one side and not mapped shows up as a difference.
+
+func TestRequestFromProtoMapsEveryField(t *testing.T) {
+	in := &splitterpb.SplitRequest{}
+	fillProto(in.ProtoReflect())
+	compareJSON(t, jsonValue(t, requestFromProto(in)), protoJSONValue(t, in))
+}
+
+func TestGoalResultToProtoMapsEveryField(t *testing.T) {
+	var r models.GoalResult
+	fillGo(reflect.ValueOf(&r).Elem())
+	compareJSON(t, jsonValue(t, r), protoJSONValue(t, goalResultToProto(r)))
+}
+
+// fillProto sets every field of m, recursively: strings to the field's JSON name, numbers
+// to 7, booleans to true, and lists and maps to a single element.
+func fillProto(m protoreflect.Message) {
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		switch {
+		case fd.IsMap():
+			m.Mutable(fd).Map().Set(protoreflect.ValueOfString(fd.JSONName()).MapKey(), scalar(fd.MapValue()))
+		case fd.IsList():
+			list := m.Mutable(fd).List()
+			if fd.Message() != nil {
+				e := list.NewElement()
+				fillProto(e.Message())
+				list.Append(e)
+			} else {
+				list.Append(scalar(fd))
+			}
+		case fd.Message() != nil:
+			fillProto(m.Mutable(fd).Message())
+		default:
+			m.Set(fd, scalar(fd))
+		}
+	}
+}
+
+func scalar(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(fd.JSONName())
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(true)
+	case protoreflect.Int32Kind:
+		return protoreflect.ValueOfInt32(7)
+	case protoreflect.Int64Kind:
+		return protoreflect.ValueOfInt64(7)
+	default:
+		panic(fmt.Sprintf("fillProto: unsupported kind %v of %s", fd.Kind(), fd.FullName()))
+	}
+}
+
+// fillGo sets every exported field of v the way fillProto does, using the field's JSON
+// name for strings.
+func fillGo(v reflect.Value) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fillGoValue(v.Field(i), jsonName(f))
+	}
+}
+
+func fillGoValue(v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillGoValue(v.Elem(), name)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillGoValue(v.Index(0), name)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		k := reflect.New(v.Type().Key()).Elem()
+		e := reflect.New(v.Type().Elem()).Elem()
+		fillGoValue(k, name)
+		fillGoValue(e, name)
+		m.SetMapIndex(k, e)
+		v.Set(m)
+	case reflect.Struct:
+		fillGo(v)
+	default:
+		panic(fmt.Sprintf("fillGo: unsupported kind %v of %s", v.Kind(), name))
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	for i, c := range tag {
+		if c == ',' {
+			return tag[:i]
+		}
+	}
+	return tag
+}
+
+func jsonValue(t *testing.T, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func protoJSONValue(t *testing.T, m proto.Message) any {
+	t.Helper()
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// compareJSON reports every path at which the models JSON and the proto JSON differ.
+func compareJSON(t *testing.T, model, pb any) {
+	t.Helper()
+	var diffs []string
+	diffJSON("", model, pb, &diffs)
+	sort.Strings(diffs)
+	for _, d := range diffs {
+		t.Error(d)
+	}
+}
+
+func diffJSON(path string, model, pb any, diffs *[]string) {
+	mm, mok := model.(map[string]any)
+	pm, pok := pb.(map[string]any)
+	if mok && pok {
+		for k, v := range mm {
+			if _, ok := pm[k]; !ok {
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: set in models, not mapped to proto", path, k))
+				continue
+			}
+			diffJSON(path+"."+k, v, pm[k], diffs)
+		}
+		for k := range pm {
+			if _, ok := mm[k]; !ok {
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: set in proto, not mapped to models", path, k))
+			}
+		}
+		return
+	}
+	ml, mok := model.([]any)
+	pl, pok := pb.([]any)
+	if mok && pok && len(ml) == len(pl) {
+		for i := range ml {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), ml[i], pl[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(model, pb) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: models %v, proto %v", path, model, pb))
+	}
+}
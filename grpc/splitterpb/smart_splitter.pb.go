@@ -0,0 +1,3059 @@
+// gRPC contract for the Smart Order Splitter. Messages mirror the JSON types in the
+// models package field-for-field; numeric values stay strings, exactly as on /split.
+//
+// Regenerate grpc/splitterpb after editing this file (see README, "gRPC").
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: smart_splitter.proto
+
+package splitterpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SplitRequest struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	AmountDecimalPrecision  string                 `protobuf:"bytes,1,opt,name=amount_decimal_precision,json=amountDecimalPrecision,proto3" json:"amount_decimal_precision,omitempty"`
+	UnitDecimalPrecision    string                 `protobuf:"bytes,2,opt,name=unit_decimal_precision,json=unitDecimalPrecision,proto3" json:"unit_decimal_precision,omitempty"`
+	VolatilityBuffer        string                 `protobuf:"bytes,3,opt,name=volatility_buffer,json=volatilityBuffer,proto3" json:"volatility_buffer,omitempty"`
+	MinTradeAmt             string                 `protobuf:"bytes,4,opt,name=min_trade_amt,json=minTradeAmt,proto3" json:"min_trade_amt,omitempty"`
+	ZeroOnMinViolation      bool                   `protobuf:"varint,5,opt,name=zero_on_min_violation,json=zeroOnMinViolation,proto3" json:"zero_on_min_violation,omitempty"`
+	ResidualPolicy          string                 `protobuf:"bytes,6,opt,name=residual_policy,json=residualPolicy,proto3" json:"residual_policy,omitempty"`
+	AllocationStrategy      string                 `protobuf:"bytes,7,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	AllocationMode          string                 `protobuf:"bytes,51,opt,name=allocation_mode,json=allocationMode,proto3" json:"allocation_mode,omitempty"`
+	IncludeEffectiveWeights bool                   `protobuf:"varint,8,opt,name=include_effective_weights,json=includeEffectiveWeights,proto3" json:"include_effective_weights,omitempty"`
+	LockCurrentHoldings     bool                   `protobuf:"varint,50,opt,name=lock_current_holdings,json=lockCurrentHoldings,proto3" json:"lock_current_holdings,omitempty"`
+	Goals                   []*GoalProto           `protobuf:"bytes,9,rep,name=goals,proto3" json:"goals,omitempty"`
+	RepairObjective         string                 `protobuf:"bytes,10,opt,name=repair_objective,json=repairObjective,proto3" json:"repair_objective,omitempty"`
+	MinProgressGuard        bool                   `protobuf:"varint,11,opt,name=min_progress_guard,json=minProgressGuard,proto3" json:"min_progress_guard,omitempty"`
+	TieBreak                string                 `protobuf:"bytes,12,opt,name=tie_break,json=tieBreak,proto3" json:"tie_break,omitempty"`
+	ZeroOutMaxWeight        string                 `protobuf:"bytes,13,opt,name=zero_out_max_weight,json=zeroOutMaxWeight,proto3" json:"zero_out_max_weight,omitempty"`
+	// Accepted for parity with the JSON type; responses are only replayed over HTTP.
+	IdempotencyKey            string   `protobuf:"bytes,14,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	NormalizeWeights          bool     `protobuf:"varint,15,opt,name=normalize_weights,json=normalizeWeights,proto3" json:"normalize_weights,omitempty"`
+	Explain                   bool     `protobuf:"varint,16,opt,name=explain,proto3" json:"explain,omitempty"`
+	WhatIfAmounts             []string `protobuf:"bytes,17,rep,name=what_if_amounts,json=whatIfAmounts,proto3" json:"what_if_amounts,omitempty"`
+	ShrinkageCoefficient      string   `protobuf:"bytes,18,opt,name=shrinkage_coefficient,json=shrinkageCoefficient,proto3" json:"shrinkage_coefficient,omitempty"`
+	AutoFullRedemption        bool     `protobuf:"varint,19,opt,name=auto_full_redemption,json=autoFullRedemption,proto3" json:"auto_full_redemption,omitempty"`
+	RepairStrategy            string   `protobuf:"bytes,20,opt,name=repair_strategy,json=repairStrategy,proto3" json:"repair_strategy,omitempty"`
+	PageSize                  string   `protobuf:"bytes,21,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Cursor                    string   `protobuf:"bytes,22,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	MinHoldingPolicy          string   `protobuf:"bytes,23,opt,name=min_holding_policy,json=minHoldingPolicy,proto3" json:"min_holding_policy,omitempty"`
+	RebuildBigRedemption      bool     `protobuf:"varint,24,opt,name=rebuild_big_redemption,json=rebuildBigRedemption,proto3" json:"rebuild_big_redemption,omitempty"`
+	ProceedsMode              string   `protobuf:"bytes,25,opt,name=proceeds_mode,json=proceedsMode,proto3" json:"proceeds_mode,omitempty"`
+	RedemptionMinimumBasis    string   `protobuf:"bytes,26,opt,name=redemption_minimum_basis,json=redemptionMinimumBasis,proto3" json:"redemption_minimum_basis,omitempty"`
+	SortOutputByTicker        bool     `protobuf:"varint,27,opt,name=sort_output_by_ticker,json=sortOutputByTicker,proto3" json:"sort_output_by_ticker,omitempty"`
+	StrictMode                bool     `protobuf:"varint,28,opt,name=strict_mode,json=strictMode,proto3" json:"strict_mode,omitempty"`
+	MinWeightDeviationTrigger string   `protobuf:"bytes,29,opt,name=min_weight_deviation_trigger,json=minWeightDeviationTrigger,proto3" json:"min_weight_deviation_trigger,omitempty"`
+	LotSelection              string   `protobuf:"bytes,30,opt,name=lot_selection,json=lotSelection,proto3" json:"lot_selection,omitempty"`
+	ScaleLotUnits             bool     `protobuf:"varint,31,opt,name=scale_lot_units,json=scaleLotUnits,proto3" json:"scale_lot_units,omitempty"`
+	MaxPriceAgeDays           string   `protobuf:"bytes,32,opt,name=max_price_age_days,json=maxPriceAgeDays,proto3" json:"max_price_age_days,omitempty"`
+	Phase1Order               string   `protobuf:"bytes,33,opt,name=phase1_order,json=phase1Order,proto3" json:"phase1_order,omitempty"`
+	FullRedemptionTolerance   string   `protobuf:"bytes,34,opt,name=full_redemption_tolerance,json=fullRedemptionTolerance,proto3" json:"full_redemption_tolerance,omitempty"`
+	AllowMissingPrice         bool     `protobuf:"varint,35,opt,name=allow_missing_price,json=allowMissingPrice,proto3" json:"allow_missing_price,omitempty"`
+	MissingPricePolicy        string   `protobuf:"bytes,36,opt,name=missing_price_policy,json=missingPricePolicy,proto3" json:"missing_price_policy,omitempty"`
+	// Accepted for parity with /split; the gRPC response is always SplitResponse.
+	RequestVersion         string            `protobuf:"bytes,37,opt,name=request_version,json=requestVersion,proto3" json:"request_version,omitempty"`
+	IncludePostTrade       bool              `protobuf:"varint,38,opt,name=include_post_trade,json=includePostTrade,proto3" json:"include_post_trade,omitempty"`
+	IncludeWeights         bool              `protobuf:"varint,39,opt,name=include_weights,json=includeWeights,proto3" json:"include_weights,omitempty"`
+	WeightDecimalPrecision string            `protobuf:"bytes,40,opt,name=weight_decimal_precision,json=weightDecimalPrecision,proto3" json:"weight_decimal_precision,omitempty"`
+	Currency               string            `protobuf:"bytes,41,opt,name=currency,proto3" json:"currency,omitempty"`
+	IgnoreCurrentHoldings  bool              `protobuf:"varint,42,opt,name=ignore_current_holdings,json=ignoreCurrentHoldings,proto3" json:"ignore_current_holdings,omitempty"`
+	WeightPrecision        string            `protobuf:"bytes,43,opt,name=weight_precision,json=weightPrecision,proto3" json:"weight_precision,omitempty"`
+	IncludeHolds           bool              `protobuf:"varint,44,opt,name=include_holds,json=includeHolds,proto3" json:"include_holds,omitempty"`
+	Quiet                  bool              `protobuf:"varint,46,opt,name=quiet,proto3" json:"quiet,omitempty"`
+	SeverityOverrides      map[string]string `protobuf:"bytes,47,rep,name=severity_overrides,json=severityOverrides,proto3" json:"severity_overrides,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RecomputeHoldingValues bool              `protobuf:"varint,48,opt,name=recompute_holding_values,json=recomputeHoldingValues,proto3" json:"recompute_holding_values,omitempty"`
+	ClientReference        string            `protobuf:"bytes,49,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	ConsolidationThreshold string            `protobuf:"bytes,45,opt,name=consolidation_threshold,json=consolidationThreshold,proto3" json:"consolidation_threshold,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SplitRequest) Reset() {
+	*x = SplitRequest{}
+	mi := &file_smart_splitter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitRequest) ProtoMessage() {}
+
+func (x *SplitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitRequest.ProtoReflect.Descriptor instead.
+func (*SplitRequest) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SplitRequest) GetAmountDecimalPrecision() string {
+	if x != nil {
+		return x.AmountDecimalPrecision
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetUnitDecimalPrecision() string {
+	if x != nil {
+		return x.UnitDecimalPrecision
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetVolatilityBuffer() string {
+	if x != nil {
+		return x.VolatilityBuffer
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetMinTradeAmt() string {
+	if x != nil {
+		return x.MinTradeAmt
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetZeroOnMinViolation() bool {
+	if x != nil {
+		return x.ZeroOnMinViolation
+	}
+	return false
+}
+
+func (x *SplitRequest) GetResidualPolicy() string {
+	if x != nil {
+		return x.ResidualPolicy
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetAllocationStrategy() string {
+	if x != nil {
+		return x.AllocationStrategy
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetAllocationMode() string {
+	if x != nil {
+		return x.AllocationMode
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetIncludeEffectiveWeights() bool {
+	if x != nil {
+		return x.IncludeEffectiveWeights
+	}
+	return false
+}
+
+func (x *SplitRequest) GetLockCurrentHoldings() bool {
+	if x != nil {
+		return x.LockCurrentHoldings
+	}
+	return false
+}
+
+func (x *SplitRequest) GetGoals() []*GoalProto {
+	if x != nil {
+		return x.Goals
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetRepairObjective() string {
+	if x != nil {
+		return x.RepairObjective
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetMinProgressGuard() bool {
+	if x != nil {
+		return x.MinProgressGuard
+	}
+	return false
+}
+
+func (x *SplitRequest) GetTieBreak() string {
+	if x != nil {
+		return x.TieBreak
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetZeroOutMaxWeight() string {
+	if x != nil {
+		return x.ZeroOutMaxWeight
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetNormalizeWeights() bool {
+	if x != nil {
+		return x.NormalizeWeights
+	}
+	return false
+}
+
+func (x *SplitRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
+func (x *SplitRequest) GetWhatIfAmounts() []string {
+	if x != nil {
+		return x.WhatIfAmounts
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetShrinkageCoefficient() string {
+	if x != nil {
+		return x.ShrinkageCoefficient
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetAutoFullRedemption() bool {
+	if x != nil {
+		return x.AutoFullRedemption
+	}
+	return false
+}
+
+func (x *SplitRequest) GetRepairStrategy() string {
+	if x != nil {
+		return x.RepairStrategy
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetPageSize() string {
+	if x != nil {
+		return x.PageSize
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetMinHoldingPolicy() string {
+	if x != nil {
+		return x.MinHoldingPolicy
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetRebuildBigRedemption() bool {
+	if x != nil {
+		return x.RebuildBigRedemption
+	}
+	return false
+}
+
+func (x *SplitRequest) GetProceedsMode() string {
+	if x != nil {
+		return x.ProceedsMode
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetRedemptionMinimumBasis() string {
+	if x != nil {
+		return x.RedemptionMinimumBasis
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetSortOutputByTicker() bool {
+	if x != nil {
+		return x.SortOutputByTicker
+	}
+	return false
+}
+
+func (x *SplitRequest) GetStrictMode() bool {
+	if x != nil {
+		return x.StrictMode
+	}
+	return false
+}
+
+func (x *SplitRequest) GetMinWeightDeviationTrigger() string {
+	if x != nil {
+		return x.MinWeightDeviationTrigger
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetLotSelection() string {
+	if x != nil {
+		return x.LotSelection
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetScaleLotUnits() bool {
+	if x != nil {
+		return x.ScaleLotUnits
+	}
+	return false
+}
+
+func (x *SplitRequest) GetMaxPriceAgeDays() string {
+	if x != nil {
+		return x.MaxPriceAgeDays
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetPhase1Order() string {
+	if x != nil {
+		return x.Phase1Order
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetFullRedemptionTolerance() string {
+	if x != nil {
+		return x.FullRedemptionTolerance
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetAllowMissingPrice() bool {
+	if x != nil {
+		return x.AllowMissingPrice
+	}
+	return false
+}
+
+func (x *SplitRequest) GetMissingPricePolicy() string {
+	if x != nil {
+		return x.MissingPricePolicy
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetRequestVersion() string {
+	if x != nil {
+		return x.RequestVersion
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetIncludePostTrade() bool {
+	if x != nil {
+		return x.IncludePostTrade
+	}
+	return false
+}
+
+func (x *SplitRequest) GetIncludeWeights() bool {
+	if x != nil {
+		return x.IncludeWeights
+	}
+	return false
+}
+
+func (x *SplitRequest) GetWeightDecimalPrecision() string {
+	if x != nil {
+		return x.WeightDecimalPrecision
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetIgnoreCurrentHoldings() bool {
+	if x != nil {
+		return x.IgnoreCurrentHoldings
+	}
+	return false
+}
+
+func (x *SplitRequest) GetWeightPrecision() string {
+	if x != nil {
+		return x.WeightPrecision
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetIncludeHolds() bool {
+	if x != nil {
+		return x.IncludeHolds
+	}
+	return false
+}
+
+func (x *SplitRequest) GetQuiet() bool {
+	if x != nil {
+		return x.Quiet
+	}
+	return false
+}
+
+func (x *SplitRequest) GetSeverityOverrides() map[string]string {
+	if x != nil {
+		return x.SeverityOverrides
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetRecomputeHoldingValues() bool {
+	if x != nil {
+		return x.RecomputeHoldingValues
+	}
+	return false
+}
+
+func (x *SplitRequest) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+func (x *SplitRequest) GetConsolidationThreshold() string {
+	if x != nil {
+		return x.ConsolidationThreshold
+	}
+	return ""
+}
+
+type GoalProto struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	GoalId                string                 `protobuf:"bytes,1,opt,name=goal_id,json=goalId,proto3" json:"goal_id,omitempty"`
+	GoalDetails           []*HoldingProto        `protobuf:"bytes,2,rep,name=goal_details,json=goalDetails,proto3" json:"goal_details,omitempty"`
+	OrderAmount           string                 `protobuf:"bytes,3,opt,name=order_amount,json=orderAmount,proto3" json:"order_amount,omitempty"`
+	OrderType             string                 `protobuf:"bytes,4,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	ModelPortfolioId      string                 `protobuf:"bytes,5,opt,name=model_portfolio_id,json=modelPortfolioId,proto3" json:"model_portfolio_id,omitempty"`
+	ModelPortfolioDetails []*ModelItemProto      `protobuf:"bytes,6,rep,name=model_portfolio_details,json=modelPortfolioDetails,proto3" json:"model_portfolio_details,omitempty"`
+	MaxTrades             string                 `protobuf:"bytes,7,opt,name=max_trades,json=maxTrades,proto3" json:"max_trades,omitempty"`
+	MinTradeAmt           string                 `protobuf:"bytes,8,opt,name=min_trade_amt,json=minTradeAmt,proto3" json:"min_trade_amt,omitempty"`
+	AllocationStrategy    string                 `protobuf:"bytes,9,opt,name=allocation_strategy,json=allocationStrategy,proto3" json:"allocation_strategy,omitempty"`
+	MaxRedemptionPct      string                 `protobuf:"bytes,10,opt,name=max_redemption_pct,json=maxRedemptionPct,proto3" json:"max_redemption_pct,omitempty"`
+	OrderUnit             string                 `protobuf:"bytes,11,opt,name=order_unit,json=orderUnit,proto3" json:"order_unit,omitempty"`
+	KeepUnmodeled         bool                   `protobuf:"varint,12,opt,name=keep_unmodeled,json=keepUnmodeled,proto3" json:"keep_unmodeled,omitempty"`
+	VolatilityBuffer      string                 `protobuf:"bytes,13,opt,name=volatility_buffer,json=volatilityBuffer,proto3" json:"volatility_buffer,omitempty"`
+	CashBalance           string                 `protobuf:"bytes,14,opt,name=cash_balance,json=cashBalance,proto3" json:"cash_balance,omitempty"`
+	TargetDate            string                 `protobuf:"bytes,15,opt,name=target_date,json=targetDate,proto3" json:"target_date,omitempty"`
+	GlidePath             []*GlidePathPointProto `protobuf:"bytes,16,rep,name=glide_path,json=glidePath,proto3" json:"glide_path,omitempty"`
+	CashOut               bool                   `protobuf:"varint,17,opt,name=cash_out,json=cashOut,proto3" json:"cash_out,omitempty"`
+	Currency              string                 `protobuf:"bytes,18,opt,name=currency,proto3" json:"currency,omitempty"`
+	ClientReference       string                 `protobuf:"bytes,19,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *GoalProto) Reset() {
+	*x = GoalProto{}
+	mi := &file_smart_splitter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalProto) ProtoMessage() {}
+
+func (x *GoalProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalProto.ProtoReflect.Descriptor instead.
+func (*GoalProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GoalProto) GetGoalId() string {
+	if x != nil {
+		return x.GoalId
+	}
+	return ""
+}
+
+func (x *GoalProto) GetGoalDetails() []*HoldingProto {
+	if x != nil {
+		return x.GoalDetails
+	}
+	return nil
+}
+
+func (x *GoalProto) GetOrderAmount() string {
+	if x != nil {
+		return x.OrderAmount
+	}
+	return ""
+}
+
+func (x *GoalProto) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *GoalProto) GetModelPortfolioId() string {
+	if x != nil {
+		return x.ModelPortfolioId
+	}
+	return ""
+}
+
+func (x *GoalProto) GetModelPortfolioDetails() []*ModelItemProto {
+	if x != nil {
+		return x.ModelPortfolioDetails
+	}
+	return nil
+}
+
+func (x *GoalProto) GetMaxTrades() string {
+	if x != nil {
+		return x.MaxTrades
+	}
+	return ""
+}
+
+func (x *GoalProto) GetMinTradeAmt() string {
+	if x != nil {
+		return x.MinTradeAmt
+	}
+	return ""
+}
+
+func (x *GoalProto) GetAllocationStrategy() string {
+	if x != nil {
+		return x.AllocationStrategy
+	}
+	return ""
+}
+
+func (x *GoalProto) GetMaxRedemptionPct() string {
+	if x != nil {
+		return x.MaxRedemptionPct
+	}
+	return ""
+}
+
+func (x *GoalProto) GetOrderUnit() string {
+	if x != nil {
+		return x.OrderUnit
+	}
+	return ""
+}
+
+func (x *GoalProto) GetKeepUnmodeled() bool {
+	if x != nil {
+		return x.KeepUnmodeled
+	}
+	return false
+}
+
+func (x *GoalProto) GetVolatilityBuffer() string {
+	if x != nil {
+		return x.VolatilityBuffer
+	}
+	return ""
+}
+
+func (x *GoalProto) GetCashBalance() string {
+	if x != nil {
+		return x.CashBalance
+	}
+	return ""
+}
+
+func (x *GoalProto) GetTargetDate() string {
+	if x != nil {
+		return x.TargetDate
+	}
+	return ""
+}
+
+func (x *GoalProto) GetGlidePath() []*GlidePathPointProto {
+	if x != nil {
+		return x.GlidePath
+	}
+	return nil
+}
+
+func (x *GoalProto) GetCashOut() bool {
+	if x != nil {
+		return x.CashOut
+	}
+	return false
+}
+
+func (x *GoalProto) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GoalProto) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+type GlidePathPointProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	YearsToTarget string                 `protobuf:"bytes,1,opt,name=years_to_target,json=yearsToTarget,proto3" json:"years_to_target,omitempty"`
+	EquityWeight  string                 `protobuf:"bytes,2,opt,name=equity_weight,json=equityWeight,proto3" json:"equity_weight,omitempty"`
+	BondWeight    string                 `protobuf:"bytes,3,opt,name=bond_weight,json=bondWeight,proto3" json:"bond_weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GlidePathPointProto) Reset() {
+	*x = GlidePathPointProto{}
+	mi := &file_smart_splitter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GlidePathPointProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlidePathPointProto) ProtoMessage() {}
+
+func (x *GlidePathPointProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlidePathPointProto.ProtoReflect.Descriptor instead.
+func (*GlidePathPointProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GlidePathPointProto) GetYearsToTarget() string {
+	if x != nil {
+		return x.YearsToTarget
+	}
+	return ""
+}
+
+func (x *GlidePathPointProto) GetEquityWeight() string {
+	if x != nil {
+		return x.EquityWeight
+	}
+	return ""
+}
+
+func (x *GlidePathPointProto) GetBondWeight() string {
+	if x != nil {
+		return x.BondWeight
+	}
+	return ""
+}
+
+type HoldingProto struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Ticker                    string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Units                     string                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	MarketPrice               string                 `protobuf:"bytes,3,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
+	Value                     string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	PendingValue              string                 `protobuf:"bytes,5,opt,name=pending_value,json=pendingValue,proto3" json:"pending_value,omitempty"`
+	PendingUnits              string                 `protobuf:"bytes,6,opt,name=pending_units,json=pendingUnits,proto3" json:"pending_units,omitempty"`
+	MinInitialInvestmentAmt   string                 `protobuf:"bytes,7,opt,name=min_initial_investment_amt,json=minInitialInvestmentAmt,proto3" json:"min_initial_investment_amt,omitempty"`
+	MinInitialInvestmentUnits string                 `protobuf:"bytes,8,opt,name=min_initial_investment_units,json=minInitialInvestmentUnits,proto3" json:"min_initial_investment_units,omitempty"`
+	MinTopupAmt               string                 `protobuf:"bytes,9,opt,name=min_topup_amt,json=minTopupAmt,proto3" json:"min_topup_amt,omitempty"`
+	MinTopupUnits             string                 `protobuf:"bytes,10,opt,name=min_topup_units,json=minTopupUnits,proto3" json:"min_topup_units,omitempty"`
+	MinRedemptionAmt          string                 `protobuf:"bytes,11,opt,name=min_redemption_amt,json=minRedemptionAmt,proto3" json:"min_redemption_amt,omitempty"`
+	MinRedemptionUnits        string                 `protobuf:"bytes,12,opt,name=min_redemption_units,json=minRedemptionUnits,proto3" json:"min_redemption_units,omitempty"`
+	MinHoldingAmt             string                 `protobuf:"bytes,13,opt,name=min_holding_amt,json=minHoldingAmt,proto3" json:"min_holding_amt,omitempty"`
+	MinHoldingUnits           string                 `protobuf:"bytes,14,opt,name=min_holding_units,json=minHoldingUnits,proto3" json:"min_holding_units,omitempty"`
+	TransactionFee            string                 `protobuf:"bytes,15,opt,name=transaction_fee,json=transactionFee,proto3" json:"transaction_fee,omitempty"`
+	RedemptionFee             string                 `protobuf:"bytes,16,opt,name=redemption_fee,json=redemptionFee,proto3" json:"redemption_fee,omitempty"`
+	WithholdingTaxRate        string                 `protobuf:"bytes,17,opt,name=withholding_tax_rate,json=withholdingTaxRate,proto3" json:"withholding_tax_rate,omitempty"`
+	AverageCost               string                 `protobuf:"bytes,18,opt,name=average_cost,json=averageCost,proto3" json:"average_cost,omitempty"`
+	TotalCost                 string                 `protobuf:"bytes,19,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	Lots                      []*LotProto            `protobuf:"bytes,20,rep,name=lots,proto3" json:"lots,omitempty"`
+	Keep                      bool                   `protobuf:"varint,21,opt,name=keep,proto3" json:"keep,omitempty"`
+	BlockedUnits              string                 `protobuf:"bytes,22,opt,name=blocked_units,json=blockedUnits,proto3" json:"blocked_units,omitempty"`
+	RedemptionPercent         string                 `protobuf:"bytes,23,opt,name=redemption_percent,json=redemptionPercent,proto3" json:"redemption_percent,omitempty"`
+	HoldingSince              string                 `protobuf:"bytes,24,opt,name=holding_since,json=holdingSince,proto3" json:"holding_since,omitempty"`
+	ForceInitialMinimums      bool                   `protobuf:"varint,25,opt,name=force_initial_minimums,json=forceInitialMinimums,proto3" json:"force_initial_minimums,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *HoldingProto) Reset() {
+	*x = HoldingProto{}
+	mi := &file_smart_splitter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldingProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldingProto) ProtoMessage() {}
+
+func (x *HoldingProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldingProto.ProtoReflect.Descriptor instead.
+func (*HoldingProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HoldingProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMarketPrice() string {
+	if x != nil {
+		return x.MarketPrice
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetPendingValue() string {
+	if x != nil {
+		return x.PendingValue
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetPendingUnits() string {
+	if x != nil {
+		return x.PendingUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinInitialInvestmentAmt() string {
+	if x != nil {
+		return x.MinInitialInvestmentAmt
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinInitialInvestmentUnits() string {
+	if x != nil {
+		return x.MinInitialInvestmentUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinTopupAmt() string {
+	if x != nil {
+		return x.MinTopupAmt
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinTopupUnits() string {
+	if x != nil {
+		return x.MinTopupUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinRedemptionAmt() string {
+	if x != nil {
+		return x.MinRedemptionAmt
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinRedemptionUnits() string {
+	if x != nil {
+		return x.MinRedemptionUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinHoldingAmt() string {
+	if x != nil {
+		return x.MinHoldingAmt
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetMinHoldingUnits() string {
+	if x != nil {
+		return x.MinHoldingUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetTransactionFee() string {
+	if x != nil {
+		return x.TransactionFee
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetRedemptionFee() string {
+	if x != nil {
+		return x.RedemptionFee
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetWithholdingTaxRate() string {
+	if x != nil {
+		return x.WithholdingTaxRate
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetAverageCost() string {
+	if x != nil {
+		return x.AverageCost
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetLots() []*LotProto {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+func (x *HoldingProto) GetKeep() bool {
+	if x != nil {
+		return x.Keep
+	}
+	return false
+}
+
+func (x *HoldingProto) GetBlockedUnits() string {
+	if x != nil {
+		return x.BlockedUnits
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetRedemptionPercent() string {
+	if x != nil {
+		return x.RedemptionPercent
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetHoldingSince() string {
+	if x != nil {
+		return x.HoldingSince
+	}
+	return ""
+}
+
+func (x *HoldingProto) GetForceInitialMinimums() bool {
+	if x != nil {
+		return x.ForceInitialMinimums
+	}
+	return false
+}
+
+type LotProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Units           string                 `protobuf:"bytes,1,opt,name=units,proto3" json:"units,omitempty"`
+	CostPerUnit     string                 `protobuf:"bytes,2,opt,name=cost_per_unit,json=costPerUnit,proto3" json:"cost_per_unit,omitempty"`
+	AcquisitionDate string                 `protobuf:"bytes,3,opt,name=acquisition_date,json=acquisitionDate,proto3" json:"acquisition_date,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LotProto) Reset() {
+	*x = LotProto{}
+	mi := &file_smart_splitter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LotProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotProto) ProtoMessage() {}
+
+func (x *LotProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotProto.ProtoReflect.Descriptor instead.
+func (*LotProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LotProto) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *LotProto) GetCostPerUnit() string {
+	if x != nil {
+		return x.CostPerUnit
+	}
+	return ""
+}
+
+func (x *LotProto) GetAcquisitionDate() string {
+	if x != nil {
+		return x.AcquisitionDate
+	}
+	return ""
+}
+
+type ModelItemProto struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Ticker                    string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Weight                    string                 `protobuf:"bytes,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	MarketPrice               string                 `protobuf:"bytes,3,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
+	ExchangeRate              string                 `protobuf:"bytes,4,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`
+	MinInitialInvestmentAmt   string                 `protobuf:"bytes,5,opt,name=min_initial_investment_amt,json=minInitialInvestmentAmt,proto3" json:"min_initial_investment_amt,omitempty"`
+	MinInitialInvestmentUnits string                 `protobuf:"bytes,6,opt,name=min_initial_investment_units,json=minInitialInvestmentUnits,proto3" json:"min_initial_investment_units,omitempty"`
+	MinTopupAmt               string                 `protobuf:"bytes,7,opt,name=min_topup_amt,json=minTopupAmt,proto3" json:"min_topup_amt,omitempty"`
+	MinTopupUnits             string                 `protobuf:"bytes,8,opt,name=min_topup_units,json=minTopupUnits,proto3" json:"min_topup_units,omitempty"`
+	MinRedemptionAmt          string                 `protobuf:"bytes,9,opt,name=min_redemption_amt,json=minRedemptionAmt,proto3" json:"min_redemption_amt,omitempty"`
+	MinRedemptionUnits        string                 `protobuf:"bytes,10,opt,name=min_redemption_units,json=minRedemptionUnits,proto3" json:"min_redemption_units,omitempty"`
+	MinHoldingAmt             string                 `protobuf:"bytes,11,opt,name=min_holding_amt,json=minHoldingAmt,proto3" json:"min_holding_amt,omitempty"`
+	MinHoldingUnits           string                 `protobuf:"bytes,12,opt,name=min_holding_units,json=minHoldingUnits,proto3" json:"min_holding_units,omitempty"`
+	TransactionFee            string                 `protobuf:"bytes,13,opt,name=transaction_fee,json=transactionFee,proto3" json:"transaction_fee,omitempty"`
+	Priority                  string                 `protobuf:"bytes,14,opt,name=priority,proto3" json:"priority,omitempty"`
+	ProtectFromZeroOut        bool                   `protobuf:"varint,15,opt,name=protect_from_zero_out,json=protectFromZeroOut,proto3" json:"protect_from_zero_out,omitempty"`
+	AllocationFloor           string                 `protobuf:"bytes,16,opt,name=allocation_floor,json=allocationFloor,proto3" json:"allocation_floor,omitempty"`
+	RedemptionFee             string                 `protobuf:"bytes,17,opt,name=redemption_fee,json=redemptionFee,proto3" json:"redemption_fee,omitempty"`
+	WithholdingTaxRate        string                 `protobuf:"bytes,18,opt,name=withholding_tax_rate,json=withholdingTaxRate,proto3" json:"withholding_tax_rate,omitempty"`
+	PriceDate                 string                 `protobuf:"bytes,19,opt,name=price_date,json=priceDate,proto3" json:"price_date,omitempty"`
+	AssetClass                string                 `protobuf:"bytes,20,opt,name=asset_class,json=assetClass,proto3" json:"asset_class,omitempty"`
+	MinHoldingPeriodDays      string                 `protobuf:"bytes,21,opt,name=min_holding_period_days,json=minHoldingPeriodDays,proto3" json:"min_holding_period_days,omitempty"`
+	BlockedFromBuy            bool                   `protobuf:"varint,22,opt,name=blocked_from_buy,json=blockedFromBuy,proto3" json:"blocked_from_buy,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *ModelItemProto) Reset() {
+	*x = ModelItemProto{}
+	mi := &file_smart_splitter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelItemProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelItemProto) ProtoMessage() {}
+
+func (x *ModelItemProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelItemProto.ProtoReflect.Descriptor instead.
+func (*ModelItemProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ModelItemProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetWeight() string {
+	if x != nil {
+		return x.Weight
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMarketPrice() string {
+	if x != nil {
+		return x.MarketPrice
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetExchangeRate() string {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinInitialInvestmentAmt() string {
+	if x != nil {
+		return x.MinInitialInvestmentAmt
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinInitialInvestmentUnits() string {
+	if x != nil {
+		return x.MinInitialInvestmentUnits
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinTopupAmt() string {
+	if x != nil {
+		return x.MinTopupAmt
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinTopupUnits() string {
+	if x != nil {
+		return x.MinTopupUnits
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinRedemptionAmt() string {
+	if x != nil {
+		return x.MinRedemptionAmt
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinRedemptionUnits() string {
+	if x != nil {
+		return x.MinRedemptionUnits
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinHoldingAmt() string {
+	if x != nil {
+		return x.MinHoldingAmt
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinHoldingUnits() string {
+	if x != nil {
+		return x.MinHoldingUnits
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetTransactionFee() string {
+	if x != nil {
+		return x.TransactionFee
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetProtectFromZeroOut() bool {
+	if x != nil {
+		return x.ProtectFromZeroOut
+	}
+	return false
+}
+
+func (x *ModelItemProto) GetAllocationFloor() string {
+	if x != nil {
+		return x.AllocationFloor
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetRedemptionFee() string {
+	if x != nil {
+		return x.RedemptionFee
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetWithholdingTaxRate() string {
+	if x != nil {
+		return x.WithholdingTaxRate
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetPriceDate() string {
+	if x != nil {
+		return x.PriceDate
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetAssetClass() string {
+	if x != nil {
+		return x.AssetClass
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetMinHoldingPeriodDays() string {
+	if x != nil {
+		return x.MinHoldingPeriodDays
+	}
+	return ""
+}
+
+func (x *ModelItemProto) GetBlockedFromBuy() bool {
+	if x != nil {
+		return x.BlockedFromBuy
+	}
+	return false
+}
+
+type SplitResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Results         []*GoalResultProto     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NextCursor      string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	Currency        string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Warnings        []*WarningProto        `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	ClientReference string                 `protobuf:"bytes,5,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SplitResponse) Reset() {
+	*x = SplitResponse{}
+	mi := &file_smart_splitter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitResponse) ProtoMessage() {}
+
+func (x *SplitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitResponse.ProtoReflect.Descriptor instead.
+func (*SplitResponse) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SplitResponse) GetResults() []*GoalResultProto {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SplitResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *SplitResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SplitResponse) GetWarnings() []*WarningProto {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *SplitResponse) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+type WarningProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Ticker        string                 `protobuf:"bytes,3,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WarningProto) Reset() {
+	*x = WarningProto{}
+	mi := &file_smart_splitter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WarningProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WarningProto) ProtoMessage() {}
+
+func (x *WarningProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WarningProto.ProtoReflect.Descriptor instead.
+func (*WarningProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WarningProto) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *WarningProto) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WarningProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+type GoalResultProto struct {
+	state                 protoimpl.MessageState    `protogen:"open.v1"`
+	GoalId                string                    `protobuf:"bytes,1,opt,name=goal_id,json=goalId,proto3" json:"goal_id,omitempty"`
+	TransactionType       string                    `protobuf:"bytes,2,opt,name=transaction_type,json=transactionType,proto3" json:"transaction_type,omitempty"`
+	TransactionDetails    []*TransactionDetailProto `protobuf:"bytes,3,rep,name=transaction_details,json=transactionDetails,proto3" json:"transaction_details,omitempty"`
+	EffectiveWeights      []*EffectiveWeightProto   `protobuf:"bytes,4,rep,name=effective_weights,json=effectiveWeights,proto3" json:"effective_weights,omitempty"`
+	Debug                 *GoalDebugProto           `protobuf:"bytes,5,opt,name=debug,proto3" json:"debug,omitempty"`
+	Error                 *TradeErrorProto          `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	TotalPortfolioValue   string                    `protobuf:"bytes,7,opt,name=total_portfolio_value,json=totalPortfolioValue,proto3" json:"total_portfolio_value,omitempty"`
+	EffectiveOrderAmount  string                    `protobuf:"bytes,8,opt,name=effective_order_amount,json=effectiveOrderAmount,proto3" json:"effective_order_amount,omitempty"`
+	WhatIfResults         []*WhatIfResultProto      `protobuf:"bytes,9,rep,name=what_if_results,json=whatIfResults,proto3" json:"what_if_results,omitempty"`
+	UnfulfilledAmount     string                    `protobuf:"bytes,10,opt,name=unfulfilled_amount,json=unfulfilledAmount,proto3" json:"unfulfilled_amount,omitempty"`
+	TradeCount            int32                     `protobuf:"varint,11,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	ErrorCount            int32                     `protobuf:"varint,12,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	EstimatedTax          string                    `protobuf:"bytes,13,opt,name=estimated_tax,json=estimatedTax,proto3" json:"estimated_tax,omitempty"`
+	NetAfterTax           string                    `protobuf:"bytes,14,opt,name=net_after_tax,json=netAfterTax,proto3" json:"net_after_tax,omitempty"`
+	EstimatedRealizedGain string                    `protobuf:"bytes,15,opt,name=estimated_realized_gain,json=estimatedRealizedGain,proto3" json:"estimated_realized_gain,omitempty"`
+	FullRedemptionExcess  string                    `protobuf:"bytes,16,opt,name=full_redemption_excess,json=fullRedemptionExcess,proto3" json:"full_redemption_excess,omitempty"`
+	PostTradeHoldings     []*PostTradeHoldingProto  `protobuf:"bytes,17,rep,name=post_trade_holdings,json=postTradeHoldings,proto3" json:"post_trade_holdings,omitempty"`
+	PostTradeTotal        string                    `protobuf:"bytes,18,opt,name=post_trade_total,json=postTradeTotal,proto3" json:"post_trade_total,omitempty"`
+	ViolationSummary      []*ViolationRecordProto   `protobuf:"bytes,19,rep,name=violation_summary,json=violationSummary,proto3" json:"violation_summary,omitempty"`
+	Summary               *GoalSummaryProto         `protobuf:"bytes,20,opt,name=summary,proto3" json:"summary,omitempty"`
+	SkippedProducts       []*SkippedProductProto    `protobuf:"bytes,21,rep,name=skipped_products,json=skippedProducts,proto3" json:"skipped_products,omitempty"`
+	Warnings              []*WarningProto           `protobuf:"bytes,22,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	ModelPortfolioId      string                    `protobuf:"bytes,23,opt,name=model_portfolio_id,json=modelPortfolioId,proto3" json:"model_portfolio_id,omitempty"`
+	OrderType             string                    `protobuf:"bytes,24,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	OrderAmount           string                    `protobuf:"bytes,25,opt,name=order_amount,json=orderAmount,proto3" json:"order_amount,omitempty"`
+	ClientReference       string                    `protobuf:"bytes,26,opt,name=client_reference,json=clientReference,proto3" json:"client_reference,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *GoalResultProto) Reset() {
+	*x = GoalResultProto{}
+	mi := &file_smart_splitter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalResultProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalResultProto) ProtoMessage() {}
+
+func (x *GoalResultProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalResultProto.ProtoReflect.Descriptor instead.
+func (*GoalResultProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GoalResultProto) GetGoalId() string {
+	if x != nil {
+		return x.GoalId
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetTransactionType() string {
+	if x != nil {
+		return x.TransactionType
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetTransactionDetails() []*TransactionDetailProto {
+	if x != nil {
+		return x.TransactionDetails
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetEffectiveWeights() []*EffectiveWeightProto {
+	if x != nil {
+		return x.EffectiveWeights
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetDebug() *GoalDebugProto {
+	if x != nil {
+		return x.Debug
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetError() *TradeErrorProto {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetTotalPortfolioValue() string {
+	if x != nil {
+		return x.TotalPortfolioValue
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetEffectiveOrderAmount() string {
+	if x != nil {
+		return x.EffectiveOrderAmount
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetWhatIfResults() []*WhatIfResultProto {
+	if x != nil {
+		return x.WhatIfResults
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetUnfulfilledAmount() string {
+	if x != nil {
+		return x.UnfulfilledAmount
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetTradeCount() int32 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *GoalResultProto) GetErrorCount() int32 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+func (x *GoalResultProto) GetEstimatedTax() string {
+	if x != nil {
+		return x.EstimatedTax
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetNetAfterTax() string {
+	if x != nil {
+		return x.NetAfterTax
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetEstimatedRealizedGain() string {
+	if x != nil {
+		return x.EstimatedRealizedGain
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetFullRedemptionExcess() string {
+	if x != nil {
+		return x.FullRedemptionExcess
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetPostTradeHoldings() []*PostTradeHoldingProto {
+	if x != nil {
+		return x.PostTradeHoldings
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetPostTradeTotal() string {
+	if x != nil {
+		return x.PostTradeTotal
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetViolationSummary() []*ViolationRecordProto {
+	if x != nil {
+		return x.ViolationSummary
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetSummary() *GoalSummaryProto {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetSkippedProducts() []*SkippedProductProto {
+	if x != nil {
+		return x.SkippedProducts
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetWarnings() []*WarningProto {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *GoalResultProto) GetModelPortfolioId() string {
+	if x != nil {
+		return x.ModelPortfolioId
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetOrderAmount() string {
+	if x != nil {
+		return x.OrderAmount
+	}
+	return ""
+}
+
+func (x *GoalResultProto) GetClientReference() string {
+	if x != nil {
+		return x.ClientReference
+	}
+	return ""
+}
+
+type GoalSummaryProto struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalGrossValue    string                 `protobuf:"bytes,1,opt,name=total_gross_value,json=totalGrossValue,proto3" json:"total_gross_value,omitempty"`
+	TotalNetValue      string                 `protobuf:"bytes,2,opt,name=total_net_value,json=totalNetValue,proto3" json:"total_net_value,omitempty"`
+	TotalEstimatedFees string                 `protobuf:"bytes,3,opt,name=total_estimated_fees,json=totalEstimatedFees,proto3" json:"total_estimated_fees,omitempty"`
+	UnallocatedAmount  string                 `protobuf:"bytes,4,opt,name=unallocated_amount,json=unallocatedAmount,proto3" json:"unallocated_amount,omitempty"`
+	TradeCount         int32                  `protobuf:"varint,5,opt,name=trade_count,json=tradeCount,proto3" json:"trade_count,omitempty"`
+	ViolationCount     int32                  `protobuf:"varint,6,opt,name=violation_count,json=violationCount,proto3" json:"violation_count,omitempty"`
+	TotalGrossProceeds string                 `protobuf:"bytes,7,opt,name=total_gross_proceeds,json=totalGrossProceeds,proto3" json:"total_gross_proceeds,omitempty"`
+	TotalNetProceeds   string                 `protobuf:"bytes,8,opt,name=total_net_proceeds,json=totalNetProceeds,proto3" json:"total_net_proceeds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GoalSummaryProto) Reset() {
+	*x = GoalSummaryProto{}
+	mi := &file_smart_splitter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalSummaryProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalSummaryProto) ProtoMessage() {}
+
+func (x *GoalSummaryProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalSummaryProto.ProtoReflect.Descriptor instead.
+func (*GoalSummaryProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GoalSummaryProto) GetTotalGrossValue() string {
+	if x != nil {
+		return x.TotalGrossValue
+	}
+	return ""
+}
+
+func (x *GoalSummaryProto) GetTotalNetValue() string {
+	if x != nil {
+		return x.TotalNetValue
+	}
+	return ""
+}
+
+func (x *GoalSummaryProto) GetTotalEstimatedFees() string {
+	if x != nil {
+		return x.TotalEstimatedFees
+	}
+	return ""
+}
+
+func (x *GoalSummaryProto) GetUnallocatedAmount() string {
+	if x != nil {
+		return x.UnallocatedAmount
+	}
+	return ""
+}
+
+func (x *GoalSummaryProto) GetTradeCount() int32 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *GoalSummaryProto) GetViolationCount() int32 {
+	if x != nil {
+		return x.ViolationCount
+	}
+	return 0
+}
+
+func (x *GoalSummaryProto) GetTotalGrossProceeds() string {
+	if x != nil {
+		return x.TotalGrossProceeds
+	}
+	return ""
+}
+
+func (x *GoalSummaryProto) GetTotalNetProceeds() string {
+	if x != nil {
+		return x.TotalNetProceeds
+	}
+	return ""
+}
+
+type SkippedProductProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkippedProductProto) Reset() {
+	*x = SkippedProductProto{}
+	mi := &file_smart_splitter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkippedProductProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkippedProductProto) ProtoMessage() {}
+
+func (x *SkippedProductProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkippedProductProto.ProtoReflect.Descriptor instead.
+func (*SkippedProductProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SkippedProductProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *SkippedProductProto) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *SkippedProductProto) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ViolationRecordProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	RequiredAmt   string                 `protobuf:"bytes,3,opt,name=required_amt,json=requiredAmt,proto3" json:"required_amt,omitempty"`
+	AllocatedAmt  string                 `protobuf:"bytes,4,opt,name=allocated_amt,json=allocatedAmt,proto3" json:"allocated_amt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ViolationRecordProto) Reset() {
+	*x = ViolationRecordProto{}
+	mi := &file_smart_splitter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ViolationRecordProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ViolationRecordProto) ProtoMessage() {}
+
+func (x *ViolationRecordProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ViolationRecordProto.ProtoReflect.Descriptor instead.
+func (*ViolationRecordProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ViolationRecordProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *ViolationRecordProto) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *ViolationRecordProto) GetRequiredAmt() string {
+	if x != nil {
+		return x.RequiredAmt
+	}
+	return ""
+}
+
+func (x *ViolationRecordProto) GetAllocatedAmt() string {
+	if x != nil {
+		return x.AllocatedAmt
+	}
+	return ""
+}
+
+type PostTradeHoldingProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Units         string                 `protobuf:"bytes,3,opt,name=units,proto3" json:"units,omitempty"`
+	Weight        string                 `protobuf:"bytes,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostTradeHoldingProto) Reset() {
+	*x = PostTradeHoldingProto{}
+	mi := &file_smart_splitter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostTradeHoldingProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostTradeHoldingProto) ProtoMessage() {}
+
+func (x *PostTradeHoldingProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostTradeHoldingProto.ProtoReflect.Descriptor instead.
+func (*PostTradeHoldingProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PostTradeHoldingProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *PostTradeHoldingProto) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *PostTradeHoldingProto) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *PostTradeHoldingProto) GetWeight() string {
+	if x != nil {
+		return x.Weight
+	}
+	return ""
+}
+
+type WhatIfResultProto struct {
+	state            protoimpl.MessageState  `protogen:"open.v1"`
+	OrderAmount      string                  `protobuf:"bytes,1,opt,name=order_amount,json=orderAmount,proto3" json:"order_amount,omitempty"`
+	Violations       int32                   `protobuf:"varint,2,opt,name=violations,proto3" json:"violations,omitempty"`
+	Unallocated      string                  `protobuf:"bytes,3,opt,name=unallocated,proto3" json:"unallocated,omitempty"`
+	EffectiveWeights []*EffectiveWeightProto `protobuf:"bytes,4,rep,name=effective_weights,json=effectiveWeights,proto3" json:"effective_weights,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *WhatIfResultProto) Reset() {
+	*x = WhatIfResultProto{}
+	mi := &file_smart_splitter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhatIfResultProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhatIfResultProto) ProtoMessage() {}
+
+func (x *WhatIfResultProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhatIfResultProto.ProtoReflect.Descriptor instead.
+func (*WhatIfResultProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WhatIfResultProto) GetOrderAmount() string {
+	if x != nil {
+		return x.OrderAmount
+	}
+	return ""
+}
+
+func (x *WhatIfResultProto) GetViolations() int32 {
+	if x != nil {
+		return x.Violations
+	}
+	return 0
+}
+
+func (x *WhatIfResultProto) GetUnallocated() string {
+	if x != nil {
+		return x.Unallocated
+	}
+	return ""
+}
+
+func (x *WhatIfResultProto) GetEffectiveWeights() []*EffectiveWeightProto {
+	if x != nil {
+		return x.EffectiveWeights
+	}
+	return nil
+}
+
+type GoalDebugProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RepairObjective string                 `protobuf:"bytes,1,opt,name=repair_objective,json=repairObjective,proto3" json:"repair_objective,omitempty"`
+	SelfCheck       *SelfCheckProto        `protobuf:"bytes,2,opt,name=self_check,json=selfCheck,proto3" json:"self_check,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GoalDebugProto) Reset() {
+	*x = GoalDebugProto{}
+	mi := &file_smart_splitter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoalDebugProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoalDebugProto) ProtoMessage() {}
+
+func (x *GoalDebugProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoalDebugProto.ProtoReflect.Descriptor instead.
+func (*GoalDebugProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GoalDebugProto) GetRepairObjective() string {
+	if x != nil {
+		return x.RepairObjective
+	}
+	return ""
+}
+
+func (x *GoalDebugProto) GetSelfCheck() *SelfCheckProto {
+	if x != nil {
+		return x.SelfCheck
+	}
+	return nil
+}
+
+type SelfCheckProto struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Passed        bool                       `protobuf:"varint,1,opt,name=passed,proto3" json:"passed,omitempty"`
+	Violations    []*InvariantViolationProto `protobuf:"bytes,2,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelfCheckProto) Reset() {
+	*x = SelfCheckProto{}
+	mi := &file_smart_splitter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelfCheckProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelfCheckProto) ProtoMessage() {}
+
+func (x *SelfCheckProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelfCheckProto.ProtoReflect.Descriptor instead.
+func (*SelfCheckProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SelfCheckProto) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *SelfCheckProto) GetViolations() []*InvariantViolationProto {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type InvariantViolationProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invariant     string                 `protobuf:"bytes,1,opt,name=invariant,proto3" json:"invariant,omitempty"`
+	Ticker        string                 `protobuf:"bytes,2,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvariantViolationProto) Reset() {
+	*x = InvariantViolationProto{}
+	mi := &file_smart_splitter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvariantViolationProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvariantViolationProto) ProtoMessage() {}
+
+func (x *InvariantViolationProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvariantViolationProto.ProtoReflect.Descriptor instead.
+func (*InvariantViolationProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InvariantViolationProto) GetInvariant() string {
+	if x != nil {
+		return x.Invariant
+	}
+	return ""
+}
+
+func (x *InvariantViolationProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *InvariantViolationProto) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TransactionDetailProto struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Ticker                string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Direction             string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	Value                 string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Units                 string                 `protobuf:"bytes,4,opt,name=units,proto3" json:"units,omitempty"`
+	Error                 *TradeErrorProto       `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Trace                 *TraceProto            `protobuf:"bytes,6,opt,name=trace,proto3" json:"trace,omitempty"`
+	FullRedemption        bool                   `protobuf:"varint,7,opt,name=full_redemption,json=fullRedemption,proto3" json:"full_redemption,omitempty"`
+	NetProceeds           string                 `protobuf:"bytes,8,opt,name=net_proceeds,json=netProceeds,proto3" json:"net_proceeds,omitempty"`
+	EstimatedTax          string                 `protobuf:"bytes,9,opt,name=estimated_tax,json=estimatedTax,proto3" json:"estimated_tax,omitempty"`
+	NetAfterTax           string                 `protobuf:"bytes,10,opt,name=net_after_tax,json=netAfterTax,proto3" json:"net_after_tax,omitempty"`
+	EstimatedRealizedGain string                 `protobuf:"bytes,11,opt,name=estimated_realized_gain,json=estimatedRealizedGain,proto3" json:"estimated_realized_gain,omitempty"`
+	Lots                  []*LotSaleProto        `protobuf:"bytes,12,rep,name=lots,proto3" json:"lots,omitempty"`
+	ManualInstruction     string                 `protobuf:"bytes,13,opt,name=manual_instruction,json=manualInstruction,proto3" json:"manual_instruction,omitempty"`
+	ModelWeight           string                 `protobuf:"bytes,14,opt,name=model_weight,json=modelWeight,proto3" json:"model_weight,omitempty"`
+	PreTradeWeight        string                 `protobuf:"bytes,15,opt,name=pre_trade_weight,json=preTradeWeight,proto3" json:"pre_trade_weight,omitempty"`
+	PostTradeWeight       string                 `protobuf:"bytes,16,opt,name=post_trade_weight,json=postTradeWeight,proto3" json:"post_trade_weight,omitempty"`
+	Drift                 string                 `protobuf:"bytes,17,opt,name=drift,proto3" json:"drift,omitempty"`
+	Price                 string                 `protobuf:"bytes,18,opt,name=price,proto3" json:"price,omitempty"`
+	HoldReason            string                 `protobuf:"bytes,19,opt,name=hold_reason,json=holdReason,proto3" json:"hold_reason,omitempty"`
+	Errors                []*TradeErrorProto     `protobuf:"bytes,20,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TransactionDetailProto) Reset() {
+	*x = TransactionDetailProto{}
+	mi := &file_smart_splitter_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionDetailProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionDetailProto) ProtoMessage() {}
+
+func (x *TransactionDetailProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionDetailProto.ProtoReflect.Descriptor instead.
+func (*TransactionDetailProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TransactionDetailProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetError() *TradeErrorProto {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *TransactionDetailProto) GetTrace() *TraceProto {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *TransactionDetailProto) GetFullRedemption() bool {
+	if x != nil {
+		return x.FullRedemption
+	}
+	return false
+}
+
+func (x *TransactionDetailProto) GetNetProceeds() string {
+	if x != nil {
+		return x.NetProceeds
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetEstimatedTax() string {
+	if x != nil {
+		return x.EstimatedTax
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetNetAfterTax() string {
+	if x != nil {
+		return x.NetAfterTax
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetEstimatedRealizedGain() string {
+	if x != nil {
+		return x.EstimatedRealizedGain
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetLots() []*LotSaleProto {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+func (x *TransactionDetailProto) GetManualInstruction() string {
+	if x != nil {
+		return x.ManualInstruction
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetModelWeight() string {
+	if x != nil {
+		return x.ModelWeight
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetPreTradeWeight() string {
+	if x != nil {
+		return x.PreTradeWeight
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetPostTradeWeight() string {
+	if x != nil {
+		return x.PostTradeWeight
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetDrift() string {
+	if x != nil {
+		return x.Drift
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetHoldReason() string {
+	if x != nil {
+		return x.HoldReason
+	}
+	return ""
+}
+
+func (x *TransactionDetailProto) GetErrors() []*TradeErrorProto {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type LotSaleProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AcquisitionDate string                 `protobuf:"bytes,1,opt,name=acquisition_date,json=acquisitionDate,proto3" json:"acquisition_date,omitempty"`
+	Units           string                 `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	CostPerUnit     string                 `protobuf:"bytes,3,opt,name=cost_per_unit,json=costPerUnit,proto3" json:"cost_per_unit,omitempty"`
+	RealizedGain    string                 `protobuf:"bytes,4,opt,name=realized_gain,json=realizedGain,proto3" json:"realized_gain,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LotSaleProto) Reset() {
+	*x = LotSaleProto{}
+	mi := &file_smart_splitter_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LotSaleProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LotSaleProto) ProtoMessage() {}
+
+func (x *LotSaleProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LotSaleProto.ProtoReflect.Descriptor instead.
+func (*LotSaleProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LotSaleProto) GetAcquisitionDate() string {
+	if x != nil {
+		return x.AcquisitionDate
+	}
+	return ""
+}
+
+func (x *LotSaleProto) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *LotSaleProto) GetCostPerUnit() string {
+	if x != nil {
+		return x.CostPerUnit
+	}
+	return ""
+}
+
+func (x *LotSaleProto) GetRealizedGain() string {
+	if x != nil {
+		return x.RealizedGain
+	}
+	return ""
+}
+
+type TraceProto struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Phase             string                 `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	CurrentValue      string                 `protobuf:"bytes,2,opt,name=current_value,json=currentValue,proto3" json:"current_value,omitempty"`
+	Ideal             string                 `protobuf:"bytes,3,opt,name=ideal,proto3" json:"ideal,omitempty"`
+	FeeAdjustedGross  string                 `protobuf:"bytes,4,opt,name=fee_adjusted_gross,json=feeAdjustedGross,proto3" json:"fee_adjusted_gross,omitempty"`
+	GrossBeforeRepair string                 `protobuf:"bytes,5,opt,name=gross_before_repair,json=grossBeforeRepair,proto3" json:"gross_before_repair,omitempty"`
+	RepairAction      string                 `protobuf:"bytes,6,opt,name=repair_action,json=repairAction,proto3" json:"repair_action,omitempty"`
+	RepairAdjustment  string                 `protobuf:"bytes,7,opt,name=repair_adjustment,json=repairAdjustment,proto3" json:"repair_adjustment,omitempty"`
+	Final             string                 `protobuf:"bytes,8,opt,name=final,proto3" json:"final,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TraceProto) Reset() {
+	*x = TraceProto{}
+	mi := &file_smart_splitter_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceProto) ProtoMessage() {}
+
+func (x *TraceProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceProto.ProtoReflect.Descriptor instead.
+func (*TraceProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TraceProto) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *TraceProto) GetCurrentValue() string {
+	if x != nil {
+		return x.CurrentValue
+	}
+	return ""
+}
+
+func (x *TraceProto) GetIdeal() string {
+	if x != nil {
+		return x.Ideal
+	}
+	return ""
+}
+
+func (x *TraceProto) GetFeeAdjustedGross() string {
+	if x != nil {
+		return x.FeeAdjustedGross
+	}
+	return ""
+}
+
+func (x *TraceProto) GetGrossBeforeRepair() string {
+	if x != nil {
+		return x.GrossBeforeRepair
+	}
+	return ""
+}
+
+func (x *TraceProto) GetRepairAction() string {
+	if x != nil {
+		return x.RepairAction
+	}
+	return ""
+}
+
+func (x *TraceProto) GetRepairAdjustment() string {
+	if x != nil {
+		return x.RepairAdjustment
+	}
+	return ""
+}
+
+func (x *TraceProto) GetFinal() string {
+	if x != nil {
+		return x.Final
+	}
+	return ""
+}
+
+type TradeErrorProto struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Message        string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	RequiredAmount string                 `protobuf:"bytes,3,opt,name=required_amount,json=requiredAmount,proto3" json:"required_amount,omitempty"`
+	RequiredUnits  string                 `protobuf:"bytes,4,opt,name=required_units,json=requiredUnits,proto3" json:"required_units,omitempty"`
+	ActualAmount   string                 `protobuf:"bytes,5,opt,name=actual_amount,json=actualAmount,proto3" json:"actual_amount,omitempty"`
+	ActualUnits    string                 `protobuf:"bytes,6,opt,name=actual_units,json=actualUnits,proto3" json:"actual_units,omitempty"`
+	Shortfall      string                 `protobuf:"bytes,7,opt,name=shortfall,proto3" json:"shortfall,omitempty"`
+	Severity       string                 `protobuf:"bytes,8,opt,name=severity,proto3" json:"severity,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TradeErrorProto) Reset() {
+	*x = TradeErrorProto{}
+	mi := &file_smart_splitter_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeErrorProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeErrorProto) ProtoMessage() {}
+
+func (x *TradeErrorProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeErrorProto.ProtoReflect.Descriptor instead.
+func (*TradeErrorProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TradeErrorProto) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetRequiredAmount() string {
+	if x != nil {
+		return x.RequiredAmount
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetRequiredUnits() string {
+	if x != nil {
+		return x.RequiredUnits
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetActualAmount() string {
+	if x != nil {
+		return x.ActualAmount
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetActualUnits() string {
+	if x != nil {
+		return x.ActualUnits
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetShortfall() string {
+	if x != nil {
+		return x.Shortfall
+	}
+	return ""
+}
+
+func (x *TradeErrorProto) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+type EffectiveWeightProto struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Ticker         string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	ModelWeight    string                 `protobuf:"bytes,2,opt,name=model_weight,json=modelWeight,proto3" json:"model_weight,omitempty"`
+	AchievedWeight string                 `protobuf:"bytes,3,opt,name=achieved_weight,json=achievedWeight,proto3" json:"achieved_weight,omitempty"`
+	AbsDeviation   string                 `protobuf:"bytes,4,opt,name=abs_deviation,json=absDeviation,proto3" json:"abs_deviation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EffectiveWeightProto) Reset() {
+	*x = EffectiveWeightProto{}
+	mi := &file_smart_splitter_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EffectiveWeightProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectiveWeightProto) ProtoMessage() {}
+
+func (x *EffectiveWeightProto) ProtoReflect() protoreflect.Message {
+	mi := &file_smart_splitter_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectiveWeightProto.ProtoReflect.Descriptor instead.
+func (*EffectiveWeightProto) Descriptor() ([]byte, []int) {
+	return file_smart_splitter_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *EffectiveWeightProto) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *EffectiveWeightProto) GetModelWeight() string {
+	if x != nil {
+		return x.ModelWeight
+	}
+	return ""
+}
+
+func (x *EffectiveWeightProto) GetAchievedWeight() string {
+	if x != nil {
+		return x.AchievedWeight
+	}
+	return ""
+}
+
+func (x *EffectiveWeightProto) GetAbsDeviation() string {
+	if x != nil {
+		return x.AbsDeviation
+	}
+	return ""
+}
+
+var File_smart_splitter_proto protoreflect.FileDescriptor
+
+const file_smart_splitter_proto_rawDesc = "" +
+	"\n" +
+	"\x14smart_splitter.proto\x12\x10smartsplitter.v1\"\xfb\x12\n" +
+	"\fSplitRequest\x128\n" +
+	"\x18amount_decimal_precision\x18\x01 \x01(\tR\x16amountDecimalPrecision\x124\n" +
+	"\x16unit_decimal_precision\x18\x02 \x01(\tR\x14unitDecimalPrecision\x12+\n" +
+	"\x11volatility_buffer\x18\x03 \x01(\tR\x10volatilityBuffer\x12\"\n" +
+	"\rmin_trade_amt\x18\x04 \x01(\tR\vminTradeAmt\x121\n" +
+	"\x15zero_on_min_violation\x18\x05 \x01(\bR\x12zeroOnMinViolation\x12'\n" +
+	"\x0fresidual_policy\x18\x06 \x01(\tR\x0eresidualPolicy\x12/\n" +
+	"\x13allocation_strategy\x18\a \x01(\tR\x12allocationStrategy\x12'\n" +
+	"\x0fallocation_mode\x183 \x01(\tR\x0eallocationMode\x12:\n" +
+	"\x19include_effective_weights\x18\b \x01(\bR\x17includeEffectiveWeights\x122\n" +
+	"\x15lock_current_holdings\x182 \x01(\bR\x13lockCurrentHoldings\x121\n" +
+	"\x05goals\x18\t \x03(\v2\x1b.smartsplitter.v1.GoalProtoR\x05goals\x12)\n" +
+	"\x10repair_objective\x18\n" +
+	" \x01(\tR\x0frepairObjective\x12,\n" +
+	"\x12min_progress_guard\x18\v \x01(\bR\x10minProgressGuard\x12\x1b\n" +
+	"\ttie_break\x18\f \x01(\tR\btieBreak\x12-\n" +
+	"\x13zero_out_max_weight\x18\r \x01(\tR\x10zeroOutMaxWeight\x12'\n" +
+	"\x0fidempotency_key\x18\x0e \x01(\tR\x0eidempotencyKey\x12+\n" +
+	"\x11normalize_weights\x18\x0f \x01(\bR\x10normalizeWeights\x12\x18\n" +
+	"\aexplain\x18\x10 \x01(\bR\aexplain\x12&\n" +
+	"\x0fwhat_if_amounts\x18\x11 \x03(\tR\rwhatIfAmounts\x123\n" +
+	"\x15shrinkage_coefficient\x18\x12 \x01(\tR\x14shrinkageCoefficient\x120\n" +
+	"\x14auto_full_redemption\x18\x13 \x01(\bR\x12autoFullRedemption\x12'\n" +
+	"\x0frepair_strategy\x18\x14 \x01(\tR\x0erepairStrategy\x12\x1b\n" +
+	"\tpage_size\x18\x15 \x01(\tR\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x16 \x01(\tR\x06cursor\x12,\n" +
+	"\x12min_holding_policy\x18\x17 \x01(\tR\x10minHoldingPolicy\x124\n" +
+	"\x16rebuild_big_redemption\x18\x18 \x01(\bR\x14rebuildBigRedemption\x12#\n" +
+	"\rproceeds_mode\x18\x19 \x01(\tR\fproceedsMode\x128\n" +
+	"\x18redemption_minimum_basis\x18\x1a \x01(\tR\x16redemptionMinimumBasis\x121\n" +
+	"\x15sort_output_by_ticker\x18\x1b \x01(\bR\x12sortOutputByTicker\x12\x1f\n" +
+	"\vstrict_mode\x18\x1c \x01(\bR\n" +
+	"strictMode\x12?\n" +
+	"\x1cmin_weight_deviation_trigger\x18\x1d \x01(\tR\x19minWeightDeviationTrigger\x12#\n" +
+	"\rlot_selection\x18\x1e \x01(\tR\flotSelection\x12&\n" +
+	"\x0fscale_lot_units\x18\x1f \x01(\bR\rscaleLotUnits\x12+\n" +
+	"\x12max_price_age_days\x18  \x01(\tR\x0fmaxPriceAgeDays\x12!\n" +
+	"\fphase1_order\x18! \x01(\tR\vphase1Order\x12:\n" +
+	"\x19full_redemption_tolerance\x18\" \x01(\tR\x17fullRedemptionTolerance\x12.\n" +
+	"\x13allow_missing_price\x18# \x01(\bR\x11allowMissingPrice\x120\n" +
+	"\x14missing_price_policy\x18$ \x01(\tR\x12missingPricePolicy\x12'\n" +
+	"\x0frequest_version\x18% \x01(\tR\x0erequestVersion\x12,\n" +
+	"\x12include_post_trade\x18& \x01(\bR\x10includePostTrade\x12'\n" +
+	"\x0finclude_weights\x18' \x01(\bR\x0eincludeWeights\x128\n" +
+	"\x18weight_decimal_precision\x18( \x01(\tR\x16weightDecimalPrecision\x12\x1a\n" +
+	"\bcurrency\x18) \x01(\tR\bcurrency\x126\n" +
+	"\x17ignore_current_holdings\x18* \x01(\bR\x15ignoreCurrentHoldings\x12)\n" +
+	"\x10weight_precision\x18+ \x01(\tR\x0fweightPrecision\x12#\n" +
+	"\rinclude_holds\x18, \x01(\bR\fincludeHolds\x12\x14\n" +
+	"\x05quiet\x18. \x01(\bR\x05quiet\x12d\n" +
+	"\x12severity_overrides\x18/ \x03(\v25.smartsplitter.v1.SplitRequest.SeverityOverridesEntryR\x11severityOverrides\x128\n" +
+	"\x18recompute_holding_values\x180 \x01(\bR\x16recomputeHoldingValues\x12)\n" +
+	"\x10client_reference\x181 \x01(\tR\x0fclientReference\x127\n" +
+	"\x17consolidation_threshold\x18- \x01(\tR\x16consolidationThreshold\x1aD\n" +
+	"\x16SeverityOverridesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb2\x06\n" +
+	"\tGoalProto\x12\x17\n" +
+	"\agoal_id\x18\x01 \x01(\tR\x06goalId\x12A\n" +
+	"\fgoal_details\x18\x02 \x03(\v2\x1e.smartsplitter.v1.HoldingProtoR\vgoalDetails\x12!\n" +
+	"\forder_amount\x18\x03 \x01(\tR\vorderAmount\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\x04 \x01(\tR\torderType\x12,\n" +
+	"\x12model_portfolio_id\x18\x05 \x01(\tR\x10modelPortfolioId\x12X\n" +
+	"\x17model_portfolio_details\x18\x06 \x03(\v2 .smartsplitter.v1.ModelItemProtoR\x15modelPortfolioDetails\x12\x1d\n" +
+	"\n" +
+	"max_trades\x18\a \x01(\tR\tmaxTrades\x12\"\n" +
+	"\rmin_trade_amt\x18\b \x01(\tR\vminTradeAmt\x12/\n" +
+	"\x13allocation_strategy\x18\t \x01(\tR\x12allocationStrategy\x12,\n" +
+	"\x12max_redemption_pct\x18\n" +
+	" \x01(\tR\x10maxRedemptionPct\x12\x1d\n" +
+	"\n" +
+	"order_unit\x18\v \x01(\tR\torderUnit\x12%\n" +
+	"\x0ekeep_unmodeled\x18\f \x01(\bR\rkeepUnmodeled\x12+\n" +
+	"\x11volatility_buffer\x18\r \x01(\tR\x10volatilityBuffer\x12!\n" +
+	"\fcash_balance\x18\x0e \x01(\tR\vcashBalance\x12\x1f\n" +
+	"\vtarget_date\x18\x0f \x01(\tR\n" +
+	"targetDate\x12D\n" +
+	"\n" +
+	"glide_path\x18\x10 \x03(\v2%.smartsplitter.v1.GlidePathPointProtoR\tglidePath\x12\x19\n" +
+	"\bcash_out\x18\x11 \x01(\bR\acashOut\x12\x1a\n" +
+	"\bcurrency\x18\x12 \x01(\tR\bcurrency\x12)\n" +
+	"\x10client_reference\x18\x13 \x01(\tR\x0fclientReference\"\x83\x01\n" +
+	"\x13GlidePathPointProto\x12&\n" +
+	"\x0fyears_to_target\x18\x01 \x01(\tR\ryearsToTarget\x12#\n" +
+	"\requity_weight\x18\x02 \x01(\tR\fequityWeight\x12\x1f\n" +
+	"\vbond_weight\x18\x03 \x01(\tR\n" +
+	"bondWeight\"\xf4\a\n" +
+	"\fHoldingProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\tR\x05units\x12!\n" +
+	"\fmarket_price\x18\x03 \x01(\tR\vmarketPrice\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12#\n" +
+	"\rpending_value\x18\x05 \x01(\tR\fpendingValue\x12#\n" +
+	"\rpending_units\x18\x06 \x01(\tR\fpendingUnits\x12;\n" +
+	"\x1amin_initial_investment_amt\x18\a \x01(\tR\x17minInitialInvestmentAmt\x12?\n" +
+	"\x1cmin_initial_investment_units\x18\b \x01(\tR\x19minInitialInvestmentUnits\x12\"\n" +
+	"\rmin_topup_amt\x18\t \x01(\tR\vminTopupAmt\x12&\n" +
+	"\x0fmin_topup_units\x18\n" +
+	" \x01(\tR\rminTopupUnits\x12,\n" +
+	"\x12min_redemption_amt\x18\v \x01(\tR\x10minRedemptionAmt\x120\n" +
+	"\x14min_redemption_units\x18\f \x01(\tR\x12minRedemptionUnits\x12&\n" +
+	"\x0fmin_holding_amt\x18\r \x01(\tR\rminHoldingAmt\x12*\n" +
+	"\x11min_holding_units\x18\x0e \x01(\tR\x0fminHoldingUnits\x12'\n" +
+	"\x0ftransaction_fee\x18\x0f \x01(\tR\x0etransactionFee\x12%\n" +
+	"\x0eredemption_fee\x18\x10 \x01(\tR\rredemptionFee\x120\n" +
+	"\x14withholding_tax_rate\x18\x11 \x01(\tR\x12withholdingTaxRate\x12!\n" +
+	"\faverage_cost\x18\x12 \x01(\tR\vaverageCost\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x13 \x01(\tR\ttotalCost\x12.\n" +
+	"\x04lots\x18\x14 \x03(\v2\x1a.smartsplitter.v1.LotProtoR\x04lots\x12\x12\n" +
+	"\x04keep\x18\x15 \x01(\bR\x04keep\x12#\n" +
+	"\rblocked_units\x18\x16 \x01(\tR\fblockedUnits\x12-\n" +
+	"\x12redemption_percent\x18\x17 \x01(\tR\x11redemptionPercent\x12#\n" +
+	"\rholding_since\x18\x18 \x01(\tR\fholdingSince\x124\n" +
+	"\x16force_initial_minimums\x18\x19 \x01(\bR\x14forceInitialMinimums\"o\n" +
+	"\bLotProto\x12\x14\n" +
+	"\x05units\x18\x01 \x01(\tR\x05units\x12\"\n" +
+	"\rcost_per_unit\x18\x02 \x01(\tR\vcostPerUnit\x12)\n" +
+	"\x10acquisition_date\x18\x03 \x01(\tR\x0facquisitionDate\"\xa3\a\n" +
+	"\x0eModelItemProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\tR\x06weight\x12!\n" +
+	"\fmarket_price\x18\x03 \x01(\tR\vmarketPrice\x12#\n" +
+	"\rexchange_rate\x18\x04 \x01(\tR\fexchangeRate\x12;\n" +
+	"\x1amin_initial_investment_amt\x18\x05 \x01(\tR\x17minInitialInvestmentAmt\x12?\n" +
+	"\x1cmin_initial_investment_units\x18\x06 \x01(\tR\x19minInitialInvestmentUnits\x12\"\n" +
+	"\rmin_topup_amt\x18\a \x01(\tR\vminTopupAmt\x12&\n" +
+	"\x0fmin_topup_units\x18\b \x01(\tR\rminTopupUnits\x12,\n" +
+	"\x12min_redemption_amt\x18\t \x01(\tR\x10minRedemptionAmt\x120\n" +
+	"\x14min_redemption_units\x18\n" +
+	" \x01(\tR\x12minRedemptionUnits\x12&\n" +
+	"\x0fmin_holding_amt\x18\v \x01(\tR\rminHoldingAmt\x12*\n" +
+	"\x11min_holding_units\x18\f \x01(\tR\x0fminHoldingUnits\x12'\n" +
+	"\x0ftransaction_fee\x18\r \x01(\tR\x0etransactionFee\x12\x1a\n" +
+	"\bpriority\x18\x0e \x01(\tR\bpriority\x121\n" +
+	"\x15protect_from_zero_out\x18\x0f \x01(\bR\x12protectFromZeroOut\x12)\n" +
+	"\x10allocation_floor\x18\x10 \x01(\tR\x0fallocationFloor\x12%\n" +
+	"\x0eredemption_fee\x18\x11 \x01(\tR\rredemptionFee\x120\n" +
+	"\x14withholding_tax_rate\x18\x12 \x01(\tR\x12withholdingTaxRate\x12\x1d\n" +
+	"\n" +
+	"price_date\x18\x13 \x01(\tR\tpriceDate\x12\x1f\n" +
+	"\vasset_class\x18\x14 \x01(\tR\n" +
+	"assetClass\x125\n" +
+	"\x17min_holding_period_days\x18\x15 \x01(\tR\x14minHoldingPeriodDays\x12(\n" +
+	"\x10blocked_from_buy\x18\x16 \x01(\bR\x0eblockedFromBuy\"\xf0\x01\n" +
+	"\rSplitResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.smartsplitter.v1.GoalResultProtoR\aresults\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12:\n" +
+	"\bwarnings\x18\x04 \x03(\v2\x1e.smartsplitter.v1.WarningProtoR\bwarnings\x12)\n" +
+	"\x10client_reference\x18\x05 \x01(\tR\x0fclientReference\"T\n" +
+	"\fWarningProto\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06ticker\x18\x03 \x01(\tR\x06ticker\"\x94\v\n" +
+	"\x0fGoalResultProto\x12\x17\n" +
+	"\agoal_id\x18\x01 \x01(\tR\x06goalId\x12)\n" +
+	"\x10transaction_type\x18\x02 \x01(\tR\x0ftransactionType\x12Y\n" +
+	"\x13transaction_details\x18\x03 \x03(\v2(.smartsplitter.v1.TransactionDetailProtoR\x12transactionDetails\x12S\n" +
+	"\x11effective_weights\x18\x04 \x03(\v2&.smartsplitter.v1.EffectiveWeightProtoR\x10effectiveWeights\x126\n" +
+	"\x05debug\x18\x05 \x01(\v2 .smartsplitter.v1.GoalDebugProtoR\x05debug\x127\n" +
+	"\x05error\x18\x06 \x01(\v2!.smartsplitter.v1.TradeErrorProtoR\x05error\x122\n" +
+	"\x15total_portfolio_value\x18\a \x01(\tR\x13totalPortfolioValue\x124\n" +
+	"\x16effective_order_amount\x18\b \x01(\tR\x14effectiveOrderAmount\x12K\n" +
+	"\x0fwhat_if_results\x18\t \x03(\v2#.smartsplitter.v1.WhatIfResultProtoR\rwhatIfResults\x12-\n" +
+	"\x12unfulfilled_amount\x18\n" +
+	" \x01(\tR\x11unfulfilledAmount\x12\x1f\n" +
+	"\vtrade_count\x18\v \x01(\x05R\n" +
+	"tradeCount\x12\x1f\n" +
+	"\verror_count\x18\f \x01(\x05R\n" +
+	"errorCount\x12#\n" +
+	"\restimated_tax\x18\r \x01(\tR\festimatedTax\x12\"\n" +
+	"\rnet_after_tax\x18\x0e \x01(\tR\vnetAfterTax\x126\n" +
+	"\x17estimated_realized_gain\x18\x0f \x01(\tR\x15estimatedRealizedGain\x124\n" +
+	"\x16full_redemption_excess\x18\x10 \x01(\tR\x14fullRedemptionExcess\x12W\n" +
+	"\x13post_trade_holdings\x18\x11 \x03(\v2'.smartsplitter.v1.PostTradeHoldingProtoR\x11postTradeHoldings\x12(\n" +
+	"\x10post_trade_total\x18\x12 \x01(\tR\x0epostTradeTotal\x12S\n" +
+	"\x11violation_summary\x18\x13 \x03(\v2&.smartsplitter.v1.ViolationRecordProtoR\x10violationSummary\x12<\n" +
+	"\asummary\x18\x14 \x01(\v2\".smartsplitter.v1.GoalSummaryProtoR\asummary\x12P\n" +
+	"\x10skipped_products\x18\x15 \x03(\v2%.smartsplitter.v1.SkippedProductProtoR\x0fskippedProducts\x12:\n" +
+	"\bwarnings\x18\x16 \x03(\v2\x1e.smartsplitter.v1.WarningProtoR\bwarnings\x12,\n" +
+	"\x12model_portfolio_id\x18\x17 \x01(\tR\x10modelPortfolioId\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\x18 \x01(\tR\torderType\x12!\n" +
+	"\forder_amount\x18\x19 \x01(\tR\vorderAmount\x12)\n" +
+	"\x10client_reference\x18\x1a \x01(\tR\x0fclientReference\"\xf1\x02\n" +
+	"\x10GoalSummaryProto\x12*\n" +
+	"\x11total_gross_value\x18\x01 \x01(\tR\x0ftotalGrossValue\x12&\n" +
+	"\x0ftotal_net_value\x18\x02 \x01(\tR\rtotalNetValue\x120\n" +
+	"\x14total_estimated_fees\x18\x03 \x01(\tR\x12totalEstimatedFees\x12-\n" +
+	"\x12unallocated_amount\x18\x04 \x01(\tR\x11unallocatedAmount\x12\x1f\n" +
+	"\vtrade_count\x18\x05 \x01(\x05R\n" +
+	"tradeCount\x12'\n" +
+	"\x0fviolation_count\x18\x06 \x01(\x05R\x0eviolationCount\x120\n" +
+	"\x14total_gross_proceeds\x18\a \x01(\tR\x12totalGrossProceeds\x12,\n" +
+	"\x12total_net_proceeds\x18\b \x01(\tR\x10totalNetProceeds\"[\n" +
+	"\x13SkippedProductProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x95\x01\n" +
+	"\x14ViolationRecordProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\tR\terrorCode\x12!\n" +
+	"\frequired_amt\x18\x03 \x01(\tR\vrequiredAmt\x12#\n" +
+	"\rallocated_amt\x18\x04 \x01(\tR\fallocatedAmt\"s\n" +
+	"\x15PostTradeHoldingProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x14\n" +
+	"\x05units\x18\x03 \x01(\tR\x05units\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\tR\x06weight\"\xcd\x01\n" +
+	"\x11WhatIfResultProto\x12!\n" +
+	"\forder_amount\x18\x01 \x01(\tR\vorderAmount\x12\x1e\n" +
+	"\n" +
+	"violations\x18\x02 \x01(\x05R\n" +
+	"violations\x12 \n" +
+	"\vunallocated\x18\x03 \x01(\tR\vunallocated\x12S\n" +
+	"\x11effective_weights\x18\x04 \x03(\v2&.smartsplitter.v1.EffectiveWeightProtoR\x10effectiveWeights\"|\n" +
+	"\x0eGoalDebugProto\x12)\n" +
+	"\x10repair_objective\x18\x01 \x01(\tR\x0frepairObjective\x12?\n" +
+	"\n" +
+	"self_check\x18\x02 \x01(\v2 .smartsplitter.v1.SelfCheckProtoR\tselfCheck\"s\n" +
+	"\x0eSelfCheckProto\x12\x16\n" +
+	"\x06passed\x18\x01 \x01(\bR\x06passed\x12I\n" +
+	"\n" +
+	"violations\x18\x02 \x03(\v2).smartsplitter.v1.InvariantViolationProtoR\n" +
+	"violations\"i\n" +
+	"\x17InvariantViolationProto\x12\x1c\n" +
+	"\tinvariant\x18\x01 \x01(\tR\tinvariant\x12\x16\n" +
+	"\x06ticker\x18\x02 \x01(\tR\x06ticker\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x98\x06\n" +
+	"\x16TransactionDetailProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x14\n" +
+	"\x05units\x18\x04 \x01(\tR\x05units\x127\n" +
+	"\x05error\x18\x05 \x01(\v2!.smartsplitter.v1.TradeErrorProtoR\x05error\x122\n" +
+	"\x05trace\x18\x06 \x01(\v2\x1c.smartsplitter.v1.TraceProtoR\x05trace\x12'\n" +
+	"\x0ffull_redemption\x18\a \x01(\bR\x0efullRedemption\x12!\n" +
+	"\fnet_proceeds\x18\b \x01(\tR\vnetProceeds\x12#\n" +
+	"\restimated_tax\x18\t \x01(\tR\festimatedTax\x12\"\n" +
+	"\rnet_after_tax\x18\n" +
+	" \x01(\tR\vnetAfterTax\x126\n" +
+	"\x17estimated_realized_gain\x18\v \x01(\tR\x15estimatedRealizedGain\x122\n" +
+	"\x04lots\x18\f \x03(\v2\x1e.smartsplitter.v1.LotSaleProtoR\x04lots\x12-\n" +
+	"\x12manual_instruction\x18\r \x01(\tR\x11manualInstruction\x12!\n" +
+	"\fmodel_weight\x18\x0e \x01(\tR\vmodelWeight\x12(\n" +
+	"\x10pre_trade_weight\x18\x0f \x01(\tR\x0epreTradeWeight\x12*\n" +
+	"\x11post_trade_weight\x18\x10 \x01(\tR\x0fpostTradeWeight\x12\x14\n" +
+	"\x05drift\x18\x11 \x01(\tR\x05drift\x12\x14\n" +
+	"\x05price\x18\x12 \x01(\tR\x05price\x12\x1f\n" +
+	"\vhold_reason\x18\x13 \x01(\tR\n" +
+	"holdReason\x129\n" +
+	"\x06errors\x18\x14 \x03(\v2!.smartsplitter.v1.TradeErrorProtoR\x06errors\"\x98\x01\n" +
+	"\fLotSaleProto\x12)\n" +
+	"\x10acquisition_date\x18\x01 \x01(\tR\x0facquisitionDate\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\tR\x05units\x12\"\n" +
+	"\rcost_per_unit\x18\x03 \x01(\tR\vcostPerUnit\x12#\n" +
+	"\rrealized_gain\x18\x04 \x01(\tR\frealizedGain\"\xa3\x02\n" +
+	"\n" +
+	"TraceProto\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\tR\x05phase\x12#\n" +
+	"\rcurrent_value\x18\x02 \x01(\tR\fcurrentValue\x12\x14\n" +
+	"\x05ideal\x18\x03 \x01(\tR\x05ideal\x12,\n" +
+	"\x12fee_adjusted_gross\x18\x04 \x01(\tR\x10feeAdjustedGross\x12.\n" +
+	"\x13gross_before_repair\x18\x05 \x01(\tR\x11grossBeforeRepair\x12#\n" +
+	"\rrepair_action\x18\x06 \x01(\tR\frepairAction\x12+\n" +
+	"\x11repair_adjustment\x18\a \x01(\tR\x10repairAdjustment\x12\x14\n" +
+	"\x05final\x18\b \x01(\tR\x05final\"\x91\x02\n" +
+	"\x0fTradeErrorProto\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12'\n" +
+	"\x0frequired_amount\x18\x03 \x01(\tR\x0erequiredAmount\x12%\n" +
+	"\x0erequired_units\x18\x04 \x01(\tR\rrequiredUnits\x12#\n" +
+	"\ractual_amount\x18\x05 \x01(\tR\factualAmount\x12!\n" +
+	"\factual_units\x18\x06 \x01(\tR\vactualUnits\x12\x1c\n" +
+	"\tshortfall\x18\a \x01(\tR\tshortfall\x12\x1a\n" +
+	"\bseverity\x18\b \x01(\tR\bseverity\"\x9f\x01\n" +
+	"\x14EffectiveWeightProto\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12!\n" +
+	"\fmodel_weight\x18\x02 \x01(\tR\vmodelWeight\x12'\n" +
+	"\x0fachieved_weight\x18\x03 \x01(\tR\x0eachievedWeight\x12#\n" +
+	"\rabs_deviation\x18\x04 \x01(\tR\fabsDeviation2T\n" +
+	"\bSplitter\x12H\n" +
+	"\x05Split\x12\x1e.smartsplitter.v1.SplitRequest\x1a\x1f.smartsplitter.v1.SplitResponseB6Z4github.com/valentinpj/smart-splitter/grpc/splitterpbb\x06proto3"
+
+var (
+	file_smart_splitter_proto_rawDescOnce sync.Once
+	file_smart_splitter_proto_rawDescData []byte
+)
+
+func file_smart_splitter_proto_rawDescGZIP() []byte {
+	file_smart_splitter_proto_rawDescOnce.Do(func() {
+		file_smart_splitter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_smart_splitter_proto_rawDesc), len(file_smart_splitter_proto_rawDesc)))
+	})
+	return file_smart_splitter_proto_rawDescData
+}
+
+var file_smart_splitter_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_smart_splitter_proto_goTypes = []any{
+	(*SplitRequest)(nil),            // 0: smartsplitter.v1.SplitRequest
+	(*GoalProto)(nil),               // 1: smartsplitter.v1.GoalProto
+	(*GlidePathPointProto)(nil),     // 2: smartsplitter.v1.GlidePathPointProto
+	(*HoldingProto)(nil),            // 3: smartsplitter.v1.HoldingProto
+	(*LotProto)(nil),                // 4: smartsplitter.v1.LotProto
+	(*ModelItemProto)(nil),          // 5: smartsplitter.v1.ModelItemProto
+	(*SplitResponse)(nil),           // 6: smartsplitter.v1.SplitResponse
+	(*WarningProto)(nil),            // 7: smartsplitter.v1.WarningProto
+	(*GoalResultProto)(nil),         // 8: smartsplitter.v1.GoalResultProto
+	(*GoalSummaryProto)(nil),        // 9: smartsplitter.v1.GoalSummaryProto
+	(*SkippedProductProto)(nil),     // 10: smartsplitter.v1.SkippedProductProto
+	(*ViolationRecordProto)(nil),    // 11: smartsplitter.v1.ViolationRecordProto
+	(*PostTradeHoldingProto)(nil),   // 12: smartsplitter.v1.PostTradeHoldingProto
+	(*WhatIfResultProto)(nil),       // 13: smartsplitter.v1.WhatIfResultProto
+	(*GoalDebugProto)(nil),          // 14: smartsplitter.v1.GoalDebugProto
+	(*SelfCheckProto)(nil),          // 15: smartsplitter.v1.SelfCheckProto
+	(*InvariantViolationProto)(nil), // 16: smartsplitter.v1.InvariantViolationProto
+	(*TransactionDetailProto)(nil),  // 17: smartsplitter.v1.TransactionDetailProto
+	(*LotSaleProto)(nil),            // 18: smartsplitter.v1.LotSaleProto
+	(*TraceProto)(nil),              // 19: smartsplitter.v1.TraceProto
+	(*TradeErrorProto)(nil),         // 20: smartsplitter.v1.TradeErrorProto
+	(*EffectiveWeightProto)(nil),    // 21: smartsplitter.v1.EffectiveWeightProto
+	nil,                             // 22: smartsplitter.v1.SplitRequest.SeverityOverridesEntry
+}
+var file_smart_splitter_proto_depIdxs = []int32{
+	1,  // 0: smartsplitter.v1.SplitRequest.goals:type_name -> smartsplitter.v1.GoalProto
+	22, // 1: smartsplitter.v1.SplitRequest.severity_overrides:type_name -> smartsplitter.v1.SplitRequest.SeverityOverridesEntry
+	3,  // 2: smartsplitter.v1.GoalProto.goal_details:type_name -> smartsplitter.v1.HoldingProto
+	5,  // 3: smartsplitter.v1.GoalProto.model_portfolio_details:type_name -> smartsplitter.v1.ModelItemProto
+	2,  // 4: smartsplitter.v1.GoalProto.glide_path:type_name -> smartsplitter.v1.GlidePathPointProto
+	4,  // 5: smartsplitter.v1.HoldingProto.lots:type_name -> smartsplitter.v1.LotProto
+	8,  // 6: smartsplitter.v1.SplitResponse.results:type_name -> smartsplitter.v1.GoalResultProto
+	7,  // 7: smartsplitter.v1.SplitResponse.warnings:type_name -> smartsplitter.v1.WarningProto
+	17, // 8: smartsplitter.v1.GoalResultProto.transaction_details:type_name -> smartsplitter.v1.TransactionDetailProto
+	21, // 9: smartsplitter.v1.GoalResultProto.effective_weights:type_name -> smartsplitter.v1.EffectiveWeightProto
+	14, // 10: smartsplitter.v1.GoalResultProto.debug:type_name -> smartsplitter.v1.GoalDebugProto
+	20, // 11: smartsplitter.v1.GoalResultProto.error:type_name -> smartsplitter.v1.TradeErrorProto
+	13, // 12: smartsplitter.v1.GoalResultProto.what_if_results:type_name -> smartsplitter.v1.WhatIfResultProto
+	12, // 13: smartsplitter.v1.GoalResultProto.post_trade_holdings:type_name -> smartsplitter.v1.PostTradeHoldingProto
+	11, // 14: smartsplitter.v1.GoalResultProto.violation_summary:type_name -> smartsplitter.v1.ViolationRecordProto
+	9,  // 15: smartsplitter.v1.GoalResultProto.summary:type_name -> smartsplitter.v1.GoalSummaryProto
+	10, // 16: smartsplitter.v1.GoalResultProto.skipped_products:type_name -> smartsplitter.v1.SkippedProductProto
+	7,  // 17: smartsplitter.v1.GoalResultProto.warnings:type_name -> smartsplitter.v1.WarningProto
+	21, // 18: smartsplitter.v1.WhatIfResultProto.effective_weights:type_name -> smartsplitter.v1.EffectiveWeightProto
+	15, // 19: smartsplitter.v1.GoalDebugProto.self_check:type_name -> smartsplitter.v1.SelfCheckProto
+	16, // 20: smartsplitter.v1.SelfCheckProto.violations:type_name -> smartsplitter.v1.InvariantViolationProto
+	20, // 21: smartsplitter.v1.TransactionDetailProto.error:type_name -> smartsplitter.v1.TradeErrorProto
+	19, // 22: smartsplitter.v1.TransactionDetailProto.trace:type_name -> smartsplitter.v1.TraceProto
+	18, // 23: smartsplitter.v1.TransactionDetailProto.lots:type_name -> smartsplitter.v1.LotSaleProto
+	20, // 24: smartsplitter.v1.TransactionDetailProto.errors:type_name -> smartsplitter.v1.TradeErrorProto
+	0,  // 25: smartsplitter.v1.Splitter.Split:input_type -> smartsplitter.v1.SplitRequest
+	6,  // 26: smartsplitter.v1.Splitter.Split:output_type -> smartsplitter.v1.SplitResponse
+	26, // [26:27] is the sub-list for method output_type
+	25, // [25:26] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_smart_splitter_proto_init() }
+func file_smart_splitter_proto_init() {
+	if File_smart_splitter_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_smart_splitter_proto_rawDesc), len(file_smart_splitter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_smart_splitter_proto_goTypes,
+		DependencyIndexes: file_smart_splitter_proto_depIdxs,
+		MessageInfos:      file_smart_splitter_proto_msgTypes,
+	}.Build()
+	File_smart_splitter_proto = out.File
+	file_smart_splitter_proto_goTypes = nil
+	file_smart_splitter_proto_depIdxs = nil
+}
@@ -0,0 +1,128 @@
+// gRPC contract for the Smart Order Splitter. Messages mirror the JSON types in the
+// models package field-for-field; numeric values stay strings, exactly as on /split.
+//
+// Regenerate grpc/splitterpb after editing this file (see README, "gRPC").
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: smart_splitter.proto
+
+package splitterpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Splitter_Split_FullMethodName = "/smartsplitter.v1.Splitter/Split"
+)
+
+// SplitterClient is the client API for Splitter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SplitterClient interface {
+	// Split runs the same validation and splitting as POST /split.
+	Split(ctx context.Context, in *SplitRequest, opts ...grpc.CallOption) (*SplitResponse, error)
+}
+
+type splitterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSplitterClient(cc grpc.ClientConnInterface) SplitterClient {
+	return &splitterClient{cc}
+}
+
+func (c *splitterClient) Split(ctx context.Context, in *SplitRequest, opts ...grpc.CallOption) (*SplitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SplitResponse)
+	err := c.cc.Invoke(ctx, Splitter_Split_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SplitterServer is the server API for Splitter service.
+// All implementations must embed UnimplementedSplitterServer
+// for forward compatibility.
+type SplitterServer interface {
+	// Split runs the same validation and splitting as POST /split.
+	Split(context.Context, *SplitRequest) (*SplitResponse, error)
+	mustEmbedUnimplementedSplitterServer()
+}
+
+// UnimplementedSplitterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSplitterServer struct{}
+
+func (UnimplementedSplitterServer) Split(context.Context, *SplitRequest) (*SplitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Split not implemented")
+}
+func (UnimplementedSplitterServer) mustEmbedUnimplementedSplitterServer() {}
+func (UnimplementedSplitterServer) testEmbeddedByValue()                  {}
+
+// UnsafeSplitterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SplitterServer will
+// result in compilation errors.
+type UnsafeSplitterServer interface {
+	mustEmbedUnimplementedSplitterServer()
+}
+
+func RegisterSplitterServer(s grpc.ServiceRegistrar, srv SplitterServer) {
+	// If the following call panics, it indicates UnimplementedSplitterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Splitter_ServiceDesc, srv)
+}
+
+func _Splitter_Split_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SplitterServer).Split(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Splitter_Split_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SplitterServer).Split(ctx, req.(*SplitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Splitter_ServiceDesc is the grpc.ServiceDesc for Splitter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Splitter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smartsplitter.v1.Splitter",
+	HandlerType: (*SplitterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Split",
+			Handler:    _Splitter_Split_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "smart_splitter.proto",
+}
@@ -2,10 +2,16 @@ package main
 
 import (
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/valentinpj/smart-splitter/api"
+	splittergrpc "github.com/valentinpj/smart-splitter/grpc"
+	"github.com/valentinpj/smart-splitter/splitter"
 )
 
 func main() {
@@ -14,9 +20,100 @@ func main() {
 		port = "8080"
 	}
 
+	if v := os.Getenv("MAX_GOALS_PER_REQUEST"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Fatalf("MAX_GOALS_PER_REQUEST must be a positive integer, got %q", v)
+		}
+		api.MaxGoalsPerRequest = n
+	}
+
+	if v := os.Getenv("MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			log.Fatalf("MAX_BODY_BYTES must be a positive integer, got %q", v)
+		}
+		api.MaxBodyBytes = n
+	}
+
+	if v := os.Getenv("MAX_PORTFOLIO_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Fatalf("MAX_PORTFOLIO_SIZE must be a positive integer, got %q", v)
+		}
+		api.MaxPortfolioSize = n
+	}
+
+	if v := os.Getenv("BATCH_WORKERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Fatalf("BATCH_WORKERS must be a positive integer, got %q", v)
+		}
+		splitter.BatchWorkers = n
+	}
+
+	if v := os.Getenv("DIVISION_PRECISION"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Fatalf("DIVISION_PRECISION must be a non-negative integer, got %q", v)
+		}
+		splitter.DivisionPrecision = n
+	}
+
+	// API_KEYS is a comma-separated list of accepted X-API-Key values; empty leaves the
+	// HTTP API open, for development.
+	var apiKeys []string
+	for _, k := range strings.Split(os.Getenv("API_KEYS"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			apiKeys = append(apiKeys, k)
+		}
+	}
+	if len(apiKeys) == 0 {
+		log.Printf("API_KEYS is not set; the HTTP API accepts unauthenticated requests")
+	}
+
+	// RATE_LIMIT_RPS limits each client IP to that many requests per second, with bursts of
+	// RATE_LIMIT_BURST; unset leaves the HTTP API unlimited.
+	var rps float64
+	if v := os.Getenv("RATE_LIMIT_RPS"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			log.Fatalf("RATE_LIMIT_RPS must be a positive number, got %q", v)
+		}
+		rps = f
+	}
+	burst := int(math.Ceil(rps))
+	if v := os.Getenv("RATE_LIMIT_BURST"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Fatalf("RATE_LIMIT_BURST must be a positive integer, got %q", v)
+		}
+		burst = n
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/split", api.HandleSplit)
+	mux.HandleFunc("POST /units", api.HandleUnits)
+	mux.HandleFunc("POST /drift", api.HandleDrift)
+	mux.HandleFunc("GET /codes", api.HandleCodes)
+	mux.HandleFunc("POST /sessions", api.HandleCreateSession)
+	mux.HandleFunc("PATCH /sessions/{token}", api.HandlePatchSession)
+	mux.HandleFunc("POST /sessions/{token}/split", api.HandleSessionSplit)
+
+	// GRPC_PORT enables the gRPC server, which has neither API-key auth nor a rate limit;
+	// unset leaves it off.
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("gRPC listen on :%s: %v", grpcPort, err)
+		}
+		go func() {
+			log.Printf("Smart Order Splitter gRPC listening on :%s", grpcPort)
+			log.Fatal(splittergrpc.NewServer().Serve(lis))
+		}()
+	}
 
 	log.Printf("Smart Order Splitter API listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	handler := api.WithCorrelationID(api.WithRateLimit(rps, burst)(api.WithAPIKeyAuth(apiKeys)(api.WithDecompression(mux))))
+	log.Fatal(http.ListenAndServe(":"+port, handler))
 }
@@ -0,0 +1,161 @@
+package models
+
+// Code is a stable, machine-readable code carried by trade errors, validation errors,
+// request errors and warnings. Clients should switch on it rather than on messages, which
+// may change.
+type Code string
+
+// Trade error codes, carried by a transaction detail's error and errors, and by a goal
+// result's error.
+const (
+	CodeMinInvestmentViolation Code = "MIN_INVESTMENT_VIOLATION"
+	CodeMinTopupViolation      Code = "MIN_TOPUP_VIOLATION"
+	CodeMinHoldingViolation    Code = "MIN_HOLDING_VIOLATION"
+	CodeMinRedemptionViolation Code = "MIN_REDEMPTION_VIOLATION"
+	CodeBelowMinTrade          Code = "BELOW_MIN_TRADE"
+	CodeBelowDeviationTrigger  Code = "BELOW_DEVIATION_TRIGGER"
+	CodeConsolidated           Code = "CONSOLIDATED"
+	CodeStalePrice             Code = "STALE_PRICE" // also a warning
+	CodeNoPrice                Code = "NO_PRICE"
+	CodeHoldingPeriod          Code = "HOLDING_PERIOD_VIOLATION"
+	CodeBlockedUnitsLimit      Code = "BLOCKED_UNITS_LIMIT"
+	CodeNothingToAllocate      Code = "NOTHING_TO_ALLOCATE"
+	CodeHoldingsLocked         Code = "HOLDINGS_LOCKED"
+	CodeBuyBlocked             Code = "BUY_BLOCKED"
+	CodeInsufficientMaxTrades  Code = "INSUFFICIENT_MAX_TRADES"
+)
+
+// Validation error codes, reported as the code of every entry in a 422 response.
+const (
+	CodeRequired             Code = "REQUIRED"               // a required field or list is empty
+	CodeInvalidDecimal       Code = "INVALID_DECIMAL"        // not a decimal number
+	CodeInvalidInteger       Code = "INVALID_INTEGER"        // not an integer
+	CodeInvalidDate          Code = "INVALID_DATE"           // not a YYYY-MM-DD date
+	CodeInvalidOption        Code = "INVALID_OPTION"         // not one of the accepted values
+	CodeInvalidCurrency      Code = "INVALID_CURRENCY"       // not a three-letter ISO 4217 code
+	CodeMustBePositive       Code = "MUST_BE_POSITIVE"       // must be greater than 0
+	CodeMustBeNonNegative    Code = "MUST_BE_NON_NEGATIVE"   // must be 0 or more
+	CodeExceedsPrecision     Code = "EXCEEDS_PRECISION"      // more decimal places than the precision allows
+	CodeRateOutOfRange       Code = "RATE_OUT_OF_RANGE"      // outside [0, 1)
+	CodeWeightOutOfRange     Code = "WEIGHT_OUT_OF_RANGE"    // outside [0, 1]
+	CodeToleranceOutOfRange  Code = "TOLERANCE_OUT_OF_RANGE" // outside (0, 1]
+	CodePercentOutOfRange    Code = "PERCENT_OUT_OF_RANGE"   // outside (0, 1]
+	CodeWeightSumMismatch    Code = "WEIGHT_SUM_MISMATCH"    // blended weights do not sum to 1
+	CodeFloorSumExceedsOne   Code = "FLOOR_SUM_EXCEEDS_ONE"  // allocation floors sum to more than 1
+	CodeConflictingFields    Code = "CONFLICTING_FIELDS"     // fields that cannot be set together
+	CodeCurrencyMismatch     Code = "CURRENCY_MISMATCH"      // goal currency differs from the request's
+	CodeUnsupportedOrderType Code = "UNSUPPORTED_ORDER_TYPE" // field not supported for the goal's orderType
+	CodeExceedsHoldings      Code = "EXCEEDS_HOLDINGS"       // order larger than the goal holds
+	CodeExceedsRedemptionCap Code = "EXCEEDS_REDEMPTION_CAP" // order larger than maxRedemptionPct allows
+	CodePendingExceedsHeld   Code = "PENDING_EXCEEDS_HELD"   // pending sells larger than the holding
+	CodeBlockedExceedsHeld   Code = "BLOCKED_EXCEEDS_HELD"   // blocked units larger than the holding
+	CodeLotUnitsMismatch     Code = "LOT_UNITS_MISMATCH"     // lot units do not add up to the holding's
+	CodeTooManyItems         Code = "TOO_MANY_ITEMS"         // list longer than allowed
+	CodeTooLong              Code = "TOO_LONG"               // string longer than allowed
+	CodeInvalidCursor        Code = "INVALID_CURSOR"         // cursor not issued for this request
+)
+
+// Request error codes, carried by the ErrorResponse of a request that failed as a whole.
+const (
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeTooManyGoals         Code = "TOO_MANY_GOALS"
+	CodePortfolioTooLarge    Code = "PORTFOLIO_TOO_LARGE"
+	CodeIdempotencyKeyReused Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeUnsupportedEncoding  Code = "UNSUPPORTED_ENCODING"
+	CodeBodyTooLarge         Code = "BODY_TOO_LARGE"
+)
+
+// Warning codes, carried by the warnings of a response or a goal result, besides
+// CodeStalePrice.
+const (
+	CodeHighFee                Code = "HIGH_FEE"
+	CodeWeightSum              Code = "WEIGHT_SUM"
+	CodeHoldingNotInModel      Code = "HOLDING_NOT_IN_MODEL"
+	CodeMixedCurrencies        Code = "MIXED_CURRENCIES"
+	CodeHoldingValueRecomputed Code = "HOLDING_VALUE_RECOMPUTED"
+)
+
+// Kinds of code in the catalogue AllErrorCodes returns.
+const (
+	CodeKindTrade      = "trade"      // a trade's or a goal's error
+	CodeKindValidation = "validation" // an entry of a 422 validation response
+	CodeKindRequest    = "request"    // the ErrorResponse of a failed request
+	CodeKindWarning    = "warning"    // a warning on a response or goal result
+)
+
+// CodeInfo describes one code of the catalogue.
+type CodeInfo struct {
+	Code        Code   `json:"code"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+var codeCatalogue = []CodeInfo{
+	{CodeMinInvestmentViolation, CodeKindTrade, "A first purchase breaches the product's minimum initial investment amount or units, net of fees."},
+	{CodeMinTopupViolation, CodeKindTrade, "A purchase of a product already held breaches its minimum top-up amount or units, net of fees."},
+	{CodeMinHoldingViolation, CodeKindTrade, "The position left after a buy or partial redemption would be below minHoldingAmt or minHoldingUnits."},
+	{CodeMinRedemptionViolation, CodeKindTrade, "A sell is below minRedemptionAmt or minRedemptionUnits."},
+	{CodeBelowMinTrade, CodeKindTrade, "The product was dropped because its allocation was below minTradeAmt."},
+	{CodeBelowDeviationTrigger, CodeKindTrade, "The product was skipped because its current weight is within minWeightDeviationTrigger of its model weight."},
+	{CodeConsolidated, CodeKindTrade, "Investment: the product was dropped by the trade-count cap. Redemption: the sell closes a position below consolidationThreshold."},
+	{CodeStalePrice, CodeKindTrade, "The product's model item has a priceDate more than maxPriceAgeDays days old; the trade is kept as allocated."},
+	{CodeNoPrice, CodeKindTrade, "The product has no marketPrice to derive units from and was left unsold under missingPricePolicy \"exclude\"."},
+	{CodeHoldingPeriod, CodeKindTrade, "The holding was bought fewer than its model item's minHoldingPeriodDays days ago and was left unsold."},
+	{CodeBlockedUnitsLimit, CodeKindTrade, "The order could not be met and the sell took every free unit of a holding with blockedUnits."},
+	{CodeHoldingsLocked, CodeKindTrade, "The product is not yet held and was not bought because lockCurrentHoldings keeps the order in the products already held."},
+	{CodeBuyBlocked, CodeKindTrade, "The product is blockedFromBuy and was not bought; its share of the order went to the other products."},
+	{CodeNothingToAllocate, CodeKindTrade, "Goal level: no product in modelPortfolioDetails has a weight greater than 0."},
+	{CodeInsufficientMaxTrades, CodeKindTrade, "Goal level: the products kept by maxTrades cannot all be traded within their minimums from the order."},
+
+	{CodeRequired, CodeKindValidation, "A required field or list is empty."},
+	{CodeInvalidDecimal, CodeKindValidation, "Not a decimal number."},
+	{CodeInvalidInteger, CodeKindValidation, "Not an integer."},
+	{CodeInvalidDate, CodeKindValidation, "Not a date in YYYY-MM-DD format."},
+	{CodeInvalidOption, CodeKindValidation, "Not one of the field's accepted values."},
+	{CodeInvalidCurrency, CodeKindValidation, "A currency that is not three letters."},
+	{CodeMustBePositive, CodeKindValidation, "Must be greater than 0."},
+	{CodeMustBeNonNegative, CodeKindValidation, "Must be 0 or more."},
+	{CodeExceedsPrecision, CodeKindValidation, "More decimal places than amountDecimalPrecision or unitDecimalPrecision allow."},
+	{CodeRateOutOfRange, CodeKindValidation, "A fee, rate or coefficient outside [0, 1)."},
+	{CodeWeightOutOfRange, CodeKindValidation, "A model weight, or a share such as maxRedemptionPct or shrinkageCoefficient, outside [0, 1]."},
+	{CodeToleranceOutOfRange, CodeKindValidation, "fullRedemptionTolerance outside (0, 1]."},
+	{CodePercentOutOfRange, CodeKindValidation, "redemptionPercent outside (0, 1]."},
+	{CodeWeightSumMismatch, CodeKindValidation, "Weights blended by shrinkageCoefficient do not sum to 1."},
+	{CodeFloorSumExceedsOne, CodeKindValidation, "A goal's allocationFloors sum to more than 1."},
+	{CodeConflictingFields, CodeKindValidation, "Fields that cannot be set together; field lists them."},
+	{CodeCurrencyMismatch, CodeKindValidation, "A goal's currency differs from the request-level currency."},
+	{CodeUnsupportedOrderType, CodeKindValidation, "A field the goal's orderType does not support, e.g. orderUnit on an investment."},
+	{CodeExceedsHoldings, CodeKindValidation, "A redemption larger than the goal holds."},
+	{CodeExceedsRedemptionCap, CodeKindValidation, "A redemption larger than maxRedemptionPct of the goal's holdings."},
+	{CodePendingExceedsHeld, CodeKindValidation, "Pending sells larger than the holding."},
+	{CodeBlockedExceedsHeld, CodeKindValidation, "blockedUnits larger than units."},
+	{CodeLotUnitsMismatch, CodeKindValidation, "A holding's lots do not add up to its units."},
+	{CodeTooManyItems, CodeKindValidation, "More whatIfAmounts than allowed."},
+	{CodeTooLong, CodeKindValidation, "A clientReference longer than allowed."},
+	{CodeInvalidCursor, CodeKindValidation, "A cursor not returned for this request."},
+
+	{CodeValidationFailed, CodeKindRequest, "The request failed validation; every problem is listed with its validation code."},
+	{CodeTooManyGoals, CodeKindRequest, "The request has more goals than MAX_GOALS_PER_REQUEST."},
+	{CodePortfolioTooLarge, CodeKindRequest, "A goal has more model items or holdings than MAX_PORTFOLIO_SIZE."},
+	{CodeIdempotencyKeyReused, CodeKindRequest, "The idempotencyKey was already used for a different request body."},
+	{CodeUnauthorized, CodeKindRequest, "The X-API-Key header is missing or invalid."},
+	{CodeRateLimited, CodeKindRequest, "The client sent more requests than RATE_LIMIT_RPS allows."},
+	{CodeUnsupportedEncoding, CodeKindRequest, "The request body has a Content-Encoding other than gzip."},
+	{CodeBodyTooLarge, CodeKindRequest, "The request body, once decompressed, is larger than MAX_BODY_BYTES."},
+
+	{CodeStalePrice, CodeKindWarning, "A trade is priced from a model item whose priceDate is more than 7 days old."},
+	{CodeHighFee, CodeKindWarning, "A trade's fee takes more than 5% of its value."},
+	{CodeWeightSum, CodeKindWarning, "The goal's model weights do not sum to exactly 1, without normalizeWeights."},
+	{CodeHoldingNotInModel, CodeKindWarning, "The goal holds a product its model does not list."},
+	{CodeMixedCurrencies, CodeKindWarning, "The request has no currency and its goals are in more than one."},
+	{CodeHoldingValueRecomputed, CodeKindWarning, "With recomputeHoldingValues, a holding's value moved by more than 1 basis point when recomputed as units × marketPrice."},
+}
+
+// AllErrorCodes returns every code the service reports, with its kind and a description,
+// trade errors first, then validation errors, request errors and warnings. A code that is
+// both a trade error and a warning is listed under each.
+func AllErrorCodes() []CodeInfo {
+	return append([]CodeInfo(nil), codeCatalogue...)
+}
@@ -3,19 +3,99 @@ package models
 // --- Request types ---
 
 type SplitRequest struct {
-	AmountDecimalPrecision string `json:"amountDecimalPrecision"`
-	UnitDecimalPrecision   string `json:"unitDecimalPrecision"`
-	VolatilityBuffer       string `json:"volatilityBuffer"`
-	Goals                  []Goal `json:"goals"`
+	AmountDecimalPrecision    string   `json:"amountDecimalPrecision"`
+	UnitDecimalPrecision      string   `json:"unitDecimalPrecision"`
+	VolatilityBuffer          string   `json:"volatilityBuffer"`
+	MinTradeAmt               string   `json:"minTradeAmt,omitempty"`
+	ConsolidationThreshold    string   `json:"consolidationThreshold,omitempty"`
+	ZeroOnMinViolation        bool     `json:"zeroOnMinViolation,omitempty"`
+	ResidualPolicy            string   `json:"residualPolicy,omitempty"`
+	AllocationStrategy        string   `json:"allocationStrategy,omitempty"`
+	AllocationMode            string   `json:"allocationMode,omitempty"`
+	IncludeEffectiveWeights   bool     `json:"includeEffectiveWeights,omitempty"`
+	LockCurrentHoldings       bool     `json:"lockCurrentHoldings,omitempty"`
+	IncludePostTrade          bool     `json:"includePostTrade,omitempty"`
+	IgnoreCurrentHoldings     bool     `json:"ignoreCurrentHoldings,omitempty"`
+	IncludeWeights            bool     `json:"includeWeights,omitempty"`
+	IncludeHolds              bool     `json:"includeHolds,omitempty"`
+	Quiet                     bool     `json:"quiet,omitempty"`
+	WeightDecimalPrecision    string   `json:"weightDecimalPrecision,omitempty"`
+	WeightPrecision           string   `json:"weightPrecision,omitempty"`
+	RepairObjective           string   `json:"repairObjective,omitempty"`
+	RepairStrategy            string   `json:"repairStrategy,omitempty"`
+	MinProgressGuard          bool     `json:"minProgressGuard,omitempty"`
+	TieBreak                  string   `json:"tieBreak,omitempty"`
+	ZeroOutMaxWeight          string   `json:"zeroOutMaxWeight,omitempty"`
+	IdempotencyKey            string   `json:"idempotencyKey,omitempty"`
+	NormalizeWeights          bool     `json:"normalizeWeights,omitempty"`
+	ShrinkageCoefficient      string   `json:"shrinkageCoefficient,omitempty"`
+	MinWeightDeviationTrigger string   `json:"minWeightDeviationTrigger,omitempty"`
+	AutoFullRedemption        bool     `json:"autoFullRedemption,omitempty"`
+	MinHoldingPolicy          string   `json:"minHoldingPolicy,omitempty"`
+	RebuildBigRedemption      bool     `json:"rebuildBigRedemption,omitempty"`
+	AllowMissingPrice         bool     `json:"allowMissingPrice,omitempty"`
+	MissingPricePolicy        string   `json:"missingPricePolicy,omitempty"`
+	ProceedsMode              string   `json:"proceedsMode,omitempty"`
+	RedemptionMinimumBasis    string   `json:"redemptionMinimumBasis,omitempty"`
+	SortOutputByTicker        bool     `json:"sortOutputByTicker,omitempty"`
+	StrictMode                bool     `json:"strictMode,omitempty"`
+	MaxPriceAgeDays           string   `json:"maxPriceAgeDays,omitempty"`
+	Phase1Order               string   `json:"phase1Order,omitempty"`
+	FullRedemptionTolerance   string   `json:"fullRedemptionTolerance,omitempty"`
+	LotSelection              string   `json:"lotSelection,omitempty"`
+	ScaleLotUnits             bool     `json:"scaleLotUnits,omitempty"`
+	Explain                   bool     `json:"explain,omitempty"`
+	WhatIfAmounts             []string `json:"whatIfAmounts,omitempty"`
+	PageSize                  string   `json:"pageSize,omitempty"`
+	Cursor                    string   `json:"cursor,omitempty"`
+	RequestVersion            string   `json:"requestVersion,omitempty"`
+	Currency                  string   `json:"currency,omitempty"`
+	RecomputeHoldingValues    bool     `json:"recomputeHoldingValues,omitempty"`
+	ClientReference           string   `json:"clientReference,omitempty"`
+	// SeverityOverrides maps a trade error code to the severity its errors are reported
+	// with, "error" or "warning"; codes not listed are errors.
+	SeverityOverrides map[string]string `json:"severityOverrides,omitempty"`
+	Goals             []Goal            `json:"goals"`
 }
 
 type Goal struct {
 	GoalID                string      `json:"goalId"`
 	GoalDetails           []Holding   `json:"goalDetails,omitempty"`
 	OrderAmount           string      `json:"orderAmount"`
+	OrderUnit             string      `json:"orderUnit,omitempty"`
 	OrderType             string      `json:"orderType"`
 	ModelPortfolioID      string      `json:"modelPortfolioId"`
 	ModelPortfolioDetails []ModelItem `json:"modelPortfolioDetails"`
+	MaxTrades             string      `json:"maxTrades,omitempty"`
+	MinTradeAmt           string      `json:"minTradeAmt,omitempty"`
+	AllocationStrategy    string      `json:"allocationStrategy,omitempty"`
+	MaxRedemptionPct      string      `json:"maxRedemptionPct,omitempty"`
+	VolatilityBuffer      string      `json:"volatilityBuffer,omitempty"`
+	// KeepUnmodeled keeps every holding with a zero or absent model weight, as Keep does
+	// for a single holding.
+	KeepUnmodeled bool `json:"keepUnmodeled,omitempty"`
+	// Currency is the ISO 4217 code the goal's amounts are in; it must match the request's
+	// Currency when both are set.
+	Currency string `json:"currency,omitempty"`
+	// CashOut liquidates the goal's holdings, largest first, up to OrderAmount or in full
+	// without one, with no reference to a model portfolio.
+	CashOut bool `json:"cashOut,omitempty"`
+	// CashBalance is uninvested cash in the goal, drawn before any holding is sold.
+	CashBalance string `json:"cashBalance,omitempty"`
+	// TargetDate (YYYY-MM-DD) and GlidePath set the weights of the goal's equity and bond
+	// model items from the years left to the target; see splitter.ApplyGlidePath.
+	TargetDate string           `json:"targetDate,omitempty"`
+	GlidePath  []GlidePathPoint `json:"glidePath,omitempty"`
+	// ClientReference is the caller's own reference for the goal, echoed on its result.
+	ClientReference string `json:"clientReference,omitempty"`
+}
+
+// GlidePathPoint is the equity and bond weight a target-date goal holds at YearsToTarget
+// years from its target date.
+type GlidePathPoint struct {
+	YearsToTarget string `json:"yearsToTarget"`
+	EquityWeight  string `json:"equityWeight"`
+	BondWeight    string `json:"bondWeight"`
 }
 
 type Holding struct {
@@ -23,6 +103,9 @@ type Holding struct {
 	Units                     string `json:"units"`
 	MarketPrice               string `json:"marketPrice"`
 	Value                     string `json:"value"`
+	PendingValue              string `json:"pendingValue,omitempty"`
+	PendingUnits              string `json:"pendingUnits,omitempty"`
+	BlockedUnits              string `json:"blockedUnits,omitempty"`
 	MinInitialInvestmentAmt   string `json:"minInitialInvestmentAmt"`
 	MinInitialInvestmentUnits string `json:"minInitialInvestmentUnits"`
 	MinTopupAmt               string `json:"minTopupAmt"`
@@ -32,12 +115,42 @@ type Holding struct {
 	MinHoldingAmt             string `json:"minHoldingAmt"`
 	MinHoldingUnits           string `json:"minHoldingUnits"`
 	TransactionFee            string `json:"transactionFee"`
+	RedemptionFee             string `json:"redemptionFee,omitempty"`
+	WithholdingTaxRate        string `json:"withholdingTaxRate,omitempty"`
+	// AverageCost is the cost per unit of the position, or TotalCost its total cost; at
+	// most one is set. Either enables the realized gain estimate on redemptions.
+	AverageCost string `json:"averageCost,omitempty"`
+	TotalCost   string `json:"totalCost,omitempty"`
+	// Lots breaks the position down by purchase, as an alternative cost basis; their units
+	// add up to Units.
+	Lots []Lot `json:"lots,omitempty"`
+	// Keep exempts a holding with a zero or absent model weight from being sold off in
+	// redemption Phase 1; its value still counts towards the goal's.
+	Keep bool `json:"keep,omitempty"`
+	// ForceInitialMinimums checks an investment in the product against its initial-investment
+	// minimums rather than its top-up minimums, as if nothing were held: for a residual
+	// position that is effectively new. The holding still counts towards the goal's value.
+	ForceInitialMinimums bool `json:"forceInitialMinimums,omitempty"`
+	// RedemptionPercent instructs a redemption to sell this share of the holding, in
+	// (0, 1], in place of the goal's orderAmount.
+	RedemptionPercent string `json:"redemptionPercent,omitempty"`
+	// HoldingSince is the date the position was bought, YYYY-MM-DD; see the model item's
+	// MinHoldingPeriodDays.
+	HoldingSince string `json:"holdingSince,omitempty"`
+}
+
+// Lot is one purchase making up a holding. AcquisitionDate is YYYY-MM-DD.
+type Lot struct {
+	Units           string `json:"units"`
+	CostPerUnit     string `json:"costPerUnit"`
+	AcquisitionDate string `json:"acquisitionDate"`
 }
 
 type ModelItem struct {
 	Ticker                    string `json:"ticker"`
 	Weight                    string `json:"weight"`
 	MarketPrice               string `json:"marketPrice"`
+	ExchangeRate              string `json:"exchangeRate,omitempty"`
 	MinInitialInvestmentAmt   string `json:"minInitialInvestmentAmt"`
 	MinInitialInvestmentUnits string `json:"minInitialInvestmentUnits"`
 	MinTopupAmt               string `json:"minTopupAmt"`
@@ -47,31 +160,325 @@ type ModelItem struct {
 	MinHoldingAmt             string `json:"minHoldingAmt"`
 	MinHoldingUnits           string `json:"minHoldingUnits"`
 	TransactionFee            string `json:"transactionFee"`
+	RedemptionFee             string `json:"redemptionFee,omitempty"`
+	WithholdingTaxRate        string `json:"withholdingTaxRate,omitempty"`
+	Priority                  string `json:"priority,omitempty"`
+	ProtectFromZeroOut        bool   `json:"protectFromZeroOut,omitempty"`
+	AllocationFloor           string `json:"allocationFloor,omitempty"`
+	// PriceDate is the date MarketPrice was observed, YYYY-MM-DD; see maxPriceAgeDays.
+	PriceDate string `json:"priceDate,omitempty"`
+	// AssetClass is "equity" or "bond" for items whose weight follows the goal's glide path.
+	AssetClass string `json:"assetClass,omitempty"`
+	// MinHoldingPeriodDays is the lock-up of the product: a redemption does not sell a
+	// holding whose HoldingSince is fewer than this many days ago.
+	MinHoldingPeriodDays string `json:"minHoldingPeriodDays,omitempty"`
+	// BlockedFromBuy puts the product on a no-buy list, e.g. a regulatory block or an ESG
+	// screen: investments never buy it, but redemptions sell it as usual.
+	BlockedFromBuy bool `json:"blockedFromBuy,omitempty"`
 }
 
 // --- Response types ---
 
+// SplitResponse is the outcome of splitting a request: one result per goal split, in
+// request order. NextCursor is set when the request was paginated with pageSize and goals
+// remain; send it back as cursor to split the next page. Currency is the currency every
+// amount is in, when the request or its goals agree on one.
+type SplitResponse struct {
+	Results    []GoalResult `json:"results"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Currency   string       `json:"currency,omitempty"`
+	Warnings   []Warning    `json:"warnings,omitempty"`
+	// ClientReference echoes the request's clientReference.
+	ClientReference string `json:"clientReference,omitempty"`
+}
+
+// Warning is a problem with a request or goal that did not stop it from being split.
+// Ticker names the product a goal warning is about, when there is one.
+type Warning struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	Ticker  string `json:"ticker,omitempty"`
+}
+
 type GoalResult struct {
-	GoalID             string              `json:"goalId"`
-	TransactionType    string              `json:"transactionType"`
-	TransactionDetails []TransactionDetail `json:"transactionDetails"`
+	GoalID               string              `json:"goalId"`
+	ClientReference      string              `json:"clientReference,omitempty"`
+	TransactionType      string              `json:"transactionType"`
+	ModelPortfolioID     string              `json:"modelPortfolioId,omitempty"`
+	OrderType            string              `json:"orderType"`
+	OrderAmount          string              `json:"orderAmount,omitempty"`
+	TransactionDetails   []TransactionDetail `json:"transactionDetails"`
+	TotalPortfolioValue  string              `json:"totalPortfolioValue,omitempty"`
+	EffectiveOrderAmount string              `json:"effectiveOrderAmount,omitempty"`
+	UnfulfilledAmount    string              `json:"unfulfilledAmount,omitempty"`
+	// FullRedemptionExcess is how much more than requested a redemption within
+	// fullRedemptionTolerance of the goal value sold by selling all of it.
+	FullRedemptionExcess string `json:"fullRedemptionExcess,omitempty"`
+	// EstimatedTax and NetAfterTax total the withholding tax figures of the goal's sells.
+	EstimatedTax string `json:"estimatedTax,omitempty"`
+	NetAfterTax  string `json:"netAfterTax,omitempty"`
+	// EstimatedRealizedGain totals the estimates of the sells that have one.
+	EstimatedRealizedGain string `json:"estimatedRealizedGain,omitempty"`
+	// TradeCount is the number of transaction details with a non-zero value, and
+	// ErrorCount the number flagged with an error.
+	TradeCount int `json:"tradeCount"`
+	ErrorCount int `json:"errorCount"`
+	// ViolationSummary lists the transaction details flagged with a minimum violation.
+	ViolationSummary []ViolationRecord `json:"violationSummary,omitempty"`
+	// SkippedProducts lists the products the goal considered but gave no trade for a
+	// structural reason.
+	SkippedProducts []SkippedProduct `json:"skippedProducts,omitempty"`
+	// Warnings lists non-fatal anomalies in the goal and its trades, unless the request
+	// sets quiet.
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Summary totals the goal's trades, fees and what is left of its order.
+	Summary          *GoalSummary      `json:"summary,omitempty"`
+	EffectiveWeights []EffectiveWeight `json:"effectiveWeights,omitempty"`
+	WhatIfResults    []WhatIfResult    `json:"whatIfResults,omitempty"`
+	// PostTradeHoldings and PostTradeTotal project the goal once the trades are placed;
+	// they are filled in when the request sets includePostTrade.
+	PostTradeHoldings []PostTradeHolding `json:"postTradeHoldings,omitempty"`
+	PostTradeTotal    string             `json:"postTradeTotal,omitempty"`
+	Debug             *GoalDebug         `json:"debug,omitempty"`
+	Error             *TradeError        `json:"error,omitempty"`
+}
+
+// GoalDebug carries diagnostic information about how a goal was processed.
+type GoalDebug struct {
+	// RepairObjective is the repair objective actually applied; it differs from the
+	// requested one when the violation count was too large for an exhaustive search.
+	RepairObjective string `json:"repairObjective,omitempty"`
+
+	// SelfCheck is the outcome of checking the result against the splitter's invariants;
+	// it is filled in when the request sets explain.
+	SelfCheck *SelfCheck `json:"selfCheck,omitempty"`
+}
+
+// SelfCheck reports which of the splitter's post-conditions a result violates.
+type SelfCheck struct {
+	Passed     bool                 `json:"passed"`
+	Violations []InvariantViolation `json:"violations,omitempty"`
+}
+
+// InvariantViolation is one post-condition a result fails, e.g. TOTAL_MISMATCH.
+type InvariantViolation struct {
+	Invariant string `json:"invariant"`
+	Ticker    string `json:"ticker,omitempty"`
+	Message   string `json:"message"`
+}
+
+// EffectiveWeight compares a product's model weight with the share of the order it
+// actually received after truncation and repair.
+type EffectiveWeight struct {
+	Ticker         string `json:"ticker"`
+	ModelWeight    string `json:"modelWeight"`
+	AchievedWeight string `json:"achievedWeight"`
+	AbsDeviation   string `json:"absDeviation"`
+}
+
+// PostTradeHolding is one product of a goal after its trades: its projected value, units
+// and share of the goal's projected total.
+type PostTradeHolding struct {
+	Ticker string `json:"ticker"`
+	Value  string `json:"value"`
+	Units  string `json:"units"`
+	Weight string `json:"weight"`
+}
+
+// GoalSummary totals the trades of a goal at amountDecimalPrecision. TotalGrossProceeds
+// and TotalNetProceeds are set on redemptions only.
+type GoalSummary struct {
+	TotalGrossValue    string `json:"totalGrossValue"`
+	TotalNetValue      string `json:"totalNetValue"`
+	TotalEstimatedFees string `json:"totalEstimatedFees"`
+	UnallocatedAmount  string `json:"unallocatedAmount"`
+	TradeCount         int    `json:"tradeCount"`
+	ViolationCount     int    `json:"violationCount"`
+	TotalGrossProceeds string `json:"totalGrossProceeds,omitempty"`
+	TotalNetProceeds   string `json:"totalNetProceeds,omitempty"`
+}
+
+// SkippedProduct is a model item or holding of a goal that received no trade, and why.
+type SkippedProduct struct {
+	Ticker  string `json:"ticker"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ViolationRecord is one trade of a goal flagged with a minimum violation: the minimum
+// amount it was checked against and the amount it was allocated.
+type ViolationRecord struct {
+	Ticker       string `json:"ticker"`
+	ErrorCode    Code   `json:"errorCode"`
+	RequiredAmt  string `json:"requiredAmt,omitempty"`
+	AllocatedAmt string `json:"allocatedAmt"`
+}
+
+// WhatIfResult summarises an investment goal split at one of the request's
+// whatIfAmounts instead of its orderAmount.
+type WhatIfResult struct {
+	OrderAmount      string            `json:"orderAmount"`
+	Violations       int               `json:"violations"`
+	Unallocated      string            `json:"unallocated"`
+	EffectiveWeights []EffectiveWeight `json:"effectiveWeights"`
 }
 
 type TransactionDetail struct {
-	Ticker    string      `json:"ticker"`
-	Direction string      `json:"direction"`
-	Value     string      `json:"value"`
-	Units     string      `json:"units"`
-	Error     *TradeError `json:"error,omitempty"`
+	Ticker    string `json:"ticker"`
+	Direction string `json:"direction"`
+	Value     string `json:"value"`
+	Units     string `json:"units"`
+	// Price echoes the marketPrice Units were derived from: the model item's, or for a
+	// redemption sell of a product without a positive model weight, the holding's.
+	Price string `json:"price,omitempty"`
+	// FullRedemption is set on a sell that closes the position. Its units are every unit
+	// held rather than value / marketPrice, so no dust is left behind.
+	FullRedemption bool `json:"fullRedemption,omitempty"`
+	// ManualInstruction is set on redemption sells of products without a market price
+	// under missingPricePolicy "manual": "units" when the sell is placed by Units, "value"
+	// when it is placed by Value and Units is empty.
+	ManualInstruction string `json:"manualInstruction,omitempty"`
+	// HoldReason is set when the request sets includeHolds, on a product reported with
+	// Direction HOLD: why it was not traded.
+	HoldReason string `json:"holdReason,omitempty"`
+	// ModelWeight, PreTradeWeight, PostTradeWeight and Drift are set when the request sets
+	// includeWeights: the product's model weight, its share of the goal before and after the
+	// trades, and the post-trade share less the model weight.
+	ModelWeight     string `json:"modelWeight,omitempty"`
+	PreTradeWeight  string `json:"preTradeWeight,omitempty"`
+	PostTradeWeight string `json:"postTradeWeight,omitempty"`
+	Drift           string `json:"drift,omitempty"`
+	// NetProceeds is set on redemptions split with proceedsMode "net": the proceeds after
+	// the transaction fee the sell was sized for. Value is the gross sell that yields them.
+	NetProceeds string `json:"netProceeds,omitempty"`
+	// EstimatedTax and NetAfterTax are set on redemption sells when withholding tax
+	// applies: the tax withheld from the proceeds after the fee, and what the client keeps.
+	EstimatedTax string `json:"estimatedTax,omitempty"`
+	NetAfterTax  string `json:"netAfterTax,omitempty"`
+	// EstimatedRealizedGain is set on redemption sells of holdings with a cost basis:
+	// (price − averageCost) × units, negative for a loss.
+	EstimatedRealizedGain string `json:"estimatedRealizedGain,omitempty"`
+	// Lots is set on redemption sells of holdings with lots: the units sold from each lot,
+	// in the order lotSelection sold them.
+	Lots  []LotSale `json:"lots,omitempty"`
+	Trace *Trace    `json:"trace,omitempty"`
+	// Error is the first rule the trade breaks, and Errors every one of them in the order
+	// they were checked, Error included: a trade can breach both its redemption and its
+	// holding minimum, or a minimum amount and a minimum number of units.
+	Error  *TradeError  `json:"error,omitempty"`
+	Errors []TradeError `json:"errors,omitempty"`
+}
+
+// LotSale is the part of a sell taken from one lot, with the gain realised on it.
+type LotSale struct {
+	AcquisitionDate string `json:"acquisitionDate"`
+	Units           string `json:"units"`
+	CostPerUnit     string `json:"costPerUnit"`
+	RealizedGain    string `json:"realizedGain"`
+}
+
+// Trace shows how a trade's value was derived. It is only filled in when the request
+// sets explain. Figures that do not apply to an order type or phase are left empty.
+type Trace struct {
+	// Phase is the redemption phase that produced the trade: "1" for zero-weight
+	// holdings sold in full, "2" for the pro-rata sale of overweight holdings.
+	Phase        string `json:"phase,omitempty"`
+	CurrentValue string `json:"currentValue"`
+	// Ideal is the unrounded amount the product should receive (investment) or the
+	// amount by which it is overweight (redemption phase 2).
+	Ideal string `json:"ideal,omitempty"`
+	// FeeAdjustedGross is Ideal grossed up for the transaction fee, before scaling to
+	// the order amount.
+	FeeAdjustedGross string `json:"feeAdjustedGross,omitempty"`
+	// GrossBeforeRepair is the truncated amount handed to the repair step, after the
+	// minimum trade check. It is not set for redemption phase 1.
+	GrossBeforeRepair string `json:"grossBeforeRepair,omitempty"`
+	// RepairAction is "bumped", "zeroed" or "reduced" when the repair step changed the
+	// amount, and RepairAdjustment the signed change it made.
+	RepairAction     string `json:"repairAction,omitempty"`
+	RepairAdjustment string `json:"repairAdjustment,omitempty"`
+	Final            string `json:"final"`
 }
 
 type TradeError struct {
 	Message string `json:"message"`
-	Code    string `json:"code"`
+	Code    Code   `json:"code"`
+	// RequiredAmount, ActualAmount and Shortfall are set on a breached amount minimum: the
+	// minimum, the amount checked against it, truncated to amountDecimalPrecision, and how
+	// far short of it that amount falls, rounded up. RequiredUnits, ActualUnits and
+	// Shortfall are set the same way, at unitDecimalPrecision, on a breached units minimum.
+	RequiredAmount string `json:"requiredAmount,omitempty"`
+	RequiredUnits  string `json:"requiredUnits,omitempty"`
+	ActualAmount   string `json:"actualAmount,omitempty"`
+	ActualUnits    string `json:"actualUnits,omitempty"`
+	Shortfall      string `json:"shortfall,omitempty"`
+	// Severity is set on a trade's errors: "error" for a hard block, "warning" for a rule
+	// the request's severityOverrides downgraded.
+	Severity string `json:"severity,omitempty"`
+}
+
+// ValidationError is one problem found in a request. Path locates the offending object
+// (e.g. "goals[0].modelPortfolioDetails[2]") and is empty for top-level fields. Field is the
+// JSON name of the offending field within it and Code classifies the problem (see the
+// validation codes of AllErrorCodes).
+type ValidationError struct {
+	Path    string `json:"path,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Code    Code   `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string { return e.Message }
+
+// UnitsRequest asks how many units a buy of Amount of a product would receive. The model
+// item carries the product's ticker, marketPrice, transactionFee and exchangeRate; its
+// other fields are ignored.
+type UnitsRequest struct {
+	ModelItem
+	Amount               string `json:"amount"`
+	UnitDecimalPrecision string `json:"unitDecimalPrecision"`
+}
+
+// UnitsResponse is the answer to a UnitsRequest.
+type UnitsResponse struct {
+	Ticker string `json:"ticker"`
+	Amount string `json:"amount"`
+	Units  string `json:"units"`
+}
+
+// DriftResponse is the answer to POST /drift: a DriftReport for every goal, in request
+// order.
+type DriftResponse struct {
+	Results []DriftReport `json:"results"`
+}
+
+// DriftReport is how far a goal's current holdings have drifted from its model, without
+// any trade. TotalDriftBps sums the drift of every product and MaxDriftBps is the largest,
+// of MaxDriftTicker. Drift is undefined for a goal that holds nothing: both are empty.
+type DriftReport struct {
+	GoalID         string         `json:"goalId"`
+	TotalValue     string         `json:"totalValue"`
+	TotalDriftBps  string         `json:"totalDriftBps,omitempty"`
+	MaxDriftBps    string         `json:"maxDriftBps,omitempty"`
+	MaxDriftTicker string         `json:"maxDriftTicker,omitempty"`
+	Products       []ProductDrift `json:"products"`
+}
+
+// ProductDrift is one product of a DriftReport. Drift is CurrentWeight less ModelWeight and
+// DriftBps its absolute value in basis points; all three are empty when the goal holds
+// nothing.
+type ProductDrift struct {
+	Ticker        string `json:"ticker"`
+	CurrentValue  string `json:"currentValue"`
+	ModelWeight   string `json:"modelWeight"`
+	CurrentWeight string `json:"currentWeight,omitempty"`
+	Drift         string `json:"drift,omitempty"`
+	DriftBps      string `json:"driftBps,omitempty"`
 }
 
 type ErrorResponse struct {
 	Message    string `json:"message"`
 	Error      string `json:"error"`
 	StatusCode int    `json:"statusCode"`
+	Code       Code   `json:"code,omitempty"`
 }
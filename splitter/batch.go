@@ -0,0 +1,242 @@
+package splitter
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// BatchWorkers is how many goals ProcessBatch splits at once. It is set from BATCH_WORKERS
+// at startup.
+var BatchWorkers = runtime.GOMAXPROCS(0)
+
+// ErrUnsupportedOrderType is wrapped by the BatchError of a goal whose orderType is neither
+// Investment nor Redemption.
+var ErrUnsupportedOrderType = errors.New("Unsupported order type")
+
+// BatchError is a goal that ProcessBatch could not split: Index is its position in the
+// request's goals.
+type BatchError struct {
+	Index  int
+	GoalID string
+	Err    error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("goals[%d] (%s): %v", e.Index, e.GoalID, e.Err)
+}
+
+func (e BatchError) Unwrap() error { return e.Err }
+
+// MinHoldingPolicy returns req's min-holding policy, lower-cased; autoFullRedemption is
+// shorthand for MinHoldingSellAll.
+func MinHoldingPolicy(req models.SplitRequest) string {
+	policy := strings.ToLower(strings.TrimSpace(req.MinHoldingPolicy))
+	if policy == "" && req.AutoFullRedemption {
+		return MinHoldingSellAll
+	}
+	return policy
+}
+
+// requestStrategy returns req's allocation strategy; allocationMode is an alias for it,
+// limited to shortfall and pro_rata, that applies when allocationStrategy is empty, and
+// ignoreCurrentHoldings always asks for pro_rata.
+func requestStrategy(req models.SplitRequest) string {
+	if req.IgnoreCurrentHoldings {
+		return StrategyProRata
+	}
+	if strings.TrimSpace(req.AllocationStrategy) == "" {
+		return req.AllocationMode
+	}
+	return req.AllocationStrategy
+}
+
+// RequestOptions returns the Options req asks for, with enumerated settings lower-cased, a
+// fresh ModelCache and Today set to now.
+func RequestOptions(req models.SplitRequest) Options {
+	return Options{
+		VolatilityBuffer:          req.VolatilityBuffer,
+		MinTradeAmt:               req.MinTradeAmt,
+		ConsolidationThreshold:    req.ConsolidationThreshold,
+		ZeroOnMinViolation:        req.ZeroOnMinViolation,
+		ResidualPolicy:            strings.ToLower(strings.TrimSpace(req.ResidualPolicy)),
+		AllocationStrategy:        requestStrategy(req),
+		IncludeEffectiveWeights:   req.IncludeEffectiveWeights,
+		LockCurrentHoldings:       req.LockCurrentHoldings,
+		IgnoreCurrentHoldings:     req.IgnoreCurrentHoldings,
+		RepairObjective:           strings.ToLower(strings.TrimSpace(req.RepairObjective)),
+		RepairStrategy:            strings.ToLower(strings.TrimSpace(req.RepairStrategy)),
+		MinProgressGuard:          req.MinProgressGuard,
+		TieBreak:                  strings.ToLower(strings.TrimSpace(req.TieBreak)),
+		ZeroOutMaxWeight:          req.ZeroOutMaxWeight,
+		NormalizeWeights:          req.NormalizeWeights,
+		ShrinkageCoefficient:      req.ShrinkageCoefficient,
+		MinWeightDeviationTrigger: req.MinWeightDeviationTrigger,
+		MinHoldingPolicy:          MinHoldingPolicy(req),
+		RebuildBigRedemption:      req.RebuildBigRedemption,
+		ProceedsMode:              strings.ToLower(strings.TrimSpace(req.ProceedsMode)),
+		RedemptionMinimumBasis:    strings.ToLower(strings.TrimSpace(req.RedemptionMinimumBasis)),
+		Phase1Order:               strings.ToLower(strings.TrimSpace(req.Phase1Order)),
+		FullRedemptionTolerance:   strings.TrimSpace(req.FullRedemptionTolerance),
+		AllowMissingPrice:         req.AllowMissingPrice,
+		MissingPricePolicy:        strings.ToLower(strings.TrimSpace(req.MissingPricePolicy)),
+		LotSelection:              strings.ToLower(strings.TrimSpace(req.LotSelection)),
+		ScaleLotUnits:             req.ScaleLotUnits,
+		Explain:                   req.Explain,
+		WhatIfAmounts:             req.WhatIfAmounts,
+		ModelCache:                NewModelPortfolioCache(),
+		Today:                     time.Now(),
+	}
+}
+
+// WeightPrecisions returns the decimal places weights are reported at, weightDecimalPrecision
+// or DefaultWeightPrec, and those they are rounded to before splitting, weightPrecision or
+// -1 when it is not set. req must already have been validated.
+func WeightPrecisions(req models.SplitRequest) (weightPrec, roundWeights int) {
+	weightPrec, roundWeights = DefaultWeightPrec, -1
+	if s := strings.TrimSpace(req.WeightDecimalPrecision); s != "" {
+		weightPrec, _ = strconv.Atoi(s)
+	}
+	if s := strings.TrimSpace(req.WeightPrecision); s != "" {
+		roundWeights, _ = strconv.Atoi(s)
+	}
+	return weightPrec, roundWeights
+}
+
+// batch holds the request-level settings every goal of a ProcessBatch is split with.
+type batch struct {
+	req                  models.SplitRequest
+	opts                 Options
+	amountPrec, unitPrec int
+	maxPriceAge          int // -1 when maxPriceAgeDays is not set
+	staleWarnDays        int // -1 when maxPriceAgeDays flags stale prices instead
+	weightPrec           int
+	roundWeights         int // -1 when weightPrecision is not set
+	recomputeValues      bool
+}
+
+// ProcessBatch splits every goal of req on a pool of BatchWorkers goroutines and returns the
+// results of those it split, in request order, with a BatchError, by Index, for each it
+// could not. req must already have been validated, as api.Split does; amountPrec and
+// unitPrec are its parsed decimal precisions.
+//
+// Each goal is split on its glide path and weightPrecision, with its holding values
+// recomputed under recomputeHoldingValues, then its result is completed as the request
+// asks: stale prices flagged, prices echoed, trades counted and summarised, warnings raised
+// unless quiet, and post-trade holdings, trade weights, the self-check, HOLD directions and
+// ticker order added as requested. Strict mode is left to the caller.
+func ProcessBatch(req models.SplitRequest, amountPrec, unitPrec int) ([]models.GoalResult, []BatchError) {
+	b := batch{
+		req: req, opts: RequestOptions(req),
+		amountPrec: amountPrec, unitPrec: unitPrec,
+		maxPriceAge: -1, staleWarnDays: StalePriceWarningDays,
+		recomputeValues: req.RecomputeHoldingValues,
+	}
+	if s := strings.TrimSpace(req.MaxPriceAgeDays); s != "" {
+		b.maxPriceAge, _ = strconv.Atoi(s)
+		// Prices flagged by maxPriceAgeDays are not warned about as well.
+		b.staleWarnDays = -1
+	}
+	b.weightPrec, b.roundWeights = WeightPrecisions(req)
+
+	results := make([]models.GoalResult, len(req.Goals))
+	failed := make([]error, len(req.Goals))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(BatchWorkers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], failed[i] = b.process(req.Goals[i])
+			}
+		}()
+	}
+	for i := range req.Goals {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var out []models.GoalResult
+	var errs []BatchError
+	for i, err := range failed {
+		if err != nil {
+			errs = append(errs, BatchError{Index: i, GoalID: req.Goals[i].GoalID, Err: err})
+			continue
+		}
+		out = append(out, results[i])
+	}
+	return out, errs
+}
+
+// process splits one goal of the batch. A panic while splitting it is returned as its
+// error, so that it fails the goal rather than the process.
+func (b batch) process(goal models.Goal) (result models.GoalResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("splitting goal: %v", r)
+		}
+	}()
+	req, opts := b.req, b.opts
+	amountPrec, unitPrec := b.amountPrec, b.unitPrec
+
+	goal = ApplyGlidePath(goal, opts.Today)
+	if b.roundWeights >= 0 {
+		goal = RoundWeights(goal, b.roundWeights)
+	}
+	var recomputed []models.Warning
+	if b.recomputeValues {
+		goal, recomputed = RecomputeHoldingValues(goal, amountPrec)
+	}
+	switch strings.ToLower(goal.OrderType) {
+	case "investment":
+		result = ProcessInvestment(goal, amountPrec, unitPrec, opts)
+	case "redemption":
+		result = ProcessRedemption(goal, amountPrec, unitPrec, opts)
+	default:
+		return models.GoalResult{}, fmt.Errorf("%w: %s", ErrUnsupportedOrderType, goal.OrderType)
+	}
+	if b.maxPriceAge >= 0 {
+		result = FlagStalePrices(goal, result, b.maxPriceAge, opts.Today)
+	}
+	result = ApplySeverities(result, req.SeverityOverrides)
+	result = EchoOrder(goal, result)
+	result = EchoPrices(goal, result)
+	result.TradeCount, result.ErrorCount = CountTrades(result.TransactionDetails)
+	result.ViolationSummary = SummarizeViolations(goal, result, opts)
+	result.SkippedProducts = SkippedProducts(goal, result)
+	result.Summary = Summarize(goal, result, amountPrec, opts)
+	if !req.Quiet {
+		result.Warnings = append(append(recomputed, goalWarnings(goal, opts)...), TradeWarnings(goal, result, b.staleWarnDays, opts.Today)...)
+	}
+	if req.IncludePostTrade {
+		result = PostTrade(goal, result, amountPrec, unitPrec)
+	}
+	if req.IncludeWeights {
+		result = AddTradeWeights(goal, result, amountPrec, unitPrec, b.weightPrec, opts)
+	}
+	if opts.Explain {
+		check := VerifyResult(goal, result, amountPrec, unitPrec, opts)
+		if result.Debug == nil {
+			result.Debug = &models.GoalDebug{}
+		}
+		result.Debug.SelfCheck = &check
+	}
+	if req.IncludeHolds {
+		result = MarkHolds(goal, result, amountPrec, unitPrec, opts)
+	}
+	if req.SortOutputByTicker {
+		sort.SliceStable(result.TransactionDetails, func(i, j int) bool {
+			return result.TransactionDetails[i].Ticker < result.TransactionDetails[j].Ticker
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestGoalVolatilityBufferOverridesRequest(t *testing.T) {
+	// Each goal redeems 950 of the 1000 it holds. Under the request's 2% buffer that is
+	// below the 980 threshold, a Small Redemption; the aggressive goal's 10% buffer puts
+	// the threshold at 900, making the same order a Big Redemption.
+	goal := func(id, buffer string) models.Goal {
+		return testhelpers.NewGoalBuilder(id).AsRedemption().
+			WithOrderAmount("950").
+			WithVolatilityBuffer(buffer).
+			WithTicker("A", "1", "1").
+			WithHolding("A", "1000", "1").
+			Build()
+	}
+	req := models.SplitRequest{
+		VolatilityBuffer: "0.02",
+		Goals:            []models.Goal{goal("conservative", ""), goal("aggressive", "0.10")},
+	}
+	results, errs := ProcessBatch(req, 2, 4)
+	if len(errs) != 0 {
+		t.Fatalf("batch errors: %v", errs)
+	}
+	want := map[string]string{"conservative": "Small Redemption", "aggressive": "Big Redemption"}
+	for _, r := range results {
+		if r.TransactionType != want[r.GoalID] {
+			t.Errorf("%s classified %q, want %q", r.GoalID, r.TransactionType, want[r.GoalID])
+		}
+	}
+	if len(results) != len(want) {
+		t.Errorf("got %d results, want %d", len(results), len(want))
+	}
+}
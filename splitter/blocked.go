@@ -0,0 +1,68 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// freeHolding returns the value and units of h that may be sold: its units less
+// BlockedUnits, floored at 0, and the same share of its value. h must already have its
+// pending sells deducted (see sellableHoldings).
+func freeHolding(h models.Holding) (value, units decimal.Decimal) {
+	value, _ = decimal.NewFromString(h.Value)
+	units, _ = decimal.NewFromString(h.Units)
+	blocked, _ := decimal.NewFromString(h.BlockedUnits)
+	if !blocked.IsPositive() || !units.IsPositive() {
+		return value, units
+	}
+	free := decimal.Max(units.Sub(blocked), decimal.Zero)
+	return div(value.Mul(free), units), free
+}
+
+// isBlocked reports whether part of h cannot be sold.
+func isBlocked(h models.Holding) bool {
+	blocked, _ := decimal.NewFromString(h.BlockedUnits)
+	return blocked.IsPositive()
+}
+
+// flagBlockedUnits flags with models.CodeBlockedUnitsLimit every sell in result that sold all
+// the free value of a holding with blocked units. A blocked holding with nothing free and
+// no sell gets a zero sell carrying the flag, so that every block that held the order back
+// is reported. A sell already flagged keeps its error, with the flag added after it in
+// Errors. holdings are the holdings the redemption was split on; kept holdings are not
+// reported.
+func flagBlockedUnits(holdings []models.Holding, result models.GoalResult, amountPrec, unitPrec int, keepUnmodeled bool, weights map[string]decimal.Decimal) models.GoalResult {
+	flag := models.TradeError{
+		Message: "Cannot sell more of this ticker because the rest of the holding is blocked",
+		Code:    models.CodeBlockedUnitsLimit,
+	}
+	prec := int32(amountPrec)
+	seen := make(map[string]bool)
+	for _, d := range result.TransactionDetails {
+		seen[d.Ticker] = true
+	}
+	for _, h := range holdings {
+		if !isBlocked(h) || (weights[h.Ticker].IsZero() && (h.Keep || keepUnmodeled)) {
+			continue
+		}
+		free, _ := freeHolding(h)
+		if !seen[h.Ticker] {
+			if !free.Truncate(prec).IsPositive() {
+				result.TransactionDetails = append(result.TransactionDetails, withErrors(models.TransactionDetail{
+					Ticker:    h.Ticker,
+					Direction: "SELL",
+					Value:     decimal.Zero.StringFixed(prec),
+					Units:     decimal.Zero.StringFixed(int32(unitPrec)),
+				}, flag))
+			}
+			continue
+		}
+		for i, d := range result.TransactionDetails {
+			value, _ := decimal.NewFromString(d.Value)
+			if d.Ticker == h.Ticker && value.GreaterThanOrEqual(free.Truncate(prec)) {
+				result.TransactionDetails[i] = withErrors(d, flag)
+			}
+		}
+	}
+	return result
+}
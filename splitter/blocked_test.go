@@ -0,0 +1,30 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestPhase1BlockedUnitsLeaveTheRestUnfulfilled(t *testing.T) {
+	// X is outside the model, so Phase 1 sells it first, but half of it is blocked: only 500
+	// of the 900 order can be sold, and A, the only model product, is not held.
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithOrderAmount("900").
+		WithTicker("A", "1", "1").
+		WithHolding("X", "100", "10").
+		WithBlockedUnits("X", "50").
+		Build()
+	result := ProcessRedemption(goal, 2, 4, Options{})
+	got := byTicker(result)
+	if got["X"].Value != "500.00" || got["X"].Units != "50.0000" {
+		t.Errorf("X = %s (%s units), want 500.00 (50.0000 units)", got["X"].Value, got["X"].Units)
+	}
+	if code := errorCode(got["X"]); code != models.CodeBlockedUnitsLimit {
+		t.Errorf("X flagged %q, want %s", code, models.CodeBlockedUnitsLimit)
+	}
+	if result.UnfulfilledAmount != "400.00" {
+		t.Errorf("unfulfilledAmount = %q, want 400.00", result.UnfulfilledAmount)
+	}
+}
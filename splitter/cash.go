@@ -0,0 +1,70 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Cash drawdowns are reported as a transaction detail of their own, with this ticker and
+// direction, ahead of the sells.
+const (
+	CashTicker = "CASH"
+	DirCashOut = "CASH_OUT"
+)
+
+// cashBalance parses goal.CashBalance; empty means no cash.
+func cashBalance(goal models.Goal) decimal.Decimal {
+	d, _ := decimal.NewFromString(strings.TrimSpace(goal.CashBalance))
+	return decimal.Max(d, decimal.Zero)
+}
+
+// cashRedemption splits a redemption from a goal with a cash balance. Cash is drawn first,
+// up to the order, and reported as a CASH_OUT detail with no units; only the rest of the
+// order is split over the holdings. The goal is classified, and its totalPortfolioValue
+// reported, on its holdings and cash together.
+func cashRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	prec := int32(amountPrec)
+	cash := cashBalance(goal)
+	orderAmount, _ := decimal.NewFromString(goal.OrderAmount)
+	drawn := decimal.Min(cash, orderAmount)
+
+	rest := goal
+	rest.CashBalance = ""
+	rest.OrderAmount = orderAmount.Sub(drawn).StringFixed(prec)
+	var result models.GoalResult
+	if orderAmount.GreaterThan(drawn) {
+		result = ProcessRedemption(rest, amountPrec, unitPrec, opts)
+	} else {
+		result = models.GoalResult{GoalID: goal.GoalID, TotalPortfolioValue: sellableValue(goal.GoalDetails).StringFixed(prec)}
+	}
+
+	vTotal, _ := decimal.NewFromString(result.TotalPortfolioValue)
+	vTotal = vTotal.Add(cash)
+	result.TransactionType = redemptionType(orderAmount, vTotal, opts.VolatilityBuffer)
+	result.TotalPortfolioValue = vTotal.StringFixed(prec)
+	if result.EffectiveOrderAmount != "" {
+		effective, _ := decimal.NewFromString(result.EffectiveOrderAmount)
+		result.EffectiveOrderAmount = effective.Add(drawn).StringFixed(prec)
+	}
+	cashOut := models.TransactionDetail{
+		Ticker:    CashTicker,
+		Direction: DirCashOut,
+		Value:     drawn.StringFixed(prec),
+	}
+	result.TransactionDetails = append([]models.TransactionDetail{cashOut}, result.TransactionDetails...)
+	return result
+}
+
+// sellableValue returns the total positive value of holdings after pending sells, the
+// V_total a redemption of them is split against.
+func sellableValue(holdings []models.Holding) decimal.Decimal {
+	total := decimal.Zero
+	for _, h := range sellableHoldings(holdings) {
+		if v, _ := decimal.NewFromString(h.Value); v.IsPositive() {
+			total = total.Add(v)
+		}
+	}
+	return total
+}
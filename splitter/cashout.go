@@ -0,0 +1,109 @@
+package splitter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// cashOutRedemption liquidates a goal flagged CashOut, without splitting to model weights.
+// Holdings are sold whole, largest value after pending sells first, until orderAmount is
+// met: the sell that meets it is partial and those after it sell nothing. Without an
+// orderAmount every holding is sold whole. Blocked units are never sold.
+//
+// A partial sell is converted to units at its model item's price when it has a positive
+// model weight, and at the holding's otherwise; a whole sell reports every free unit. Sells
+// are checked against the minimums of their model item, or of the holding outside the
+// model, and flagged, not dropped, when they breach one. The goal is always classified as a
+// Full Redemption, and the total sold is reported as effectiveOrderAmount.
+func cashOutRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	prec := int32(amountPrec)
+	modelMap := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		modelMap[mp.Ticker] = mp
+	}
+
+	var holdings []models.Holding
+	vTotal, freeTotal := decimal.Zero, decimal.Zero
+	for _, h := range sellableHoldings(goal.GoalDetails) {
+		if value, _ := decimal.NewFromString(h.Value); value.IsPositive() {
+			holdings = append(holdings, h)
+			vTotal = vTotal.Add(value)
+			free, _ := freeHolding(h)
+			freeTotal = freeTotal.Add(free.Truncate(prec))
+		}
+	}
+	sort.SliceStable(holdings, func(i, j int) bool {
+		vi, _ := decimal.NewFromString(holdings[i].Value)
+		vj, _ := decimal.NewFromString(holdings[j].Value)
+		return vi.GreaterThan(vj)
+	})
+
+	orderAmount, err := decimal.NewFromString(strings.TrimSpace(goal.OrderAmount))
+	if err != nil {
+		orderAmount = freeTotal
+	}
+	remaining, total := orderAmount, decimal.Zero
+	var details []models.TransactionDetail
+	for _, h := range holdings {
+		free, freeUnits := freeHolding(h)
+		free = free.Truncate(prec)
+		redeemAmt, units := decimal.Min(free, remaining), freeUnits
+		full := redeemAmt.Equal(free)
+		if !full {
+			price, _ := decimal.NewFromString(h.MarketPrice)
+			mp, inModel := modelMap[h.Ticker]
+			if w, _ := decimal.NewFromString(mp.Weight); inModel && w.IsPositive() {
+				price, _ = decimal.NewFromString(mp.MarketPrice)
+			}
+			units = decimal.Zero
+			if price.IsPositive() {
+				units = div(redeemAmt, price).Truncate(int32(unitPrec))
+			}
+		}
+
+		minimums := models.ModelItem{
+			MinRedemptionAmt: h.MinRedemptionAmt, MinRedemptionUnits: h.MinRedemptionUnits,
+			MinHoldingAmt: h.MinHoldingAmt, MinHoldingUnits: h.MinHoldingUnits,
+		}
+		if mp, inModel := modelMap[h.Ticker]; inModel {
+			minimums = mp
+		}
+		var tradeErrs []models.TradeError
+		if redeemAmt.IsPositive() {
+			tradeErrs = checkRedemptionMinimums(
+				redeemAmt, units,
+				full,
+				h.Value, h.Units,
+				minimums.MinRedemptionAmt, minimums.MinRedemptionUnits,
+				minimums.MinHoldingAmt, minimums.MinHoldingUnits,
+				amountPrec, unitPrec,
+			)
+		}
+
+		remaining = remaining.Sub(redeemAmt)
+		total = total.Add(redeemAmt)
+		details = append(details, withErrors(models.TransactionDetail{
+			Ticker:         h.Ticker,
+			Direction:      "SELL",
+			Value:          redeemAmt.StringFixed(prec),
+			Units:          units.StringFixed(int32(unitPrec)),
+			FullRedemption: full && redeemAmt.IsPositive() && !isBlocked(h),
+		}, tradeErrs...))
+	}
+
+	result := models.GoalResult{
+		GoalID:               goal.GoalID,
+		TransactionType:      "Full Redemption",
+		TransactionDetails:   details,
+		TotalPortfolioValue:  vTotal.StringFixed(prec),
+		EffectiveOrderAmount: total.StringFixed(prec),
+	}
+	if remaining.IsPositive() {
+		result.UnfulfilledAmount = remaining.StringFixed(prec)
+		result.EffectiveOrderAmount = orderAmount.StringFixed(prec)
+	}
+	return realizedGains(goal, withholdingTax(goal, result, amountPrec, opts), amountPrec, unitPrec, opts)
+}
@@ -0,0 +1,92 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// smallPositions returns the held products of goal whose value after pending sells is
+// positive and below opts.ConsolidationThreshold. Kept holdings with a zero or absent model
+// weight are left out, since they are never sold.
+func smallPositions(goal models.Goal, opts Options) map[string]bool {
+	threshold, err := decimal.NewFromString(strings.TrimSpace(opts.ConsolidationThreshold))
+	if err != nil || !threshold.IsPositive() {
+		return nil
+	}
+	weights := make(map[string]decimal.Decimal)
+	for _, mp := range goal.ModelPortfolioDetails {
+		weights[mp.Ticker], _ = decimal.NewFromString(mp.Weight)
+	}
+	out := make(map[string]bool)
+	for _, h := range sellableHoldings(goal.GoalDetails) {
+		value, _ := decimal.NewFromString(h.Value)
+		if !value.IsPositive() || !value.LessThan(threshold) {
+			continue
+		}
+		if weights[h.Ticker].IsZero() && (h.Keep || goal.KeepUnmodeled) {
+			continue
+		}
+		out[h.Ticker] = true
+	}
+	return out
+}
+
+// consolidatedRedemption splits a redemption whose goal holds positions below
+// opts.ConsolidationThreshold. Each is given a zero model weight, so that Phase 1 sells it
+// in full ahead of the split, and the weight it had is shared among the other model items
+// in proportion to theirs. Its holding is priced from its model item, as it would have been
+// in Phase 2. Every sell of such a position is flagged models.CodeConsolidated.
+func consolidatedRedemption(goal models.Goal, small map[string]bool, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	consolidated := goal
+	items := make([]models.ModelItem, len(goal.ModelPortfolioDetails))
+	copy(items, goal.ModelPortfolioDetails)
+	prices := make(map[string]string)
+	total, rest := decimal.Zero, decimal.Zero
+	for _, mp := range items {
+		w, _ := decimal.NewFromString(mp.Weight)
+		total = total.Add(w)
+		if !small[mp.Ticker] {
+			rest = rest.Add(w)
+		} else if w.IsPositive() {
+			prices[mp.Ticker] = mp.MarketPrice
+		}
+	}
+	for i, mp := range items {
+		w, _ := decimal.NewFromString(mp.Weight)
+		if small[mp.Ticker] {
+			items[i].Weight = "0"
+		} else if rest.IsPositive() && w.IsPositive() {
+			items[i].Weight = div(w.Mul(total), rest).String()
+		}
+	}
+	consolidated.ModelPortfolioDetails = items
+
+	// Kept holdings stay kept, but goal-wide keepUnmodeled must not keep the positions that
+	// only now have a zero weight.
+	holdings := make([]models.Holding, len(goal.GoalDetails))
+	for i, h := range goal.GoalDetails {
+		if price, ok := prices[h.Ticker]; ok {
+			h.MarketPrice = price
+		}
+		if goal.KeepUnmodeled && !small[h.Ticker] {
+			h.Keep = true
+		}
+		holdings[i] = h
+	}
+	consolidated.GoalDetails = holdings
+	consolidated.KeepUnmodeled = false
+
+	opts.ConsolidationThreshold = ""
+	result := ProcessRedemption(consolidated, amountPrec, unitPrec, opts)
+	for i, d := range result.TransactionDetails {
+		if small[d.Ticker] && d.Direction == "SELL" && d.Error == nil {
+			result.TransactionDetails[i] = withErrors(d, models.TradeError{
+				Message: "Sold in full to consolidate a position below consolidationThreshold",
+				Code:    models.CodeConsolidated,
+			})
+		}
+	}
+	return result
+}
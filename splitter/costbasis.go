@@ -0,0 +1,80 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// averageCost returns the cost per unit of h: its averageCost, or its totalCost spread over
+// its units. ok is false when h has no usable cost basis.
+func averageCost(h models.Holding) (cost decimal.Decimal, ok bool) {
+	if s := strings.TrimSpace(h.AverageCost); s != "" {
+		cost, err := decimal.NewFromString(s)
+		return cost, err == nil
+	}
+	total, err := decimal.NewFromString(strings.TrimSpace(h.TotalCost))
+	units, _ := decimal.NewFromString(h.Units)
+	if err != nil || !units.IsPositive() {
+		return decimal.Zero, false
+	}
+	return div(total, units), true
+}
+
+// realizedGains estimates the gain realised by every sell in result against the cost basis
+// of its holding, (price − averageCost) × units, rounded to amountPrec. The price is the one
+// the sell's units were derived from: the model price for products with a positive model
+// weight and the holding price for the rest. A holding with lots is sold lot by lot (see
+// sellLots) and its estimate is the sum of theirs. Sells of holdings without a cost basis
+// are left without an estimate; the goal carries the total of the others, if any.
+//
+// goal must hold the holdings as sent, before pending sells take units off them.
+func realizedGains(goal models.Goal, result models.GoalResult, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	costs := make(map[string]decimal.Decimal)
+	held := make(map[string]models.Holding)
+	prices := make(map[string]string)
+	for _, h := range goal.GoalDetails {
+		if cost, ok := averageCost(h); ok || len(h.Lots) > 0 {
+			costs[h.Ticker] = cost
+		}
+		held[h.Ticker] = h
+		prices[h.Ticker] = h.MarketPrice
+	}
+	if len(costs) == 0 {
+		return result
+	}
+	for _, mp := range goal.ModelPortfolioDetails {
+		if w, _ := decimal.NewFromString(mp.Weight); w.IsPositive() {
+			prices[mp.Ticker] = mp.MarketPrice
+		}
+	}
+
+	prec := int32(amountPrec)
+	total, estimated := decimal.Zero, false
+	for i, d := range result.TransactionDetails {
+		cost, ok := costs[d.Ticker]
+		if d.Direction != "SELL" || !ok {
+			continue
+		}
+		price, _ := decimal.NewFromString(prices[d.Ticker])
+		units, _ := decimal.NewFromString(d.Units)
+		gain := price.Sub(cost).Mul(units).Round(prec)
+		if h := held[d.Ticker]; len(h.Lots) > 0 {
+			heldUnits, _ := decimal.NewFromString(h.Units)
+			d.Lots = sellLots(h.Lots, units, price, heldUnits, amountPrec, unitPrec, opts)
+			gain = decimal.Zero
+			for _, l := range d.Lots {
+				g, _ := decimal.NewFromString(l.RealizedGain)
+				gain = gain.Add(g)
+			}
+		}
+		d.EstimatedRealizedGain = gain.StringFixed(prec)
+		total, estimated = total.Add(gain), true
+		result.TransactionDetails[i] = d
+	}
+	if estimated {
+		result.EstimatedRealizedGain = total.StringFixed(prec)
+	}
+	return result
+}
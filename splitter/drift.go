@@ -0,0 +1,68 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// bps is the number of basis points in a weight of 1.
+var bps = decimal.NewFromInt(10000)
+
+// GoalDrift reports how far goal has drifted from its model, without computing any trade.
+// Every model item and holding is listed, in model order and then holding order, with any
+// cash balance last under CashTicker, as by projectTrades before its trades.
+//
+// A product's current weight is its share of the goal once pending orders settle, cash
+// included, and its model weight the weight the goal would be split on, 0 outside the model
+// or for cash, both rounded to weightPrec decimal places. driftBps_i is
+// |currentWeight_i − modelWeight_i| × 10000, worked out before rounding and shown to
+// weightPrec − 4 decimal places. A goal that holds nothing has no current weights, so its
+// products report only their value and model weight, and the goal no drift at all.
+func GoalDrift(goal models.Goal, amountPrec, unitPrec, weightPrec int, opts Options) models.DriftReport {
+	model := make(map[string]decimal.Decimal)
+	for i, w := range modelWeights(goal, opts) {
+		model[goal.ModelPortfolioDetails[i].Ticker] = w
+	}
+	positions := projectTrades(goal, models.GoalResult{}, amountPrec, unitPrec)
+	total := decimal.Zero
+	for _, p := range positions {
+		total = total.Add(p.preValue)
+	}
+
+	prec, bpsPrec := int32(weightPrec), int32(max(weightPrec-4, 0))
+	report := models.DriftReport{
+		GoalID:     goal.GoalID,
+		TotalValue: total.StringFixed(int32(amountPrec)),
+		Products:   make([]models.ProductDrift, len(positions)),
+	}
+	totalBps, maxBps := decimal.Zero, decimal.Zero
+	for i, p := range positions {
+		modelWeight := model[p.ticker]
+		if p.cash {
+			modelWeight = decimal.Zero
+		}
+		report.Products[i] = models.ProductDrift{
+			Ticker:       p.ticker,
+			CurrentValue: p.preValue.StringFixed(int32(amountPrec)),
+			ModelWeight:  modelWeight.StringFixed(prec),
+		}
+		if !total.IsPositive() {
+			continue
+		}
+		current := div(p.preValue, total)
+		drift := current.Sub(modelWeight)
+		driftBps := drift.Abs().Mul(bps)
+		report.Products[i].CurrentWeight = current.StringFixed(prec)
+		report.Products[i].Drift = drift.StringFixed(prec)
+		report.Products[i].DriftBps = driftBps.StringFixed(bpsPrec)
+		totalBps = totalBps.Add(driftBps)
+		if driftBps.GreaterThan(maxBps) {
+			maxBps, report.MaxDriftTicker = driftBps, p.ticker
+		}
+	}
+	if total.IsPositive() {
+		report.TotalDriftBps = totalBps.StringFixed(bpsPrec)
+		report.MaxDriftBps = maxBps.StringFixed(bpsPrec)
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestGoalDrift(t *testing.T) {
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithTicker("A", "0.5", "1").
+		WithTicker("B", "0.5", "1").
+		WithHolding("A", "3000", "1").
+		WithHolding("B", "1000", "1").
+		Build()
+	goal.CashBalance = "50"
+	report := GoalDrift(goal, 2, 4, DefaultWeightPrec, Options{})
+	if report.TotalValue != "4050.00" || report.TotalDriftBps != "5061.73" || report.MaxDriftBps != "2530.86" || report.MaxDriftTicker != "B" {
+		t.Errorf("report = %s total, %s bps, max %s bps of %s; want 4050.00, 5061.73, 2530.86 of B",
+			report.TotalValue, report.TotalDriftBps, report.MaxDriftBps, report.MaxDriftTicker)
+	}
+	want := []models.ProductDrift{
+		{Ticker: "A", CurrentValue: "3000.00", ModelWeight: "0.500000", CurrentWeight: "0.740741", Drift: "0.240741", DriftBps: "2407.41"},
+		{Ticker: "B", CurrentValue: "1000.00", ModelWeight: "0.500000", CurrentWeight: "0.246914", Drift: "-0.253086", DriftBps: "2530.86"},
+		{Ticker: CashTicker, CurrentValue: "50.00", ModelWeight: "0.000000", CurrentWeight: "0.012346", Drift: "0.012346", DriftBps: "123.46"},
+	}
+	assertProductDrifts(t, report.Products, want)
+}
+
+func TestGoalDriftIsUndefinedWithoutHoldings(t *testing.T) {
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithTicker("A", "0.5", "1").
+		WithTicker("B", "0.5", "1").
+		Build()
+	report := GoalDrift(goal, 2, 4, DefaultWeightPrec, Options{})
+	if report.TotalDriftBps != "" || report.MaxDriftBps != "" || report.MaxDriftTicker != "" {
+		t.Errorf("report = %q total bps, max %q of %q; want no drift", report.TotalDriftBps, report.MaxDriftBps, report.MaxDriftTicker)
+	}
+	want := []models.ProductDrift{
+		{Ticker: "A", CurrentValue: "0.00", ModelWeight: "0.500000"},
+		{Ticker: "B", CurrentValue: "0.00", ModelWeight: "0.500000"},
+	}
+	assertProductDrifts(t, report.Products, want)
+}
+
+func assertProductDrifts(t *testing.T, got, want []models.ProductDrift) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d products, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("product %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestTradeReportsEveryBreachedRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		result models.GoalResult
+		want   []models.Code
+	}{
+		{
+			// A buy of 100 into a position of 50 is below the 200 top-up minimum and leaves
+			// 150, below the 500 holding minimum.
+			name: "investment",
+			result: ProcessInvestment(testhelpers.NewGoalBuilder("g1").
+				WithOrderAmount("100").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("1").
+					WithMinTopup("200", "0").WithMinHolding("500", "0").Build()).
+				WithHolding("A", "50", "1").
+				Build(), 2, 4, Options{}),
+			want: []models.Code{models.CodeMinTopupViolation, models.CodeMinHoldingViolation},
+		},
+		{
+			// A sell of 50 from a position of 100 is below the 60 redemption minimum and
+			// leaves 50, below the 80 holding minimum.
+			name: "redemption",
+			result: ProcessRedemption(testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithOrderAmount("50").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("1").
+					WithMinRedemption("60", "0").WithMinHolding("80", "0").Build()).
+				WithHolding("A", "100", "1").
+				Build(), 2, 4, Options{}),
+			want: []models.Code{models.CodeMinRedemptionViolation, models.CodeMinHoldingViolation},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := byTicker(tt.result)["A"]
+			got := errorCodes(d)
+			if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			if d.Error == nil || *d.Error != d.Errors[0] {
+				t.Errorf("error = %+v, want the first of errors, %+v", d.Error, d.Errors[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestTradeFees(t *testing.T) {
+	// A charges 0.25% on a buy and 0.75% on a sell, and B charges nothing.
+	feeA := testhelpers.NewModelItemBuilder("A").WithWeight("0.5").
+		WithTransactionFee("0.0025").WithRedemptionFee("0.0075").Build()
+	noFeeB := testhelpers.NewModelItemBuilder("B").WithWeight("0.5").Build()
+	tests := []struct {
+		name     string
+		goal     models.Goal
+		process  func(models.Goal, int, int, Options) models.GoalResult
+		want     map[string]string
+		proceeds map[string]string
+	}{
+		{
+			// A's ideal of 500 is grossed up by 1/(1 − 0.0025) against B's 500, which
+			// splits the 1000 as 500.62 and 499.37. With the exit fee it would have been
+			// 501.88 and 498.11.
+			name: "investment uses transactionFee",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(feeA).WithModelItem(noFeeB).
+				WithHolding("A", "5000", "1").
+				WithHolding("B", "5000", "1").
+				Build(),
+			process: ProcessInvestment,
+			want:    map[string]string{"A": "500.62", "B": "499.37"},
+		},
+		{
+			// Sized on proceeds, A's 5000 is worth 4962.50 after the 0.75% exit fee against
+			// B's 5000, so of the 8962.50 left each should keep 4481.25: A sells 481.25 of
+			// proceeds, 484.89 gross. With the 0.25% purchase fee it would have sold 493.75,
+			// 494.99 gross.
+			name: "redemption uses redemptionFee",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithModelItem(feeA).WithModelItem(noFeeB).
+				WithHolding("A", "5000", "1").
+				WithHolding("B", "5000", "1").
+				Build(),
+			process:  ProcessRedemption,
+			want:     map[string]string{"A": "484.89", "B": "518.75"},
+			proceeds: map[string]string{"A": "481.25", "B": "518.75"},
+		},
+		{
+			// Without a redemptionFee the sell is grossed up by the 0.25% transactionFee:
+			// 1000/0.9975 = 1002.506…, rounded up so the proceeds reach 1000.
+			name: "redemptionFee falls back to transactionFee",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("1").
+					WithTransactionFee("0.0025").Build()).
+				WithHolding("A", "5000", "1").
+				Build(),
+			process:  ProcessRedemption,
+			want:     map[string]string{"A": "1002.51"},
+			proceeds: map[string]string{"A": "1000.00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := byTicker(tt.process(tt.goal, 2, 4, Options{ProceedsMode: ProceedsNet}))
+			assertValues(t, got, tt.want)
+			for ticker, proceeds := range tt.proceeds {
+				if got[ticker].NetProceeds != proceeds {
+					t.Errorf("%s proceeds = %s, want %s", ticker, got[ticker].NetProceeds, proceeds)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// applyAllocationFloors reserves orderAmount × allocationFloor_i of the order for every
+// product that has a floor and was not dropped, and scales the other ideals down so that
+// they share what is left: ideal_i' = floor_i × orderAmount + ideal_i × (orderAmount − F) / totalIdeal,
+// where F is the sum of the reserved amounts. Σ ideal' is then orderAmount.
+// Floors are validated to sum to at most 1.
+func applyAllocationFloors(allocs []productAlloc, dropped map[int][]models.TradeError, orderAmount, totalIdeal decimal.Decimal) {
+	reserved := make([]decimal.Decimal, len(allocs))
+	totalReserved := decimal.Zero
+	for i, a := range allocs {
+		if dropped[i] != nil {
+			continue
+		}
+		floor, _ := decimal.NewFromString(strings.TrimSpace(a.mp.AllocationFloor))
+		reserved[i] = floor.Mul(orderAmount)
+		totalReserved = totalReserved.Add(reserved[i])
+	}
+	if !totalReserved.IsPositive() || !totalIdeal.IsPositive() {
+		return
+	}
+	rest := orderAmount.Sub(totalReserved)
+	for i, a := range allocs {
+		allocs[i].ideal = reserved[i].Add(div(a.ideal.Mul(rest), totalIdeal))
+	}
+}
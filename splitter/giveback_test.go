@@ -0,0 +1,72 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestGiveBackCeiling(t *testing.T) {
+	// An investment of 1000 into a goal that also holds some Z, which is not in the model.
+	// V falls short of its 105 minimum, and the only way to fund it is to zero K and its
+	// 400 minimum, which frees far more than V needs. W can neither be zeroed nor give up
+	// slack, so the excess can only go back to V and W, up to their giveback ceilings.
+	v := testhelpers.NewModelItemBuilder("V").WithWeight("0.1").
+		WithMinInitialInvestment("105", "0").Build()
+	k := testhelpers.NewModelItemBuilder("K").WithWeight("0.4").
+		WithMinInitialInvestment("400", "0").Build()
+	w := testhelpers.NewModelItemBuilder("W").WithWeight("0.5").
+		WithMinInitialInvestment("500", "0").ProtectFromZeroOut().Build()
+	// With 100 outside the model every product gets 1000/1100 of its ideal, so V's ceiling
+	// is its cap of 110 and W's 550: of the 395 freed beyond V's bump, they take 5 and 50,
+	// and the remaining 340 is left unfulfilled rather than pushing them past their caps.
+	outside100 := testhelpers.NewGoalBuilder("g1").
+		WithModelItem(v).WithModelItem(k).WithModelItem(w).
+		WithHolding("Z", "100", "1").
+		Build()
+	capped := map[string]string{"V": "110.00", "K": "0.00", "W": "550.00"}
+	tests := []struct {
+		name        string
+		goal        models.Goal
+		policy      string
+		want        map[string]string
+		unfulfilled string
+	}{
+		{"stops at ceiling/" + ResidualLargestRemainder, outside100, ResidualLargestRemainder, capped, "340.00"},
+		{"stops at ceiling/" + ResidualRoundRobin, outside100, ResidualRoundRobin, capped, "340.00"},
+		{"stops at ceiling/" + ResidualHighestWeight, outside100, ResidualHighestWeight, capped, "340.00"},
+		// With 1000 outside the model every product gets half its ideal, so W has room for
+		// all of the excess V cannot take and the whole order is still placed.
+		{
+			name: "within ceiling",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(v).WithModelItem(k).WithModelItem(w).
+				WithHolding("Z", "1000", "1").
+				Build(),
+			want: map[string]string{"V": "200.00", "K": "0.00", "W": "800.00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{ResidualPolicy: tt.policy}
+			result := ProcessInvestment(tt.goal, 2, 4, opts)
+			got := byTicker(result)
+			assertValues(t, got, tt.want)
+			if d := got["V"]; d.Error != nil {
+				t.Errorf("V flagged %s, want it fixed", errorCode(d))
+			}
+			if result.UnfulfilledAmount != tt.unfulfilled {
+				t.Errorf("unfulfilledAmount = %q, want %q", result.UnfulfilledAmount, tt.unfulfilled)
+			}
+			unfulfilled, _ := decimal.NewFromString(result.UnfulfilledAmount)
+			if total := sumValues(result).Add(unfulfilled); !total.Equal(decimal.NewFromInt(1000)) {
+				t.Errorf("Σ gross + unfulfilled = %s, want 1000", total)
+			}
+			if check := VerifyResult(tt.goal, result, 2, 4, opts); !check.Passed {
+				t.Errorf("self-check failed: %+v", check.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package splitter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Asset classes that a glide path reweights, matched case-insensitively on
+// ModelItem.AssetClass.
+const (
+	AssetClassEquity = "equity"
+	AssetClassBond   = "bond"
+)
+
+// daysPerYear converts the days to a goal's target date into years.
+var daysPerYear = decimal.RequireFromString("365.25")
+
+// ApplyGlidePath returns goal with the weights of its equity and bond model items set from
+// its glide path, for the years from today (UTC) to its TargetDate. The equity and bond
+// weights are interpolated linearly between the two glide path points either side of that
+// many years, and held at the first or last point beyond them. Each class's weight is then
+// shared among its items in proportion to their weights as sent, or equally when those are
+// all zero. Other items keep their weights. A goal without a TargetDate, or with a date or
+// glide path that does not parse, is returned unchanged.
+func ApplyGlidePath(goal models.Goal, today time.Time) models.Goal {
+	if strings.TrimSpace(goal.TargetDate) == "" || len(goal.GlidePath) == 0 {
+		return goal
+	}
+	target, err := time.Parse("2006-01-02", strings.TrimSpace(goal.TargetDate))
+	if err != nil {
+		return goal
+	}
+	today = today.UTC().Truncate(24 * time.Hour)
+	days := int64(target.Sub(today) / (24 * time.Hour))
+	years := div(decimal.NewFromInt(days), daysPerYear)
+	equity, bond, ok := glideWeights(goal.GlidePath, years)
+	if !ok {
+		return goal
+	}
+
+	items := make([]models.ModelItem, len(goal.ModelPortfolioDetails))
+	copy(items, goal.ModelPortfolioDetails)
+	reweightClass(items, AssetClassEquity, equity)
+	reweightClass(items, AssetClassBond, bond)
+	goal.ModelPortfolioDetails = items
+	return goal
+}
+
+// glideWeights interpolates the equity and bond weights of path at years to target.
+func glideWeights(path []models.GlidePathPoint, years decimal.Decimal) (equity, bond decimal.Decimal, ok bool) {
+	type point struct{ years, equity, bond decimal.Decimal }
+	points := make([]point, len(path))
+	for i, p := range path {
+		y, err := strconv.Atoi(strings.TrimSpace(p.YearsToTarget))
+		e, errE := decimal.NewFromString(strings.TrimSpace(p.EquityWeight))
+		b, errB := decimal.NewFromString(strings.TrimSpace(p.BondWeight))
+		if err != nil || errE != nil || errB != nil {
+			return decimal.Zero, decimal.Zero, false
+		}
+		points[i] = point{decimal.NewFromInt(int64(y)), e, b}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].years.LessThan(points[j].years) })
+
+	if !years.GreaterThan(points[0].years) {
+		return points[0].equity, points[0].bond, true
+	}
+	for i := 1; i < len(points); i++ {
+		lo, hi := points[i-1], points[i]
+		if years.GreaterThan(hi.years) {
+			continue
+		}
+		t := div(years.Sub(lo.years), hi.years.Sub(lo.years))
+		return lo.equity.Add(hi.equity.Sub(lo.equity).Mul(t)), lo.bond.Add(hi.bond.Sub(lo.bond).Mul(t)), true
+	}
+	last := points[len(points)-1]
+	return last.equity, last.bond, true
+}
+
+// reweightClass shares weight among the items of class in proportion to their weights, or
+// equally when those sum to zero.
+func reweightClass(items []models.ModelItem, class string, weight decimal.Decimal) {
+	var idx []int
+	total := decimal.Zero
+	for i, mp := range items {
+		if strings.EqualFold(strings.TrimSpace(mp.AssetClass), class) {
+			idx = append(idx, i)
+			w, _ := decimal.NewFromString(mp.Weight)
+			total = total.Add(w)
+		}
+	}
+	for _, i := range idx {
+		share := div(decimal.NewFromInt(1), decimal.NewFromInt(int64(len(idx))))
+		if total.IsPositive() {
+			w, _ := decimal.NewFromString(items[i].Weight)
+			share = div(w, total)
+		}
+		items[i].Weight = weight.Mul(share).Round(int32(DivisionPrecision)).String()
+	}
+}
@@ -0,0 +1,54 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// byTicker indexes a result's transaction details by ticker.
+func byTicker(result models.GoalResult) map[string]models.TransactionDetail {
+	out := make(map[string]models.TransactionDetail, len(result.TransactionDetails))
+	for _, d := range result.TransactionDetails {
+		out[d.Ticker] = d
+	}
+	return out
+}
+
+// errorCode returns the code of d's first error, or "" when it has none.
+func errorCode(d models.TransactionDetail) models.Code {
+	if d.Error == nil {
+		return ""
+	}
+	return d.Error.Code
+}
+
+// errorCodes returns the code of every error on d, in order.
+func errorCodes(d models.TransactionDetail) []models.Code {
+	var codes []models.Code
+	for _, e := range d.Errors {
+		codes = append(codes, e.Code)
+	}
+	return codes
+}
+
+// sumValues returns Σ value over result's transaction details.
+func sumValues(result models.GoalResult) decimal.Decimal {
+	total := decimal.Zero
+	for _, d := range result.TransactionDetails {
+		v, _ := decimal.NewFromString(d.Value)
+		total = total.Add(v)
+	}
+	return total
+}
+
+// assertValues checks the value of each ticker in want against got.
+func assertValues(t *testing.T, got map[string]models.TransactionDetail, want map[string]string) {
+	t.Helper()
+	for ticker, value := range want {
+		if got[ticker].Value != value {
+			t.Errorf("%s = %s, want %s", ticker, got[ticker].Value, value)
+		}
+	}
+}
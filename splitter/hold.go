@@ -0,0 +1,74 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// DirHold is the direction MarkHolds gives a product the split considered but did not trade.
+const DirHold = "HOLD"
+
+// Reasons for a HOLD without a trade error to give one.
+const (
+	// HoldAtWeight: the product was already at or past its model weight in the direction
+	// of the order, so it needed no trade.
+	HoldAtWeight = "AT_WEIGHT"
+	// HoldZeroed: the product needed a trade, but the repair step or rounding took it to
+	// zero.
+	HoldZeroed = "ZEROED"
+)
+
+// MarkHolds returns result with every BUY or SELL of zero value and no units reported as a
+// HOLD, with HoldReason saying why it was not traded: the code of its trade error when it
+// has one (such as BELOW_MIN_TRADE or BELOW_DEVIATION_TRIGGER), and otherwise HoldAtWeight
+// or HoldZeroed. A product is at weight when its value once pending orders settle is at or
+// above its model weight of the goal after an investment, or at or below it after a
+// redemption, cash included.
+func MarkHolds(goal models.Goal, result models.GoalResult, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	model := make(map[string]decimal.Decimal)
+	for i, w := range modelWeights(goal, opts) {
+		model[goal.ModelPortfolioDetails[i].Ticker] = w
+	}
+	current := make(map[string]decimal.Decimal)
+	total := decimal.Zero
+	for _, p := range projectTrades(goal, models.GoalResult{}, amountPrec, unitPrec) {
+		if !p.cash {
+			current[p.ticker] = p.preValue
+		}
+		total = total.Add(p.preValue)
+	}
+	orderAmount, _ := decimal.NewFromString(strings.TrimSpace(goal.OrderAmount))
+
+	details := make([]models.TransactionDetail, len(result.TransactionDetails))
+	for i, d := range result.TransactionDetails {
+		details[i] = d
+		if (d.Direction != "BUY" && d.Direction != "SELL") || !isZeroTrade(d) {
+			continue
+		}
+		reason := HoldZeroed
+		switch {
+		case d.Error != nil:
+			reason = string(d.Error.Code)
+		case d.Direction == "BUY" && current[d.Ticker].GreaterThanOrEqual(model[d.Ticker].Mul(total.Add(orderAmount))):
+			reason = HoldAtWeight
+		case d.Direction == "SELL" && current[d.Ticker].LessThanOrEqual(model[d.Ticker].Mul(decimal.Max(total.Sub(orderAmount), decimal.Zero))):
+			reason = HoldAtWeight
+		}
+		details[i].Direction = DirHold
+		details[i].HoldReason = reason
+	}
+	result.TransactionDetails = details
+	return result
+}
+
+// isZeroTrade reports whether d has a zero value and its units are zero or not given.
+func isZeroTrade(d models.TransactionDetail) bool {
+	value, err := decimal.NewFromString(d.Value)
+	if err != nil || !value.IsZero() {
+		return false
+	}
+	units, err := decimal.NewFromString(d.Units)
+	return err != nil || units.IsZero()
+}
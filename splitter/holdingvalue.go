@@ -0,0 +1,52 @@
+package splitter
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// holdingValueTolerance is how far, as a share of the value sent, a recomputed holding value
+// may move before RecomputeHoldingValues warns about it: 1 basis point.
+var holdingValueTolerance = decimal.New(1, -4)
+
+// RecomputeHoldingValues returns goal with the Value of every holding replaced by
+// units × marketPrice, truncated to amountPrec, so that the goal value agrees with the
+// prices it is split on. The price is the one a sell of the holding would be converted at
+// (see unitPrice), or the holding's own when the model item has none. A holding without
+// units or a positive price keeps its value. Each value that moves by more than
+// holdingValueTolerance of the value sent is reported as a CodeHoldingValueRecomputed
+// warning.
+func RecomputeHoldingValues(goal models.Goal, amountPrec int) (models.Goal, []models.Warning) {
+	model := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		model[mp.Ticker] = mp
+	}
+	var warnings []models.Warning
+	holdings := make([]models.Holding, len(goal.GoalDetails))
+	for i, h := range goal.GoalDetails {
+		holdings[i] = h
+		mp, inModel := model[h.Ticker]
+		price, err := decimal.NewFromString(unitPrice("SELL", mp, inModel, h))
+		if err != nil || !price.IsPositive() {
+			price, err = decimal.NewFromString(h.MarketPrice)
+		}
+		units, unitsErr := decimal.NewFromString(h.Units)
+		if err != nil || !price.IsPositive() || unitsErr != nil {
+			continue
+		}
+		value := units.Mul(price).Truncate(int32(amountPrec))
+		sent, _ := decimal.NewFromString(h.Value)
+		if value.Sub(sent).Abs().GreaterThan(sent.Abs().Mul(holdingValueTolerance)) {
+			warnings = append(warnings, models.Warning{
+				Code:    models.CodeHoldingValueRecomputed,
+				Message: fmt.Sprintf("holding %s value %s recomputed as %s from units × marketPrice", h.Ticker, h.Value, value.StringFixed(int32(amountPrec))),
+				Ticker:  h.Ticker,
+			})
+		}
+		holdings[i].Value = value.StringFixed(int32(amountPrec))
+	}
+	goal.GoalDetails = holdings
+	return goal, warnings
+}
@@ -2,394 +2,643 @@ package splitter
 
 import (
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/shopspring/decimal"
 	"github.com/valentinpj/smart-splitter/models"
 )
 
 type productAlloc struct {
-	mp      models.ModelItem
-	current decimal.Decimal
-	ideal   decimal.Decimal
+	mp           models.ModelItem
+	weight       decimal.Decimal
+	current      decimal.Decimal
+	currentUnits decimal.Decimal
+	newPosition  bool // forceInitialMinimums: checked as a first purchase whatever is held
+	ideal        decimal.Decimal
+	target       decimal.Decimal // post-trade value at model weight: weight × postTotal
+	priority     int             // higher is preferred for discretionary choices in the repair step
 }
 
 // ProcessInvestment splits an investment order across model portfolio products,
-// prioritising products that are furthest below their model weight (shortfall-based allocation).
+// prioritising products that are furthest below their model weight (shortfall-based allocation)
+// unless another allocation strategy is selected.
 // The output preserves the order of modelPortfolioDetails from the input.
-func ProcessInvestment(goal models.Goal, amountPrec, unitPrec int) models.GoalResult {
+func ProcessInvestment(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
 	orderAmount, _ := decimal.NewFromString(goal.OrderAmount)
 
-	// Build current-holdings map: ticker -> current value in portfolio
+	// Build current-holdings map: ticker -> current value in portfolio, including
+	// unsettled orders so that a deposit still settling is not bought twice. With
+	// IgnoreCurrentHoldings it stays empty and every product starts from zero.
 	holdingsMap := make(map[string]decimal.Decimal)
+	unitsMap := make(map[string]decimal.Decimal)
+	newPositions := make(map[string]bool)
 	vTotal := decimal.Zero
-	for _, h := range goal.GoalDetails {
-		val, _ := decimal.NewFromString(h.Value)
-		holdingsMap[h.Ticker] = val
-		vTotal = vTotal.Add(val)
+	if !opts.IgnoreCurrentHoldings {
+		for _, h := range goal.GoalDetails {
+			val := projectedValue(h)
+			holdingsMap[h.Ticker] = val
+			unitsMap[h.Ticker] = projectedUnits(h)
+			newPositions[h.Ticker] = newPositions[h.Ticker] || h.ForceInitialMinimums
+			vTotal = vTotal.Add(val)
+		}
 	}
 
 	postTotal := vTotal.Add(orderAmount)
 
-	// Compute ideal (shortfall-based) allocation for each model product with weight > 0.
-	// ideal_i = max(0, w_i * postTotal - currentValue_i)
+	// Collect model products with weight > 0 and compute their ideal (net) allocation
+	// with the goal's allocation strategy (shortfall-based by default).
 	var allocs []productAlloc
-	totalIdeal := decimal.Zero
-	totalWeight := decimal.Zero
-
-	for _, mp := range goal.ModelPortfolioDetails {
-		weight, _ := decimal.NewFromString(mp.Weight)
+	weights := modelWeights(goal, opts)
+	for j, mp := range goal.ModelPortfolioDetails {
+		weight := weights[j]
 		if weight.IsZero() {
 			continue
 		}
-		totalWeight = totalWeight.Add(weight)
-		currentVal := holdingsMap[mp.Ticker]
-		ideal := weight.Mul(postTotal).Sub(currentVal)
-		if ideal.LessThan(decimal.Zero) {
-			ideal = decimal.Zero
+		allocs = append(allocs, productAlloc{
+			mp:           mp,
+			weight:       weight,
+			current:      holdingsMap[mp.Ticker],
+			currentUnits: unitsMap[mp.Ticker],
+			newPosition:  newPositions[mp.Ticker],
+			target:       weight.Mul(postTotal),
+			priority:     parseOptionalInt(mp.Priority),
+		})
+	}
+	// Nothing to allocate to (e.g. a placeholder model with only zero weights): report it on
+	// the goal rather than producing an empty split.
+	if len(allocs) == 0 {
+		return models.GoalResult{
+			GoalID:             goal.GoalID,
+			TransactionType:    goal.OrderType,
+			TransactionDetails: []models.TransactionDetail{},
+			Error: &models.TradeError{
+				Message: "No product in modelPortfolioDetails has a weight greater than 0",
+				Code:    models.CodeNothingToAllocate,
+			},
 		}
-		allocs = append(allocs, productAlloc{mp: mp, current: currentVal, ideal: ideal})
+	}
+
+	// Products dropped from the allocation for a structural reason, keyed by alloc index.
+	dropped := make(map[int][]models.TradeError)
+
+	totalIdeal := decimal.Zero
+	for i, ideal := range strategyFor(goal, opts).ideals(allocs, orderAmount, postTotal) {
+		allocs[i].ideal = ideal
 		totalIdeal = totalIdeal.Add(ideal)
 	}
 
+	// Holdings lock: split the order over the products already held, in proportion to
+	// their value, so no held position's share of the goal falls.
+	locked := opts.LockCurrentHoldings && lockHoldings(allocs, dropped, orderAmount)
+	if locked {
+		totalIdeal = orderAmount
+	}
+
+	// No-buy list: blocked products are never bought and their share goes to the others.
+	// One that would not have been bought anyway is dropped without an error. When no other
+	// product has a shortfall, the fallback below spreads the order over them by weight.
+	if blocked := blockedFromBuy(allocs); len(blocked) > 0 {
+		for _, i := range blocked {
+			dropped[i] = []models.TradeError{}
+			floor, _ := decimal.NewFromString(strings.TrimSpace(allocs[i].mp.AllocationFloor))
+			if allocs[i].ideal.IsPositive() || !totalIdeal.IsPositive() || floor.IsPositive() {
+				dropped[i] = []models.TradeError{{
+					Message: "Not bought because the product is blocked from new investment",
+					Code:    models.CodeBuyBlocked,
+				}}
+			}
+		}
+		if !dropAndRescale(allocs, blocked) {
+			for _, i := range blocked {
+				allocs[i].ideal = decimal.Zero
+			}
+			totalIdeal = decimal.Zero
+		}
+	}
+
+	// Trade-count cap: keep only the top maxTrades products and hand the shortfall of the
+	// consolidated products to the survivors, so the whole order is placed in fewer trades.
+	if maxTrades := parseOptionalInt(goal.MaxTrades); maxTrades > 0 && totalIdeal.IsPositive() {
+		consolidated := consolidate(allocs, maxTrades, orderAmount, amountPrec)
+		for _, i := range consolidated {
+			dropped[i] = []models.TradeError{{
+				Message: "Not traded because the order was consolidated into fewer products (maxTrades)",
+				Code:    models.CodeConsolidated,
+			}}
+		}
+		// The kept products must be tradeable within their minimums, or the consolidated
+		// split would only trade some of them; fail the goal rather than place part of it.
+		if len(consolidated) > 0 && !minimumsFit(allocs, orderAmount, amountPrec) {
+			return models.GoalResult{
+				GoalID:             goal.GoalID,
+				TransactionType:    goal.OrderType,
+				TransactionDetails: []models.TransactionDetail{},
+				Error: &models.TradeError{
+					Message: "The order cannot meet the minimums of the products kept by maxTrades",
+					Code:    models.CodeInsufficientMaxTrades,
+				},
+			}
+		}
+	}
+
 	// Fallback: if every product is already at or above its model weight (totalIdeal == 0),
-	// distribute pro-rata by model weight.
+	// distribute pro-rata by model weight over the products not blocked from buying.
 	if totalIdeal.IsZero() {
+		keptWeight := decimal.Zero
 		for i, a := range allocs {
-			w, _ := decimal.NewFromString(a.mp.Weight)
-			allocs[i].ideal = w.Div(totalWeight).Mul(orderAmount)
+			if dropped[i] == nil {
+				keptWeight = keptWeight.Add(a.weight)
+			}
+		}
+		for i, a := range allocs {
+			if dropped[i] == nil && keptWeight.IsPositive() {
+				allocs[i].ideal = div(a.weight, keptWeight).Mul(orderAmount)
+			}
 		}
 		totalIdeal = orderAmount
 	}
 
-	// Apply transaction fee adjustment: to achieve a net investment equal to ideal_i,
-	// the gross amount must be ideal_i / (1 - fee_i).
-	// We then scale so that all gross amounts sum to orderAmount.
-	one := decimal.NewFromInt(1)
-	feeAdjusted := make([]decimal.Decimal, len(allocs))
-	totalFeeAdjusted := decimal.Zero
-	for i, a := range allocs {
-		fee, _ := decimal.NewFromString(a.mp.TransactionFee)
-		divisor := one.Sub(fee) // 1 - fee; fee is validated < 1, so divisor > 0
-		feeAdjusted[i] = a.ideal.Div(divisor)
-		totalFeeAdjusted = totalFeeAdjusted.Add(feeAdjusted[i])
+	// Drift trigger: products already close enough to their model weight are not traded,
+	// and their share goes to the products that drifted further.
+	if trigger, _ := decimal.NewFromString(strings.TrimSpace(opts.MinWeightDeviationTrigger)); trigger.IsPositive() {
+		if within := withinDeviation(allocs, dropped, vTotal, trigger); dropAndRescale(allocs, within) {
+			for _, i := range within {
+				dropped[i] = []models.TradeError{{
+					Message: "Not traded because the current weight is within minWeightDeviationTrigger of the model weight",
+					Code:    models.CodeBelowDeviationTrigger,
+				}}
+			}
+		}
 	}
 
-	// Gross cap per product: the maximum gross that keeps the post-investment value at or
-	// below the model weight target.  cap_i = floor(ideal_i / (1 − fee_i), amountPrec).
-	grossCaps := make([]decimal.Decimal, len(allocs))
-	for i := range allocs {
-		grossCaps[i] = feeAdjusted[i].Truncate(int32(amountPrec))
-	}
+	// Allocation floors: reserve a share of the order for products regardless of shortfall.
+	applyAllocationFloors(allocs, dropped, orderAmount, totalIdeal)
 
-	// Pass 1: compute initial gross amounts (truncated down to amountDecimalPrecision),
-	// capped so no product overshoots its model weight target.
-	grossAmounts := make([]decimal.Decimal, len(allocs))
-	for i := range allocs {
-		g := feeAdjusted[i].Div(totalFeeAdjusted).Mul(orderAmount).Truncate(int32(amountPrec))
-		if g.GreaterThan(grossCaps[i]) {
-			g = grossCaps[i]
+	// Pass 1: fee-adjusted, capped gross amounts.
+	grossAmounts, grossCaps := initialGross(allocs, orderAmount, amountPrec)
+
+	// Global minimum trade size: drop trades below minTradeAmt and hand their share to the
+	// survivors, repeating until every remaining trade clears the threshold or only one is left.
+	if minTrade := minTradeAmt(goal, opts); minTrade.IsPositive() {
+		for {
+			below := belowMinTrade(grossAmounts, minTrade)
+			if len(below) == 0 {
+				break
+			}
+			for _, i := range below {
+				dropped[i] = []models.TradeError{{
+					Message: "Not traded because the allocation is below the minimum trade amount",
+					Code:    models.CodeBelowMinTrade,
+				}}
+			}
+			dropAndRescale(allocs, below)
+			grossAmounts, grossCaps = initialGross(allocs, orderAmount, amountPrec)
 		}
-		grossAmounts[i] = g
 	}
 
 	// Repair step: bump violating products up to their minimum requirement,
-	// funded by proportionally reducing non-violating products.
-	grossAmounts = repairViolations(allocs, grossAmounts, grossCaps, amountPrec, unitPrec)
+	// funded by reducing non-violating products as the repair strategy directs.
+	// The repair only moves money between products, except for over-zeroed excess it has no
+	// room to hand back; that is reported as unfulfilled rather than over-funding anyone.
+	// Under the holdings lock there is no repair: a bump would be funded by cutting another
+	// held product below its current share, so violations are flagged instead.
+	repair := newRepairer(allocs, amountPrec, opts)
+	preRepair := explainSnapshot(grossAmounts, opts)
+	placed := sumDecimals(grossAmounts)
+	repairObjective := ""
+	if !locked {
+		grossAmounts, repairObjective = repair.repairViolations(grossAmounts, grossCaps)
+	}
+	unplaced := placed.Sub(sumDecimals(grossAmounts))
+
+	// Zero-on-violation mode: products the repair step could not fix are zeroed instead of
+	// kept, and their share is redistributed to the remaining products before repairing again.
+	// The violation is still reported on the zeroed product.
+	if opts.ZeroOnMinViolation {
+		for {
+			var violating []int
+			for i, a := range allocs {
+				if dropped[i] != nil {
+					continue
+				}
+				if tradeErrs := checkInvestmentMinimums(a, grossAmounts[i], amountPrec, unitPrec); tradeErrs != nil {
+					violating = append(violating, i)
+					dropped[i] = tradeErrs
+				}
+			}
+			if len(violating) == 0 {
+				break
+			}
+			if !dropAndRescale(allocs, violating) {
+				// Nothing left to redistribute to: every remaining trade violates.
+				for _, i := range violating {
+					grossAmounts[i] = decimal.Zero
+				}
+				break
+			}
+			grossAmounts, grossCaps = initialGross(allocs, orderAmount, amountPrec)
+			preRepair = explainSnapshot(grossAmounts, opts)
+			placed = sumDecimals(grossAmounts)
+			if !locked {
+				grossAmounts, repairObjective = repair.repairViolations(grossAmounts, grossCaps)
+			}
+			unplaced = placed.Sub(sumDecimals(grossAmounts))
+		}
+	}
 
 	// Pass 2: build transaction details with updated gross amounts.
 	var details []models.TransactionDetail
 	for i, a := range allocs {
 		gross := grossAmounts[i]
 
+		// marketPrice is quoted in the product's local currency; convert the base-currency
+		// gross into local currency before deriving units.
 		price, _ := decimal.NewFromString(a.mp.MarketPrice)
+		fx := exchangeRate(a.mp)
 		var units decimal.Decimal
 		if price.IsPositive() {
-			units = gross.Div(price).Truncate(int32(unitPrec))
-		}
-
-		// Compute net amount (after fee) for minimum requirement checks.
-		// Minimums are expressed in terms of what actually enters the portfolio.
-		fee, _ := decimal.NewFromString(a.mp.TransactionFee)
-		net := gross.Mul(one.Sub(fee))
-		var netUnits decimal.Decimal
-		if price.IsPositive() {
-			netUnits = net.Div(price).Truncate(int32(unitPrec))
+			units = div(gross.Mul(fx), price).Truncate(int32(unitPrec))
 		}
 
 		// Check minimum requirements (flag-and-keep: violations are reported but allocation is preserved).
-		var tradeErr *models.TradeError
-		if gross.IsPositive() {
-			if a.current.IsZero() {
-				// First-time purchase: apply initial investment minimums against net amount.
-				minAmt, _ := decimal.NewFromString(a.mp.MinInitialInvestmentAmt)
-				minUnits, _ := decimal.NewFromString(a.mp.MinInitialInvestmentUnits)
-				if net.LessThan(minAmt) || netUnits.LessThan(minUnits) {
-					tradeErr = &models.TradeError{
-						Message: "Cannot trade this ticker because it breaches the minimum initial investment amount",
-						Code:    "MIN_INVESTMENT_VIOLATION",
-					}
-				}
-			} else {
-				// Subsequent purchase: apply top-up minimums against net amount.
-				minAmt, _ := decimal.NewFromString(a.mp.MinTopupAmt)
-				minUnits, _ := decimal.NewFromString(a.mp.MinTopupUnits)
-				if net.LessThan(minAmt) || netUnits.LessThan(minUnits) {
-					tradeErr = &models.TradeError{
-						Message: "Cannot trade this ticker because it breaches the minimum topup amount",
-						Code:    "MIN_TOPUP_VIOLATION",
-					}
-				}
-			}
+		tradeErrs := dropped[i]
+		if tradeErrs == nil {
+			tradeErrs = checkInvestmentMinimums(a, gross, amountPrec, unitPrec)
 		}
 
-		details = append(details, models.TransactionDetail{
+		detail := withErrors(models.TransactionDetail{
 			Ticker:    a.mp.Ticker,
 			Direction: "BUY",
 			Value:     gross.StringFixed(int32(amountPrec)),
 			Units:     units.StringFixed(int32(unitPrec)),
-			Error:     tradeErr,
-		})
+		}, tradeErrs...)
+		if opts.Explain {
+			detail.Trace = investmentTrace(a, preRepair[i], gross, amountPrec)
+		}
+		details = append(details, detail)
 	}
 
-	return models.GoalResult{
+	result := models.GoalResult{
 		GoalID:             goal.GoalID,
 		TransactionType:    goal.OrderType,
 		TransactionDetails: details,
 	}
+	if unplaced.IsPositive() {
+		result.UnfulfilledAmount = unplaced.StringFixed(int32(amountPrec))
+	}
+	if opts.IncludeEffectiveWeights {
+		result.EffectiveWeights = effectiveWeights(allocs, grossAmounts, orderAmount, weightsTransformed(opts))
+	}
+	if opts.RepairObjective != "" {
+		result.Debug = &models.GoalDebug{RepairObjective: repairObjective}
+	}
+	if len(opts.WhatIfAmounts) > 0 {
+		result.WhatIfResults = whatIf(goal, opts.WhatIfAmounts, amountPrec, unitPrec, opts)
+	}
+	return result
 }
 
-// repairViolations attempts to clear minimum-requirement violations by bumping each
-// violating product's gross allocation up to its required minimum.
-//
-// Two funding tiers, applied in order for each violation (cheapest bump first):
-//
-//  1. Safe slack: reduce non-violating products from their current gross down to their
-//     own minimum floor (gross_j − reqGross_j). Never creates a new violation.
-//
-//  2. Zero-out: if safe slack alone is insufficient, additionally zero out non-violating
-//     products entirely (smallest reqGross first), gaining their reqGross as extra slack.
-//     A gross of 0 is always valid — it simply means no trade for that product.
-//
-// After deciding which violations to fix, non-zeroed products are reduced pro-rata by
-// their safe slack to fund the bumps, keeping Σ gross == orderAmount exactly.
-func repairViolations(allocs []productAlloc, grossAmounts []decimal.Decimal, grossCaps []decimal.Decimal, amountPrec, unitPrec int) []decimal.Decimal {
-	one := decimal.NewFromInt(1)
+// weightDisplayPrec is the number of decimal places used for weights in the response.
+const weightDisplayPrec = 6
 
-	type itemInfo struct {
-		gross    decimal.Decimal
-		reqGross decimal.Decimal // minimum gross to pass all checks; 0 if no minimum applies
-	}
-
-	items := make([]itemInfo, len(allocs))
+// effectiveWeights reports, per product, the share of orderAmount it received
+// (achievedWeight_i = gross_i / orderAmount) against its model weight. The model weight is
+// reported as sent unless the weights were normalised or shrunk.
+func effectiveWeights(allocs []productAlloc, grossAmounts []decimal.Decimal, orderAmount decimal.Decimal, transformed bool) []models.EffectiveWeight {
+	out := make([]models.EffectiveWeight, len(allocs))
 	for i, a := range allocs {
-		fee, _ := decimal.NewFromString(a.mp.TransactionFee)
-		price, _ := decimal.NewFromString(a.mp.MarketPrice)
-
-		var minAmt, minUnits decimal.Decimal
-		if a.current.IsZero() {
-			minAmt, _ = decimal.NewFromString(a.mp.MinInitialInvestmentAmt)
-			minUnits, _ = decimal.NewFromString(a.mp.MinInitialInvestmentUnits)
-		} else {
-			minAmt, _ = decimal.NewFromString(a.mp.MinTopupAmt)
-			minUnits, _ = decimal.NewFromString(a.mp.MinTopupUnits)
+		achieved := decimal.Zero
+		if orderAmount.IsPositive() {
+			achieved = div(grossAmounts[i], orderAmount)
 		}
-
-		// requiredNet = max(minAmt, minUnits × price)
-		requiredNet := minAmt
-		if minUnitsCost := minUnits.Mul(price); minUnitsCost.GreaterThan(requiredNet) {
-			requiredNet = minUnitsCost
+		modelWeight := a.mp.Weight
+		if transformed {
+			modelWeight = a.weight.StringFixed(weightDisplayPrec)
 		}
-
-		// requiredGross = ⌈requiredNet / (1 − fee)⌉ at amountPrec decimal places.
-		var reqGross decimal.Decimal
-		if requiredNet.IsPositive() {
-			if divisor := one.Sub(fee); divisor.IsPositive() {
-				reqGross = ceilToPrec(requiredNet.Div(divisor), int32(amountPrec))
-			}
+		out[i] = models.EffectiveWeight{
+			Ticker:         a.mp.Ticker,
+			ModelWeight:    modelWeight,
+			AchievedWeight: achieved.StringFixed(weightDisplayPrec),
+			AbsDeviation:   achieved.Sub(a.weight).Abs().StringFixed(weightDisplayPrec),
 		}
-
-		items[i] = itemInfo{gross: grossAmounts[i], reqGross: reqGross}
 	}
+	return out
+}
+
+// firstPurchase reports whether a buy of a is checked against the product's initial-investment
+// minimums: when nothing is held, or the holding sets forceInitialMinimums.
+func (a productAlloc) firstPurchase() bool {
+	return a.current.IsZero() || a.newPosition
+}
 
-	// Identify violations: positive gross allocation that falls below reqGross.
-	// Skip violations where reqGross exceeds the model-weight cap — bumping to the
-	// minimum would overshoot the target weight, so the violation is left unfixed.
-	type violation struct {
-		idx  int
-		bump decimal.Decimal
+// checkInvestmentMinimums checks a positive gross allocation against the product's
+// initial-investment (first purchase) or top-up (subsequent purchase) minimums, and
+// the resulting position against its holding minimums. It returns every minimum the
+// allocation breaches, amounts before units, or nil when it meets them all.
+// Minimums are expressed in terms of what actually enters the portfolio, so they are
+// checked against the net amount after the transaction fee.
+func checkInvestmentMinimums(a productAlloc, gross decimal.Decimal, amountPrec, unitPrec int) []models.TradeError {
+	if !gross.IsPositive() {
+		return nil
 	}
-	var violations []violation
-	for i, it := range items {
-		if it.gross.IsZero() || it.reqGross.IsZero() {
-			continue
-		}
-		if it.gross.LessThan(it.reqGross) {
-			if it.reqGross.GreaterThan(grossCaps[i]) {
-				continue // cannot fix without overshooting model weight
-			}
-			violations = append(violations, violation{idx: i, bump: it.reqGross.Sub(it.gross)})
+	fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+	net := gross.Mul(decimal.NewFromInt(1).Sub(fee))
+	netUnits := GetExpectedUnits(a.mp.Ticker, gross, a.mp, unitPrec)
+
+	check := minimumCheck{amountPrec: amountPrec, unitPrec: unitPrec}
+	if a.firstPurchase() {
+		// First-time purchase: apply initial investment minimums against net amount.
+		minAmt, _ := decimal.NewFromString(a.mp.MinInitialInvestmentAmt)
+		minUnits, _ := decimal.NewFromString(a.mp.MinInitialInvestmentUnits)
+		check.amount(net, minAmt, models.CodeMinInvestmentViolation,
+			"Cannot trade this ticker because it breaches the minimum initial investment amount")
+		check.units(netUnits, minUnits, models.CodeMinInvestmentViolation,
+			"Cannot trade this ticker because it breaches the minimum initial investment units")
+	} else {
+		// Subsequent purchase: apply top-up minimums against net amount.
+		minAmt, _ := decimal.NewFromString(a.mp.MinTopupAmt)
+		minUnits, _ := decimal.NewFromString(a.mp.MinTopupUnits)
+		check.amount(net, minAmt, models.CodeMinTopupViolation,
+			"Cannot trade this ticker because it breaches the minimum topup amount")
+		check.units(netUnits, minUnits, models.CodeMinTopupViolation,
+			"Cannot trade this ticker because it breaches the minimum topup units")
+	}
+
+	// The resulting position must meet the holding minimum once the fee is netted.
+	minHoldAmt, _ := decimal.NewFromString(a.mp.MinHoldingAmt)
+	minHoldUnits, _ := decimal.NewFromString(a.mp.MinHoldingUnits)
+	check.amount(a.current.Add(net), minHoldAmt, models.CodeMinHoldingViolation,
+		"Cannot trade this ticker because the resulting holding would breach the minimum holding amount")
+	check.units(a.currentUnits.Add(netUnits), minHoldUnits, models.CodeMinHoldingViolation,
+		"Cannot trade this ticker because the resulting holding would breach the minimum holding units")
+	return check.errs
+}
+
+// consolidate keeps the maxTrades products with the largest shortfall and zeroes the
+// ideal of every other product. Products whose minimum could be met by the whole order
+// are ranked ahead of those that could not, so the survivors are tradeable where possible.
+// The survivors' ideals are scaled up pro-rata so that Σ ideal is unchanged.
+// It returns the consolidated (dropped) allocation indices.
+func consolidate(allocs []productAlloc, maxTrades int, orderAmount decimal.Decimal, amountPrec int) []int {
+	var candidates []int
+	for i, a := range allocs {
+		if a.ideal.IsPositive() {
+			candidates = append(candidates, i)
 		}
 	}
-	if len(violations) == 0 {
-		return grossAmounts
+	if len(candidates) <= maxTrades {
+		return nil
 	}
 
-	// Sort violations cheapest-first to maximise the number fixed when resources are limited.
-	sort.Slice(violations, func(i, j int) bool {
-		return violations[i].bump.LessThan(violations[j].bump)
+	feasible := func(i int) bool {
+		return !requiredGross(allocs[i], amountPrec).GreaterThan(orderAmount)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		fi, fj := feasible(candidates[i]), feasible(candidates[j])
+		if fi != fj {
+			return fi
+		}
+		return allocs[candidates[i]].ideal.GreaterThan(allocs[candidates[j]].ideal)
 	})
 
-	violatingSet := make(map[int]bool)
-	for _, v := range violations {
-		violatingSet[v.idx] = true
+	drop := candidates[maxTrades:]
+	dropAndRescale(allocs, drop)
+	return drop
+}
+
+// minimumsFit reports whether the order can buy every product with a positive ideal at
+// its required gross: Σ requiredGross_i ≤ orderAmount.
+func minimumsFit(allocs []productAlloc, orderAmount decimal.Decimal, amountPrec int) bool {
+	required := decimal.Zero
+	for _, a := range allocs {
+		if a.ideal.IsPositive() {
+			required = required.Add(requiredGross(a, amountPrec))
+		}
 	}
+	return !required.GreaterThan(orderAmount)
+}
 
-	// Build slack info for non-violating products.
-	type slackItem struct {
-		idx       int
-		safeSlack decimal.Decimal // gross − reqGross; can always be taken without creating a new violation
-		reqGross  decimal.Decimal // additional slack available only if the product is zeroed entirely
+// blockedFromBuy returns the indices of the products on the no-buy list.
+func blockedFromBuy(allocs []productAlloc) []int {
+	var blocked []int
+	for i, a := range allocs {
+		if a.mp.BlockedFromBuy {
+			blocked = append(blocked, i)
+		}
 	}
-	var slackItems []slackItem
-	totalSafeSlack := decimal.Zero
-	for i, it := range items {
-		if violatingSet[i] || it.gross.IsZero() {
-			continue
+	return blocked
+}
+
+// dropAndRescale zeroes the ideal of each product in drop and scales the remaining
+// positive ideals up pro-rata, so Σ ideal is unchanged and the freed share goes to
+// the survivors. If no product survives, the ideals are left untouched and it reports false.
+func dropAndRescale(allocs []productAlloc, drop []int) bool {
+	dropSet := make(map[int]bool, len(drop))
+	for _, i := range drop {
+		dropSet[i] = true
+	}
+	totalIdeal, keptIdeal := decimal.Zero, decimal.Zero
+	for i, a := range allocs {
+		totalIdeal = totalIdeal.Add(a.ideal)
+		if !dropSet[i] {
+			keptIdeal = keptIdeal.Add(a.ideal)
 		}
-		safeSlack := it.gross.Sub(it.reqGross) // >= 0 since non-violating
-		slackItems = append(slackItems, slackItem{idx: i, safeSlack: safeSlack, reqGross: it.reqGross})
-		totalSafeSlack = totalSafeSlack.Add(safeSlack)
 	}
-	if len(slackItems) == 0 {
-		return grossAmounts
+	if !keptIdeal.IsPositive() {
+		return false
 	}
-
-	// Zero-out candidates sorted by reqGross ascending: prefer zeroing products with
-	// the smallest minimum so that we sacrifice as little as possible.
-	zeroableSorted := make([]slackItem, len(slackItems))
-	copy(zeroableSorted, slackItems)
-	sort.Slice(zeroableSorted, func(i, j int) bool {
-		return zeroableSorted[i].reqGross.LessThan(zeroableSorted[j].reqGross)
-	})
-
-	result := make([]decimal.Decimal, len(grossAmounts))
-	copy(result, grossAmounts)
-
-	zeroedSet := make(map[int]bool)
-	remainingSlack := totalSafeSlack // tracks available pool across iterations
-	totalBumpUsed := decimal.Zero
-
-	for _, v := range violations {
-		if v.bump.LessThanOrEqual(remainingSlack) {
-			// Tier 1: safe slack is sufficient.
-			result[v.idx] = items[v.idx].reqGross
-			remainingSlack = remainingSlack.Sub(v.bump)
-			totalBumpUsed = totalBumpUsed.Add(v.bump)
+	for i := range allocs {
+		if dropSet[i] {
+			allocs[i].ideal = decimal.Zero
 		} else {
-			// Tier 2: try to bridge the gap by zeroing non-violating products.
-			extraNeeded := v.bump.Sub(remainingSlack)
-			extraGained := decimal.Zero
-			var toZero []int
-			for _, si := range zeroableSorted {
-				if zeroedSet[si.idx] || si.reqGross.IsZero() {
-					continue
-				}
-				toZero = append(toZero, si.idx)
-				extraGained = extraGained.Add(si.reqGross)
-				if extraGained.GreaterThanOrEqual(extraNeeded) {
-					break
-				}
-			}
-			if extraGained.GreaterThanOrEqual(extraNeeded) {
-				result[v.idx] = items[v.idx].reqGross
-				for _, idx := range toZero {
-					result[idx] = decimal.Zero
-					zeroedSet[idx] = true
-				}
-				// The zeroed products' reqGross values bridge the gap; update the pool.
-				remainingSlack = remainingSlack.Add(extraGained).Sub(v.bump)
-				totalBumpUsed = totalBumpUsed.Add(v.bump)
-			}
-			// else: insufficient resources even with zeroing — leave this violation unfixed.
+			allocs[i].ideal = div(allocs[i].ideal.Mul(totalIdeal), keptIdeal)
 		}
 	}
+	return true
+}
+
+// initialGross converts each product's ideal into a gross allocation: the ideal is
+// grossed up for the transaction fee, scaled so that gross amounts sum to orderAmount,
+// truncated to amountPrec and capped at the model-weight target. It returns the gross
+// amounts together with the per-product caps used by the repair step.
+func initialGross(allocs []productAlloc, orderAmount decimal.Decimal, amountPrec int) (grossAmounts, grossCaps []decimal.Decimal) {
+	// Apply transaction fee adjustment: to achieve a net investment equal to ideal_i,
+	// the gross amount must be ideal_i / (1 - fee_i).
+	// We then scale so that all gross amounts sum to orderAmount.
+	one := decimal.NewFromInt(1)
+	feeAdjusted := make([]decimal.Decimal, len(allocs))
+	totalFeeAdjusted := decimal.Zero
+	for i, a := range allocs {
+		fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+		divisor := one.Sub(fee) // 1 - fee; fee is validated < 1, so divisor > 0
+		feeAdjusted[i] = div(a.ideal, divisor)
+		totalFeeAdjusted = totalFeeAdjusted.Add(feeAdjusted[i])
+	}
 
-	if totalBumpUsed.IsZero() {
-		return grossAmounts
+	// Gross cap per product: the maximum gross that keeps the post-investment value at or
+	// below the model weight target.  cap_i = floor(ideal_i / (1 − fee_i), amountPrec).
+	grossCaps = make([]decimal.Decimal, len(allocs))
+	for i := range allocs {
+		grossCaps[i] = feeAdjusted[i].Truncate(int32(amountPrec))
 	}
 
-	// Compute the net reduction still required from non-zeroed non-violating products.
-	// (Zeroed products already contribute their full gross to balancing the sum.)
-	zeroedContribution := decimal.Zero
-	for idx := range zeroedSet {
-		zeroedContribution = zeroedContribution.Add(items[idx].gross)
+	// Compute initial gross amounts (truncated down to amountDecimalPrecision),
+	// capped so no product overshoots its model weight target. With nothing to scale
+	// (every ideal is zero) every product gets zero rather than dividing by zero.
+	grossAmounts = make([]decimal.Decimal, len(allocs))
+	if !totalFeeAdjusted.IsPositive() {
+		for i := range grossAmounts {
+			grossAmounts[i] = decimal.Zero
+		}
+		return grossAmounts, grossCaps
+	}
+	for i := range allocs {
+		g := div(feeAdjusted[i], totalFeeAdjusted).Mul(orderAmount).Truncate(int32(amountPrec))
+		if g.GreaterThan(grossCaps[i]) {
+			g = grossCaps[i]
+		}
+		grossAmounts[i] = g
 	}
-	stillNeeded := totalBumpUsed.Sub(zeroedContribution)
+	return grossAmounts, grossCaps
+}
 
-	// Collect non-zeroed, non-violating products for pro-rata redistribution.
-	var redistItems []slackItem
-	redistSafeSlack := decimal.Zero
-	for _, si := range slackItems {
-		if zeroedSet[si.idx] {
+// withinDeviation returns the indices of the products, not already dropped, whose current
+// weight V_i / vTotal differs from their model weight by less than trigger. A goal with no
+// holdings has every current weight at 0.
+func withinDeviation(allocs []productAlloc, dropped map[int][]models.TradeError, vTotal, trigger decimal.Decimal) []int {
+	var within []int
+	for i, a := range allocs {
+		if dropped[i] != nil {
 			continue
 		}
-		redistItems = append(redistItems, si)
-		redistSafeSlack = redistSafeSlack.Add(si.safeSlack)
+		current := decimal.Zero
+		if vTotal.IsPositive() {
+			current = div(a.current, vTotal)
+		}
+		if current.Sub(a.weight).Abs().LessThan(trigger) {
+			within = append(within, i)
+		}
 	}
+	return within
+}
 
-	unit := decimal.New(1, -int32(amountPrec))
-
-	if stillNeeded.IsPositive() {
-		// Reduce non-zeroed products pro-rata by their safe slack.
-		if redistSafeSlack.IsPositive() {
-			actualReduced := decimal.Zero
-			reductions := make([]decimal.Decimal, len(redistItems))
-			for i, si := range redistItems {
-				reductions[i] = si.safeSlack.Div(redistSafeSlack).Mul(stillNeeded).Truncate(int32(amountPrec))
-				actualReduced = actualReduced.Add(reductions[i])
-			}
-			for i, si := range redistItems {
-				result[si.idx] = result[si.idx].Sub(reductions[i])
-			}
-			// Distribute any truncation residual one unit at a time.
-			residual := stillNeeded.Sub(actualReduced)
-			for _, si := range redistItems {
-				if !residual.IsPositive() {
-					break
-				}
-				if result[si.idx].Sub(items[si.idx].reqGross).GreaterThanOrEqual(unit) {
-					result[si.idx] = result[si.idx].Sub(unit)
-					residual = residual.Sub(unit)
-				}
-			}
+// belowMinTrade returns the indices of positive allocations below minTrade. When every
+// positive allocation is below it, the largest is spared so that one trade remains.
+// It returns nil once at most one positive allocation is left.
+func belowMinTrade(amounts []decimal.Decimal, minTrade decimal.Decimal) []int {
+	var below []int
+	positive, largest := 0, -1
+	for i, a := range amounts {
+		if !a.IsPositive() {
+			continue
 		}
-	} else if stillNeeded.IsNegative() {
-		// We over-zeroed (last zeroed product's reqGross exceeded what was strictly needed).
-		// Add the excess back to fixed-violation products, one unit at a time.
-		excess := stillNeeded.Neg()
-		var fixedIdxs []int
-		for _, v := range violations {
-			if result[v.idx].Equal(items[v.idx].reqGross) {
-				fixedIdxs = append(fixedIdxs, v.idx)
+		positive++
+		if a.LessThan(minTrade) {
+			below = append(below, i)
+			if largest == -1 || a.GreaterThan(amounts[largest]) {
+				largest = i
 			}
 		}
-		for excess.IsPositive() && len(fixedIdxs) > 0 {
-			anyAdded := false
-			for _, idx := range fixedIdxs {
-				if !excess.IsPositive() {
-					break
-				}
-				result[idx] = result[idx].Add(unit)
-				excess = excess.Sub(unit)
-				anyAdded = true
-			}
-			if !anyAdded {
+	}
+	if positive <= 1 {
+		return nil
+	}
+	if len(below) == positive {
+		for k, i := range below {
+			if i == largest {
+				below = append(below[:k], below[k+1:]...)
 				break
 			}
 		}
 	}
+	return below
+}
+
+func sumDecimals(ds []decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for _, d := range ds {
+		total = total.Add(d)
+	}
+	return total
+}
+
+// requiredGross returns the minimum gross allocation that clears the product's initial
+// or top-up minimum, and leaves a position meeting its holding minimum, once the fee is
+// deducted; 0 if no minimum applies.
+func requiredGross(a productAlloc, amountPrec int) decimal.Decimal {
+	fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+	price, _ := decimal.NewFromString(a.mp.MarketPrice)
+
+	var minAmt, minUnits decimal.Decimal
+	if a.firstPurchase() {
+		minAmt, _ = decimal.NewFromString(a.mp.MinInitialInvestmentAmt)
+		minUnits, _ = decimal.NewFromString(a.mp.MinInitialInvestmentUnits)
+	} else {
+		minAmt, _ = decimal.NewFromString(a.mp.MinTopupAmt)
+		minUnits, _ = decimal.NewFromString(a.mp.MinTopupUnits)
+	}
 
-	return result
+	// The resulting position must also meet the holding minimum:
+	// current + net ≥ minHoldingAmt and currentUnits + netUnits ≥ minHoldingUnits.
+	minHoldAmt, _ := decimal.NewFromString(a.mp.MinHoldingAmt)
+	minHoldUnits, _ := decimal.NewFromString(a.mp.MinHoldingUnits)
+
+	// requiredNet = max(minAmt, minUnits × price / exchangeRate,
+	//                   minHoldingAmt − current, (minHoldingUnits − currentUnits) × price / exchangeRate),
+	// in base currency.
+	fx := exchangeRate(a.mp)
+	requiredNet := decimal.Max(
+		minAmt,
+		div(minUnits.Mul(price), fx),
+		minHoldAmt.Sub(a.current),
+		div(minHoldUnits.Sub(a.currentUnits).Mul(price), fx),
+	)
+
+	// requiredGross = ⌈requiredNet / (1 − fee)⌉ at amountPrec decimal places.
+	if !requiredNet.IsPositive() {
+		return decimal.Zero
+	}
+	divisor := decimal.NewFromInt(1).Sub(fee)
+	if !divisor.IsPositive() {
+		return decimal.Zero
+	}
+	return ceilToPrec(div(requiredNet, divisor), int32(amountPrec))
+}
+
+// parseOptionalInt parses an optional non-negative integer field, such as a product's
+// priority or a goal's maxTrades; an absent, negative or malformed value is 0.
+func parseOptionalInt(s string) int {
+	p, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || p < 0 {
+		return 0
+	}
+	return p
+}
+
+// GetExpectedUnits returns the units a buy of grossAmount of mp is expected to receive: the
+// amount left after mp's transactionFee, converted at its exchangeRate and divided by its
+// marketPrice, truncated to unitPrec decimal places as every investment's units are. A
+// unitPrec of 0 gives whole units. It is 0 when mp has no positive price. ticker names the
+// product for the caller; the figures all come from mp.
+func GetExpectedUnits(ticker string, grossAmount decimal.Decimal, mp models.ModelItem, unitPrec int) decimal.Decimal {
+	price, _ := decimal.NewFromString(mp.MarketPrice)
+	if !price.IsPositive() {
+		return decimal.Zero
+	}
+	fee, _ := decimal.NewFromString(mp.TransactionFee)
+	net := grossAmount.Mul(decimal.NewFromInt(1).Sub(fee))
+	return div(net.Mul(exchangeRate(mp)), price).Truncate(int32(unitPrec))
+}
+
+// exchangeRate returns the number of local-currency units per base-currency unit for mp.
+// An absent rate means the product is quoted in the base currency (rate = 1).
+func exchangeRate(mp models.ModelItem) decimal.Decimal {
+	fx, err := decimal.NewFromString(mp.ExchangeRate)
+	if err != nil || !fx.IsPositive() {
+		return decimal.NewFromInt(1)
+	}
+	return fx
 }
 
 // ceilToPrec rounds d up to the given number of decimal places.
@@ -0,0 +1,89 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestInvestmentWithNothingToAllocate(t *testing.T) {
+	tests := []struct {
+		name string
+		goal models.Goal
+	}{
+		{
+			name: "all-zero weights",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithTicker("A", "0", "10").
+				WithTicker("B", "0", "20").
+				Build(),
+		},
+		{
+			name: "only a zero-weight cash line",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithTicker("CASH", "0", "1").
+				WithHolding("A", "10", "10").
+				Build(),
+		},
+		{
+			name: "empty modelPortfolioDetails",
+			goal: testhelpers.NewGoalBuilder("g1").Build(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessInvestment(tt.goal, 2, 4, Options{})
+			if result.Error == nil || result.Error.Code != models.CodeNothingToAllocate {
+				t.Fatalf("goal error = %+v, want %s", result.Error, models.CodeNothingToAllocate)
+			}
+			if result.TransactionDetails == nil || len(result.TransactionDetails) != 0 {
+				t.Errorf("transactionDetails = %v, want an empty list", result.TransactionDetails)
+			}
+		})
+	}
+}
+
+func TestInvestmentWithZeroFee(t *testing.T) {
+	tests := []struct {
+		name string
+		fee  string
+	}{
+		{"fee of 0", "0"},
+		{"fee of 0.0000", "0.0000"},
+		{"absent fee", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := testhelpers.NewGoalBuilder("g1").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithMarketPrice("10").
+					WithTransactionFee(tt.fee).Build()).
+				Build()
+			result := ProcessInvestment(goal, 2, 4, Options{})
+			if result.Error != nil {
+				t.Fatalf("goal error = %+v, want none", result.Error)
+			}
+			d := byTicker(result)["A"]
+			if d.Value != "1000.00" || d.Units != "100.0000" {
+				t.Errorf("A = %s (%s units), want 1000.00 (100.0000 units)", d.Value, d.Units)
+			}
+		})
+	}
+}
+
+func TestInvestmentFallbackWhenEveryProductIsOverweight(t *testing.T) {
+	// The weights sum to 0.5, so both products are already above their model weight, no
+	// shortfall is positive and the order is spread by weight rather than divided by a
+	// zero total.
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithOrderAmount("100").
+		WithTicker("A", "0.25", "1").
+		WithTicker("B", "0.25", "1").
+		WithHolding("A", "1000", "1").
+		WithHolding("B", "1000", "1").
+		Build()
+	got := byTicker(ProcessInvestment(goal, 2, 4, Options{}))
+	if got["A"].Value != "50.00" || got["B"].Value != "50.00" {
+		t.Errorf("A = %s, B = %s, want 50.00 each", got["A"].Value, got["B"].Value)
+	}
+}
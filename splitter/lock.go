@@ -0,0 +1,39 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// lockHoldings re-splits the order under LockCurrentHoldings so that no held model
+// product's share of the goal falls: ideal_i = current_i / Σ current × orderAmount for
+// every held product, which keeps V_i / V_total fixed for each of them. Every product not
+// yet held gets an ideal of 0, and one the strategy would have bought is dropped with
+// HOLDINGS_LOCKED; buying it would have diluted the held positions. A goal holding none of
+// its model products has nothing to lock, and the ideals are left untouched; it reports
+// whether the lock applied.
+func lockHoldings(allocs []productAlloc, dropped map[int][]models.TradeError, orderAmount decimal.Decimal) bool {
+	held := decimal.Zero
+	for _, a := range allocs {
+		if a.current.IsPositive() {
+			held = held.Add(a.current)
+		}
+	}
+	if !held.IsPositive() {
+		return false
+	}
+	for i, a := range allocs {
+		if a.current.IsPositive() {
+			allocs[i].ideal = div(a.current, held).Mul(orderAmount)
+			continue
+		}
+		if a.ideal.IsPositive() {
+			dropped[i] = []models.TradeError{{
+				Message: "Not bought because lockCurrentHoldings keeps the order in the products already held",
+				Code:    models.CodeHoldingsLocked,
+			}}
+		}
+		allocs[i].ideal = decimal.Zero
+	}
+	return true
+}
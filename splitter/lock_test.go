@@ -0,0 +1,56 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestLockCurrentHoldings(t *testing.T) {
+	tests := []struct {
+		name    string
+		goal    models.Goal
+		want    map[string]string
+		flagged map[string]models.Code
+	}{
+		{
+			// By shortfall C, which is not held, would get most of the 1000, diluting A
+			// and B; locked, the order goes to A and B in proportion to their holdings.
+			name: "keeps held shares",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithTicker("A", "0.3", "1").
+				WithTicker("B", "0.3", "1").
+				WithTicker("C", "0.4", "1").
+				WithHolding("A", "300", "1").
+				WithHolding("B", "100", "1").
+				Build(),
+			want:    map[string]string{"A": "750.00", "B": "250.00", "C": "0.00"},
+			flagged: map[string]models.Code{"C": models.CodeHoldingsLocked},
+		},
+		{
+			name: "nothing held",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithTicker("A", "0.5", "1").
+				WithTicker("B", "0.5", "1").
+				Build(),
+			want: map[string]string{"A": "500.00", "B": "500.00"},
+		},
+	}
+	opts := Options{LockCurrentHoldings: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessInvestment(tt.goal, 2, 4, opts)
+			got := byTicker(result)
+			assertValues(t, got, tt.want)
+			for ticker := range tt.want {
+				if code := errorCode(got[ticker]); code != tt.flagged[ticker] {
+					t.Errorf("%s flagged %q, want %q", ticker, code, tt.flagged[ticker])
+				}
+			}
+			if check := VerifyResult(tt.goal, result, 2, 4, opts); !check.Passed {
+				t.Errorf("self-check failed: %+v", check.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package splitter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// lockedTickers returns the held products of goal still within their lock-up: those whose
+// holdingSince is fewer than their model item's minHoldingPeriodDays before today, as
+// calendar days in UTC. Holdings without a date, and products without a period, are never
+// locked; nor are kept holdings, which are not sold anyway.
+func lockedTickers(goal models.Goal, today time.Time) map[string]bool {
+	model := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		model[mp.Ticker] = mp
+	}
+	today = today.UTC().Truncate(24 * time.Hour)
+	out := make(map[string]bool)
+	for _, h := range goal.GoalDetails {
+		mp, inModel := model[h.Ticker]
+		if !inModel {
+			continue
+		}
+		if value, _ := decimal.NewFromString(h.Value); !value.IsPositive() {
+			continue
+		}
+		if w, _ := decimal.NewFromString(mp.Weight); !w.IsPositive() && (h.Keep || goal.KeepUnmodeled) {
+			continue
+		}
+		period, err := strconv.Atoi(strings.TrimSpace(mp.MinHoldingPeriodDays))
+		if err != nil || period <= 0 {
+			continue
+		}
+		since, err := time.Parse("2006-01-02", strings.TrimSpace(h.HoldingSince))
+		if err != nil {
+			continue
+		}
+		if int(today.Sub(since).Hours()/24) < period {
+			out[h.Ticker] = true
+		}
+	}
+	return out
+}
+
+// lockedRedemption splits a redemption whose goal holds products within their lock-up. The
+// products are held back as if all their units were blocked, so their share of the order
+// goes to the other holdings, and each is reported as a zero sell flagged
+// models.CodeHoldingPeriod.
+func lockedRedemption(goal models.Goal, locked map[string]bool, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	held := goal
+	held.GoalDetails = make([]models.Holding, len(goal.GoalDetails))
+	for i, h := range goal.GoalDetails {
+		if locked[h.Ticker] {
+			h.BlockedUnits = h.Units
+			h.HoldingSince = ""
+		}
+		held.GoalDetails[i] = h
+	}
+	result := ProcessRedemption(held, amountPrec, unitPrec, opts)
+	details := result.TransactionDetails[:0:0]
+	for _, d := range result.TransactionDetails {
+		if !locked[d.Ticker] || d.Direction != "SELL" {
+			details = append(details, d)
+		}
+	}
+	for _, h := range goal.GoalDetails {
+		if locked[h.Ticker] {
+			details = append(details, withErrors(models.TransactionDetail{
+				Ticker:    h.Ticker,
+				Direction: "SELL",
+				Value:     decimal.Zero.StringFixed(int32(amountPrec)),
+				Units:     decimal.Zero.StringFixed(int32(unitPrec)),
+			}, models.TradeError{
+				Message: "Cannot sell this ticker because it is within its minimum holding period",
+				Code:    models.CodeHoldingPeriod,
+			}))
+		}
+	}
+	result.TransactionDetails = details
+	return result
+}
@@ -0,0 +1,99 @@
+package splitter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Lot selection methods accepted in Options.LotSelection.
+const (
+	// LotFIFO sells the earliest acquired lots first. This is the default.
+	LotFIFO = "fifo"
+	// LotLIFO sells the latest acquired lots first.
+	LotLIFO = "lifo"
+	// LotHIFO sells the lots with the highest cost per unit first.
+	LotHIFO = "hifo"
+	// LotMinGain sells the lots with the smallest gain per unit first, losses before gains.
+	// Every lot of a sell has the same price, so this is the LotHIFO order under another name.
+	LotMinGain = "min_gain"
+)
+
+// ValidLotSelection reports whether method is empty or a known lot selection method.
+func ValidLotSelection(method string) bool {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case "", LotFIFO, LotLIFO, LotHIFO, LotMinGain:
+		return true
+	}
+	return false
+}
+
+// sellLots allocates a sell of units of a holding at price across its lots, in the order
+// opts.LotSelection sells them, and returns the units taken from each lot with the gain
+// realised on them, (price − costPerUnit) × units, rounded to amountPrec.
+//
+// With opts.ScaleLotUnits the lots are first scaled to add up to heldUnits. Units left over
+// once every lot is exhausted, at most the rounding tolerance validation allows, are taken
+// from the last lot sold.
+func sellLots(lots []models.Lot, units, price, heldUnits decimal.Decimal, amountPrec, unitPrec int, opts Options) []models.LotSale {
+	type lot struct {
+		models.Lot
+		units, cost decimal.Decimal
+	}
+	ordered := make([]lot, len(lots))
+	lotTotal := decimal.Zero
+	for i, l := range lots {
+		ordered[i].Lot = l
+		ordered[i].units, _ = decimal.NewFromString(strings.TrimSpace(l.Units))
+		ordered[i].cost, _ = decimal.NewFromString(strings.TrimSpace(l.CostPerUnit))
+		lotTotal = lotTotal.Add(ordered[i].units)
+	}
+	if opts.ScaleLotUnits && lotTotal.IsPositive() && !lotTotal.Equal(heldUnits) {
+		for i := range ordered {
+			ordered[i].units = div(ordered[i].units.Mul(heldUnits), lotTotal).Truncate(int32(unitPrec))
+		}
+	}
+
+	// Acquisition dates are ISO 8601, so they sort as strings. Ties keep the lots' order.
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		switch opts.LotSelection {
+		case LotLIFO:
+			return a.AcquisitionDate > b.AcquisitionDate
+		case LotHIFO:
+			return a.cost.GreaterThan(b.cost)
+		case LotMinGain:
+			return price.Sub(a.cost).LessThan(price.Sub(b.cost))
+		}
+		return a.AcquisitionDate < b.AcquisitionDate
+	})
+
+	var sales []models.LotSale
+	var sold []decimal.Decimal
+	remaining := units
+	for _, l := range ordered {
+		if !remaining.IsPositive() {
+			break
+		}
+		take := decimal.Min(remaining, l.units)
+		if !take.IsPositive() {
+			continue
+		}
+		sales = append(sales, models.LotSale{AcquisitionDate: l.AcquisitionDate, CostPerUnit: l.CostPerUnit})
+		sold = append(sold, take)
+		remaining = remaining.Sub(take)
+	}
+	if remaining.IsPositive() && len(sold) > 0 {
+		sold[len(sold)-1] = sold[len(sold)-1].Add(remaining)
+	}
+
+	prec := int32(amountPrec)
+	for i := range sales {
+		cost, _ := decimal.NewFromString(strings.TrimSpace(sales[i].CostPerUnit))
+		sales[i].Units = sold[i].StringFixed(int32(unitPrec))
+		sales[i].RealizedGain = price.Sub(cost).Mul(sold[i]).Round(prec).StringFixed(prec)
+	}
+	return sales
+}
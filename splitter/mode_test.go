@@ -0,0 +1,55 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestAllocationModeIsAnAliasOfAllocationStrategy(t *testing.T) {
+	// By shortfall the 300 of A already held moves most of the order to B; pro_rata splits
+	// the order by weight.
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithTicker("A", "0.5", "1").
+		WithTicker("B", "0.5", "1").
+		WithHolding("A", "300", "1").
+		Build()
+	for _, mode := range []string{"", StrategyShortfall, StrategyProRata} {
+		t.Run(mode, func(t *testing.T) {
+			opts := RequestOptions(models.SplitRequest{AllocationMode: mode})
+			if opts.AllocationStrategy != mode {
+				t.Errorf("AllocationStrategy = %q, want %q", opts.AllocationStrategy, mode)
+			}
+			got := byTicker(ProcessInvestment(goal, 2, 4, opts))
+			want := map[string]string{"A": "350.00", "B": "650.00"}
+			if mode == StrategyProRata {
+				want = map[string]string{"A": "500.00", "B": "500.00"}
+			}
+			assertValues(t, got, want)
+		})
+	}
+}
+
+func TestAllocationStrategyTakesPriorityOverAllocationMode(t *testing.T) {
+	opts := RequestOptions(models.SplitRequest{AllocationStrategy: StrategyEqual, AllocationMode: StrategyProRata})
+	if opts.AllocationStrategy != StrategyEqual {
+		t.Errorf("AllocationStrategy = %q, want %q", opts.AllocationStrategy, StrategyEqual)
+	}
+}
+
+func TestIgnoreCurrentHoldingsResolvesToProRata(t *testing.T) {
+	for _, req := range []models.SplitRequest{
+		{IgnoreCurrentHoldings: true},
+		{IgnoreCurrentHoldings: true, AllocationMode: StrategyShortfall},
+		{IgnoreCurrentHoldings: true, AllocationStrategy: StrategyProRata},
+	} {
+		opts := RequestOptions(req)
+		if opts.AllocationStrategy != StrategyProRata {
+			t.Errorf("%+v: AllocationStrategy = %q, want %q", req, opts.AllocationStrategy, StrategyProRata)
+		}
+		if !opts.IgnoreCurrentHoldings {
+			t.Errorf("%+v: IgnoreCurrentHoldings = false, want true", req)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package splitter
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// ParsedModelPortfolio is a model portfolio with its weights parsed as modelWeights
+// returns them.
+type ParsedModelPortfolio struct {
+	Weights []decimal.Decimal
+}
+
+// ModelPortfolioCache keeps the parsed weights of every model portfolio a request uses, so
+// that goals sharing a portfolio parse it once. Entries are keyed by ModelPortfolioID and a
+// hash of the weights sent, the only part of modelPortfolioDetails the parsed weights
+// depend on: a goal that sends different weights under the same ID gets its own entry
+// rather than a stale one. The weights also depend on Options, so a cache must not be
+// shared across requests. It is safe for concurrent use by the goals of one request.
+type ModelPortfolioCache struct {
+	seed    maphash.Seed
+	entries sync.Map // modelKey → *ParsedModelPortfolio
+}
+
+// modelKey identifies a model portfolio's weights in a ModelPortfolioCache.
+type modelKey struct {
+	id  string
+	sum uint64
+}
+
+// NewModelPortfolioCache returns an empty ModelPortfolioCache.
+func NewModelPortfolioCache() *ModelPortfolioCache {
+	return &ModelPortfolioCache{seed: maphash.MakeSeed()}
+}
+
+// weights returns modelWeights(goal, opts), from the cache when goal's model portfolio has
+// been parsed before. The slice is the caller's to modify.
+func (c *ModelPortfolioCache) weights(goal models.Goal, opts Options) []decimal.Decimal {
+	key := modelKey{id: goal.ModelPortfolioID, sum: c.hash(goal.ModelPortfolioDetails)}
+	if p, ok := c.entries.Load(key); ok {
+		return slices.Clone(p.(*ParsedModelPortfolio).Weights)
+	}
+	p, _ := c.entries.LoadOrStore(key, &ParsedModelPortfolio{Weights: parseModelWeights(goal, opts)})
+	return slices.Clone(p.(*ParsedModelPortfolio).Weights)
+}
+
+// hash returns a hash of the weights of items, in order.
+func (c *ModelPortfolioCache) hash(items []models.ModelItem) uint64 {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	for _, mp := range items {
+		h.WriteString(mp.Weight)
+		h.WriteByte(0)
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,35 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestModelPortfolioCacheKeysOnWeights(t *testing.T) {
+	cache := NewModelPortfolioCache()
+	opts := Options{ModelCache: cache}
+	goal := func(weightA, weightB string) *testhelpers.GoalBuilder {
+		return testhelpers.NewGoalBuilder("g").
+			WithModelPortfolioID("mp-1").
+			WithTicker("A", weightA, "1").
+			WithTicker("B", weightB, "1")
+	}
+	first := modelWeights(goal("0.6", "0.4").Build(), opts)
+	// The same ID with other weights must not be served the first goal's weights.
+	other := modelWeights(goal("0.3", "0.7").Build(), opts)
+	if other[0].String() != "0.3" || other[1].String() != "0.7" {
+		t.Errorf("weights = %v, want [0.3 0.7]", other)
+	}
+	// Callers may modify the slice they get without changing the cached entry.
+	first[0] = first[1]
+	again := modelWeights(goal("0.6", "0.4").Build(), opts)
+	if again[0].String() != "0.6" || again[1].String() != "0.4" {
+		t.Errorf("weights = %v, want [0.6 0.4]", again)
+	}
+	entries := 0
+	cache.entries.Range(func(any, any) bool { entries++; return true })
+	if entries != 2 {
+		t.Errorf("cache holds %d entries, want 2", entries)
+	}
+}
@@ -0,0 +1,123 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Missing-price policies accepted in Options.MissingPricePolicy. They apply to redemptions
+// with Options.AllowMissingPrice.
+const (
+	MissingPriceExclude = "exclude" // leave the holding unsold and flag it NO_PRICE (default)
+	MissingPriceManual  = "manual"  // sell it as usual, as a manual instruction by units or value
+)
+
+// Manual instructions set on TransactionDetail.ManualInstruction.
+const (
+	ManualByUnits = "units" // a sell of the whole holding, placed by its units
+	ManualByValue = "value" // a partial sell, placed by its value; units are left empty
+)
+
+// ValidMissingPricePolicy reports whether name is an accepted missing-price policy (empty
+// means default).
+func ValidMissingPricePolicy(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", MissingPriceExclude, MissingPriceManual:
+		return true
+	}
+	return false
+}
+
+// MissingPrice reports whether s does not give a usable market price: it is empty, zero or
+// not a number. A negative price is invalid rather than missing.
+func MissingPrice(s string) bool {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	return err != nil || d.IsZero()
+}
+
+// noPriceTickers returns the held products of goal whose sells could not be converted to
+// units: those whose model item has a positive weight and a missing price, and the other
+// holdings with a missing price of their own. Kept holdings are never sold and are left out.
+func noPriceTickers(goal models.Goal) map[string]bool {
+	model := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		model[mp.Ticker] = mp
+	}
+	out := make(map[string]bool)
+	for _, h := range goal.GoalDetails {
+		if value, _ := decimal.NewFromString(h.Value); !value.IsPositive() {
+			continue
+		}
+		price := h.MarketPrice
+		if w, _ := decimal.NewFromString(model[h.Ticker].Weight); w.IsPositive() {
+			price = model[h.Ticker].MarketPrice
+		} else if h.Keep || goal.KeepUnmodeled {
+			continue
+		}
+		if MissingPrice(price) {
+			out[h.Ticker] = true
+		}
+	}
+	return out
+}
+
+// missingPriceRedemption splits a redemption whose goal holds products without a price,
+// per opts.MissingPricePolicy.
+//
+// Under MissingPriceExclude the products are held back as if all their units were blocked,
+// so their share of the order goes to the other holdings, and each is reported as a zero
+// sell flagged models.CodeNoPrice. Under MissingPriceManual they are sold as usual; a sell of the
+// whole holding carries its units and ManualByUnits, and any other sell, whose units cannot
+// be known, carries empty units and ManualByValue.
+func missingPriceRedemption(goal models.Goal, noPrice map[string]bool, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	opts.AllowMissingPrice = false
+	if opts.MissingPricePolicy == MissingPriceManual {
+		result := ProcessRedemption(goal, amountPrec, unitPrec, opts)
+		for i, d := range result.TransactionDetails {
+			value, _ := decimal.NewFromString(d.Value)
+			if !noPrice[d.Ticker] || d.Direction != "SELL" || !value.IsPositive() {
+				continue
+			}
+			if d.FullRedemption {
+				result.TransactionDetails[i].ManualInstruction = ManualByUnits
+			} else {
+				result.TransactionDetails[i].Units = ""
+				result.TransactionDetails[i].ManualInstruction = ManualByValue
+			}
+		}
+		return result
+	}
+
+	held := goal
+	held.GoalDetails = make([]models.Holding, len(goal.GoalDetails))
+	for i, h := range goal.GoalDetails {
+		if noPrice[h.Ticker] {
+			h.BlockedUnits = h.Units
+		}
+		held.GoalDetails[i] = h
+	}
+	result := ProcessRedemption(held, amountPrec, unitPrec, opts)
+	details := result.TransactionDetails[:0:0]
+	for _, d := range result.TransactionDetails {
+		if !noPrice[d.Ticker] || d.Direction != "SELL" {
+			details = append(details, d)
+		}
+	}
+	for _, h := range goal.GoalDetails {
+		if noPrice[h.Ticker] {
+			details = append(details, withErrors(models.TransactionDetail{
+				Ticker:    h.Ticker,
+				Direction: "SELL",
+				Value:     decimal.Zero.StringFixed(int32(amountPrec)),
+				Units:     decimal.Zero.StringFixed(int32(unitPrec)),
+			}, models.TradeError{
+				Message: "Cannot sell this ticker because it has no market price",
+				Code:    models.CodeNoPrice,
+			}))
+		}
+	}
+	result.TransactionDetails = details
+	return result
+}
@@ -0,0 +1,79 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Repair objectives accepted in Options.RepairObjective.
+const (
+	// RepairMostFixed greedily fixes violations highest priority, cheapest bump first,
+	// maximising the number of violations cleared. This is the default.
+	RepairMostFixed = "most_fixed"
+	// RepairMaxInvested fixes the subset of violations that maximises the amount that can
+	// actually be traded, i.e. minimises the gross stranded on still-violating products.
+	RepairMaxInvested = "max_invested"
+	// RepairMinDrift fixes the subset of violations that minimises the post-trade
+	// deviation from the ideal allocation.
+	RepairMinDrift = "min_drift"
+)
+
+// maxExhaustiveViolations bounds the subset search in bestRepair (2^n candidate plans).
+// With more violations than this the greedy most_fixed repair is applied instead.
+const maxExhaustiveViolations = 12
+
+// bestRepair evaluates every subset of violations to attempt — a small 0/1 knapsack where
+// each violation costs its bump and the capacity is the safe slack plus whatever zeroing
+// other products frees — and returns the repaired amounts that score best under
+// opts.RepairObjective. Each subset is funded by apply, so every candidate keeps
+// the same sum invariant as the greedy repair. Ties keep the earlier subset, which favours
+// the greedy ordering.
+func (r *repairer) bestRepair(items []repairItem, grossAmounts []decimal.Decimal, violations []violation) []decimal.Decimal {
+	best := grossAmounts
+	bestScore := repairScore(r.allocs, items, grossAmounts, violations, r.opts.RepairObjective)
+	n := len(violations)
+	for mask := (1 << n) - 1; mask > 0; mask-- {
+		attempt := make(map[int]bool, n)
+		for b := 0; b < n; b++ {
+			if mask&(1<<b) != 0 {
+				attempt[violations[b].idx] = true
+			}
+		}
+		candidate := r.apply(items, grossAmounts, violations, attempt)
+		if score := repairScore(r.allocs, items, candidate, violations, r.opts.RepairObjective); score.GreaterThan(bestScore) {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// repairScore rates a repaired allocation; higher is better.
+//
+//   - max_invested: the total gross on products that can trade, i.e. everything except
+//     violations left below their requirement.
+//   - min_drift: minus Σ |net_i − ideal_i|, where products still violating trade nothing.
+func repairScore(allocs []productAlloc, items []repairItem, amounts []decimal.Decimal, violations []violation, objective string) decimal.Decimal {
+	stranded := make(map[int]bool, len(violations))
+	for _, v := range violations {
+		if amounts[v.idx].LessThan(items[v.idx].reqGross) {
+			stranded[v.idx] = true
+		}
+	}
+
+	one := decimal.NewFromInt(1)
+	score := decimal.Zero
+	for i, a := range allocs {
+		traded := amounts[i]
+		if stranded[i] {
+			traded = decimal.Zero
+		}
+		switch objective {
+		case RepairMaxInvested:
+			score = score.Add(traded)
+		default: // RepairMinDrift
+			fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+			net := traded.Mul(one.Sub(fee))
+			score = score.Sub(net.Sub(a.ideal).Abs())
+		}
+	}
+	return score
+}
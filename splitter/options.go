@@ -0,0 +1,237 @@
+package splitter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Options carries request-level settings that apply to every goal in a request.
+// Goal-level fields, where they exist, take priority over the values here.
+type Options struct {
+	VolatilityBuffer string
+	MinTradeAmt      string
+
+	// ZeroOnMinViolation zeroes investment products whose minimum cannot be met and
+	// redistributes their allocation, instead of the default flag-and-keep.
+	ZeroOnMinViolation bool
+
+	// ResidualPolicy selects how the investment repair step hands back leftover units and
+	// how redemptions hand out the truncation residual; see distributeResidual. Empty means
+	// ResidualLargestRemainder.
+	ResidualPolicy string
+
+	// AllocationStrategy selects how investment ideals are computed; see strategyFor.
+	AllocationStrategy string
+
+	// IncludeEffectiveWeights adds GoalResult.EffectiveWeights to investment results.
+	IncludeEffectiveWeights bool
+
+	// LockCurrentHoldings splits investments over the model products already held, in
+	// proportion to their current value, so that no held position's share of the goal
+	// falls; see lockHoldings. Products not yet held are not bought and the repair step is
+	// skipped. A goal holding none of its model products is split as usual.
+	LockCurrentHoldings bool
+
+	// IgnoreCurrentHoldings splits investments as if the goal held nothing, so
+	// initial-investment rather than top-up minimums apply. It does not pick the strategy:
+	// RequestOptions sets AllocationStrategy to StrategyProRata alongside it. It does not
+	// affect redemptions, nor the BUY leg of a rebuilt Big Redemption.
+	IgnoreCurrentHoldings bool
+
+	// RepairObjective selects how the investment repair step chooses which violations to
+	// fix; empty means RepairMostFixed. When set, the applied objective is reported in
+	// GoalResult.Debug.
+	RepairObjective string
+
+	// RepairStrategy selects how the investment repair step takes the bumps back from
+	// the products funding them; empty means RepairStrategyGreedy.
+	RepairStrategy string
+
+	// MinProgressGuard stops the repair step from zeroing a product when that would
+	// increase its deviation from the model weight.
+	MinProgressGuard bool
+
+	// TieBreak orders repair zero-out candidates that tie on priority and required gross;
+	// empty means TieBreakModelOrder.
+	TieBreak string
+
+	// ZeroOutMaxWeight, when set, protects products whose model weight exceeds it from
+	// being zeroed by the repair step.
+	ZeroOutMaxWeight string
+
+	// MinWeightDeviationTrigger, when set, leaves investment products whose current weight
+	// is within it of their model weight untraded; see withinDeviation.
+	MinWeightDeviationTrigger string
+
+	// NormalizeWeights rescales each goal's model weights to sum to 1 before any
+	// allocation math; see modelWeights.
+	NormalizeWeights bool
+
+	// ShrinkageCoefficient, when set, blends each goal's model weights toward equal weight
+	// before any allocation math; see modelWeights.
+	ShrinkageCoefficient string
+
+	// Explain adds a models.Trace to every transaction detail showing how its value was
+	// derived. The API also attaches VerifyResult's report to each goal.
+	Explain bool
+
+	// MinHoldingPolicy selects what the redemption repair step does with a Phase 2 sell
+	// that would leave less than the holding minimum behind; empty means MinHoldingFlag.
+	MinHoldingPolicy string
+
+	// RebuildBigRedemption splits a redemption in the Big Redemption band as a sell of the
+	// order plus the volatility buffer and a buy reinvesting the buffer to model; see
+	// rebuildBigRedemption.
+	RebuildBigRedemption bool
+
+	// ProceedsMode selects whether redemption sells are sized on their gross value or on
+	// their proceeds after the transaction fee; empty means ProceedsGross.
+	ProceedsMode string
+
+	// RedemptionMinimumBasis selects whether minRedemptionAmt is checked against the gross
+	// sell or its proceeds; empty means MinimumBasisGross. See feeAdjustedGoal.
+	RedemptionMinimumBasis string
+
+	// Phase1Order selects the order in which a redemption sells its zero-weight and absent
+	// products; empty means Phase1SmallestFirst.
+	Phase1Order string
+
+	// FullRedemptionTolerance, when set, treats a redemption of at least this fraction of
+	// the goal value as an order for all of it, so that an order sized on a stale valuation
+	// closes every position instead of leaving dust.
+	FullRedemptionTolerance string
+
+	// AllowMissingPrice lets a redemption hold products without a market price, handled per
+	// MissingPricePolicy (see missingPriceRedemption).
+	AllowMissingPrice  bool
+	MissingPricePolicy string
+
+	// LotSelection selects the order in which a redemption sell takes units from the lots of
+	// its holding; empty means LotFIFO. See sellLots.
+	LotSelection string
+
+	// ScaleLotUnits scales each holding's lots to add up to its units instead of relying on
+	// validation to have checked that they do.
+	ScaleLotUnits bool
+
+	// WhatIfAmounts lists alternative order amounts to re-run every investment goal at;
+	// see whatIf.
+	WhatIfAmounts []string
+
+	// ModelCache, when set, shares parsed model weights between the goals of a request;
+	// see ModelPortfolioCache.
+	ModelCache *ModelPortfolioCache
+
+	// ConsolidationThreshold sells every redemption position worth less than it in full,
+	// whatever its model weight; see consolidatedRedemption. Empty means no consolidation.
+	ConsolidationThreshold string
+
+	// Today is the date redemptions measure holding periods to; zero means now. See
+	// lockedTickers.
+	Today time.Time
+}
+
+// today returns opts.Today, or the current time when it is not set.
+func (opts Options) today() time.Time {
+	if opts.Today.IsZero() {
+		return time.Now()
+	}
+	return opts.Today
+}
+
+// modelWeights parses the weight of every item in goal.ModelPortfolioDetails, in order.
+// With opts.NormalizeWeights each weight is divided by their sum, so that weights from an
+// upstream system that sum to slightly more or less than 1 are used as exact proportions.
+// A model whose weights sum to zero is left as is.
+//
+// With opts.ShrinkageCoefficient s, each positive weight is then blended toward equal
+// weight as w' = (1 − s) × w + s / N, where N is the number of products with a positive
+// weight. Zero-weight products stay at zero.
+//
+// With opts.ModelCache the weights are parsed once per model portfolio; see
+// ModelPortfolioCache.
+func modelWeights(goal models.Goal, opts Options) []decimal.Decimal {
+	if opts.ModelCache != nil {
+		return opts.ModelCache.weights(goal, opts)
+	}
+	return parseModelWeights(goal, opts)
+}
+
+// parseModelWeights is modelWeights without the cache.
+func parseModelWeights(goal models.Goal, opts Options) []decimal.Decimal {
+	weights := make([]decimal.Decimal, len(goal.ModelPortfolioDetails))
+	total := decimal.Zero
+	positive := 0
+	for i, mp := range goal.ModelPortfolioDetails {
+		weights[i], _ = decimal.NewFromString(mp.Weight)
+		total = total.Add(weights[i])
+		if weights[i].IsPositive() {
+			positive++
+		}
+	}
+	if opts.NormalizeWeights && total.IsPositive() {
+		for i := range weights {
+			weights[i] = div(weights[i], total)
+		}
+	}
+	if shrink := shrinkage(opts); shrink.IsPositive() && positive > 0 {
+		equal := div(shrink, decimal.NewFromInt(int64(positive)))
+		keep := decimal.NewFromInt(1).Sub(shrink)
+		for i := range weights {
+			if weights[i].IsPositive() {
+				weights[i] = keep.Mul(weights[i]).Add(equal)
+			}
+		}
+	}
+	return weights
+}
+
+// RoundWeights returns goal with the weight of every model item rounded to prec decimal
+// places, for upstream systems whose weights carry digits past those that are significant
+// (such as a spreadsheet export). Weights that do not parse are left as sent.
+func RoundWeights(goal models.Goal, prec int) models.Goal {
+	items := make([]models.ModelItem, len(goal.ModelPortfolioDetails))
+	copy(items, goal.ModelPortfolioDetails)
+	for i, mp := range items {
+		if w, err := decimal.NewFromString(strings.TrimSpace(mp.Weight)); err == nil {
+			items[i].Weight = w.Round(int32(prec)).String()
+		}
+	}
+	goal.ModelPortfolioDetails = items
+	return goal
+}
+
+// shrinkage parses opts.ShrinkageCoefficient; empty means 0 (no shrinkage).
+func shrinkage(opts Options) decimal.Decimal {
+	d, _ := decimal.NewFromString(strings.TrimSpace(opts.ShrinkageCoefficient))
+	return d
+}
+
+// weightsTransformed reports whether modelWeights returns anything other than the weights
+// as sent.
+func weightsTransformed(opts Options) bool {
+	return opts.NormalizeWeights || shrinkage(opts).IsPositive()
+}
+
+// minTradeAmt resolves the global minimum trade size for a goal: the goal-level value
+// when present, otherwise the request-level one. Zero means no minimum.
+func minTradeAmt(goal models.Goal, opts Options) decimal.Decimal {
+	s := opts.MinTradeAmt
+	if strings.TrimSpace(goal.MinTradeAmt) != "" {
+		s = goal.MinTradeAmt
+	}
+	d, _ := decimal.NewFromString(strings.TrimSpace(s))
+	return d
+}
+
+// volatilityBuffer resolves the volatility buffer for a goal: the goal-level value when
+// present, otherwise the request-level one. Empty means no buffer.
+func volatilityBuffer(goal models.Goal, opts Options) string {
+	if strings.TrimSpace(goal.VolatilityBuffer) != "" {
+		return goal.VolatilityBuffer
+	}
+	return opts.VolatilityBuffer
+}
@@ -0,0 +1,47 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// projectedValue returns the holding's value once its pending orders settle:
+// value + pendingValue, floored at 0. pendingValue is negative for pending sells.
+func projectedValue(h models.Holding) decimal.Decimal {
+	val, _ := decimal.NewFromString(h.Value)
+	pending, _ := decimal.NewFromString(h.PendingValue)
+	val = val.Add(pending)
+	if val.IsNegative() {
+		return decimal.Zero
+	}
+	return val
+}
+
+// projectedUnits returns the holding's units once its pending orders settle,
+// floored at 0.
+func projectedUnits(h models.Holding) decimal.Decimal {
+	units, _ := decimal.NewFromString(h.Units)
+	pending, _ := decimal.NewFromString(h.PendingUnits)
+	return decimal.Max(units.Add(pending), decimal.Zero)
+}
+
+// sellableHoldings returns a copy of holdings with Value and Units reduced by any pending
+// sells, so that a redemption never sells units already committed to an unsettled order.
+// Pending buys are not yet held and are not sellable, so they leave the holding unchanged.
+func sellableHoldings(holdings []models.Holding) []models.Holding {
+	out := make([]models.Holding, len(holdings))
+	for i, h := range holdings {
+		pendingValue, _ := decimal.NewFromString(h.PendingValue)
+		if pendingValue.IsNegative() {
+			val, _ := decimal.NewFromString(h.Value)
+			h.Value = decimal.Max(val.Add(pendingValue), decimal.Zero).String()
+		}
+		pendingUnits, _ := decimal.NewFromString(h.PendingUnits)
+		if pendingUnits.IsNegative() {
+			units, _ := decimal.NewFromString(h.Units)
+			h.Units = decimal.Max(units.Add(pendingUnits), decimal.Zero).String()
+		}
+		out[i] = h
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// HasRedemptionPercents reports whether any holding of goal sets RedemptionPercent, making
+// it a redemption by percentage rather than by orderAmount.
+func HasRedemptionPercents(goal models.Goal) bool {
+	for _, h := range goal.GoalDetails {
+		if strings.TrimSpace(h.RedemptionPercent) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// percentRedemption splits a redemption given as a share of each holding rather than as an
+// order amount. A holding with RedemptionPercent p sells p of its value after pending sells,
+// truncated to amountPrec; holdings without one are not sold. A p of 1 closes the position,
+// selling every unit held, and is exempt from the minimum holding; any other sell is
+// converted to units at its model item's price when it has a positive model weight, and at
+// the holding's otherwise. Each sell is checked against
+// its minimums and flagged, not dropped, when it breaches one. Blocked units are never
+// sold: p applies to the free part of the holding, and a p of 1 sells all of it.
+//
+// The total sold is reported as effectiveOrderAmount and classifies the redemption.
+func percentRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	prec := int32(amountPrec)
+	modelMap := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		modelMap[mp.Ticker] = mp
+	}
+
+	vTotal, total := decimal.Zero, decimal.Zero
+	var details []models.TransactionDetail
+	for _, h := range sellableHoldings(goal.GoalDetails) {
+		value, _ := decimal.NewFromString(h.Value)
+		if value.IsPositive() {
+			vTotal = vTotal.Add(value)
+		}
+		pct, err := decimal.NewFromString(strings.TrimSpace(h.RedemptionPercent))
+		if err != nil || !pct.IsPositive() {
+			continue
+		}
+
+		free, freeUnits := freeHolding(h)
+		full := pct.GreaterThanOrEqual(decimal.NewFromInt(1))
+		redeemAmt := free.Truncate(prec)
+		units := freeUnits
+		if !full {
+			redeemAmt = free.Mul(pct).Truncate(prec)
+			price, _ := decimal.NewFromString(h.MarketPrice)
+			mp, inModel := modelMap[h.Ticker]
+			if w, _ := decimal.NewFromString(mp.Weight); inModel && w.IsPositive() {
+				price, _ = decimal.NewFromString(mp.MarketPrice)
+			}
+			units = decimal.Zero
+			if price.IsPositive() {
+				units = div(redeemAmt, price).Truncate(int32(unitPrec))
+			}
+		}
+
+		minimums := models.ModelItem{
+			MinRedemptionAmt: h.MinRedemptionAmt, MinRedemptionUnits: h.MinRedemptionUnits,
+			MinHoldingAmt: h.MinHoldingAmt, MinHoldingUnits: h.MinHoldingUnits,
+		}
+		if mp, inModel := modelMap[h.Ticker]; inModel {
+			minimums = mp
+		}
+		var tradeErrs []models.TradeError
+		if redeemAmt.IsPositive() {
+			tradeErrs = checkRedemptionMinimums(
+				redeemAmt, units,
+				full,
+				h.Value, h.Units,
+				minimums.MinRedemptionAmt, minimums.MinRedemptionUnits,
+				minimums.MinHoldingAmt, minimums.MinHoldingUnits,
+				amountPrec, unitPrec,
+			)
+		}
+
+		total = total.Add(redeemAmt)
+		details = append(details, withErrors(models.TransactionDetail{
+			Ticker:         h.Ticker,
+			Direction:      "SELL",
+			Value:          redeemAmt.StringFixed(prec),
+			Units:          units.StringFixed(int32(unitPrec)),
+			FullRedemption: full && !isBlocked(h),
+		}, tradeErrs...))
+	}
+
+	result := models.GoalResult{
+		GoalID:               goal.GoalID,
+		TransactionType:      redemptionType(total, vTotal, opts.VolatilityBuffer),
+		TransactionDetails:   details,
+		TotalPortfolioValue:  vTotal.StringFixed(prec),
+		EffectiveOrderAmount: total.StringFixed(prec),
+	}
+	return realizedGains(goal, withholdingTax(goal, result, amountPrec, opts), amountPrec, unitPrec, opts)
+}
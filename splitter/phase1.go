@@ -0,0 +1,23 @@
+package splitter
+
+import (
+	"strings"
+)
+
+// Phase 1 orders accepted in Options.Phase1Order. They set the order in which a redemption
+// sells its zero-weight and absent products, and so which of them is left partially sold
+// when the budget runs out mid-list.
+const (
+	Phase1SmallestFirst = "smallest_first" // ascending value, most positions fully closed (default)
+	Phase1LargestFirst  = "largest_first"  // descending value, fewest partial positions left
+	Phase1ModelOrder    = "model_order"    // modelPortfolioDetails order, then absent products in goalDetails order
+)
+
+// ValidPhase1Order reports whether name is an accepted Phase 1 order (empty means default).
+func ValidPhase1Order(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", Phase1SmallestFirst, Phase1LargestFirst, Phase1ModelOrder:
+		return true
+	}
+	return false
+}
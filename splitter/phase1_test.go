@@ -0,0 +1,52 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestPhase1OrderPartiallySellsLastProduct(t *testing.T) {
+	// 550 is redeemed from three zero-weight positions worth 900 between them: Z, a model
+	// item of weight 0, and X and Y, which are not in the model. Each ordering closes the
+	// positions it reaches first and sells the rest of the budget from the next one.
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithOrderAmount("550").
+		WithTicker("Z", "0", "1").
+		WithTicker("A", "1", "1").
+		WithHolding("A", "10000", "1").
+		WithHolding("X", "500", "1").
+		WithHolding("Y", "100", "1").
+		WithHolding("Z", "300", "1").
+		Build()
+	type sell struct {
+		value string
+		units string
+		full  bool
+	}
+	tests := []struct {
+		order string
+		want  map[string]sell
+	}{
+		{"", map[string]sell{"Y": {"100.00", "100.0000", true}, "Z": {"300.00", "300.0000", true}, "X": {"150.00", "150.0000", false}}},
+		{Phase1SmallestFirst, map[string]sell{"Y": {"100.00", "100.0000", true}, "Z": {"300.00", "300.0000", true}, "X": {"150.00", "150.0000", false}}},
+		{Phase1LargestFirst, map[string]sell{"X": {"500.00", "500.0000", true}, "Z": {"50.00", "50.0000", false}}},
+		{Phase1ModelOrder, map[string]sell{"Z": {"300.00", "300.0000", true}, "X": {"250.00", "250.0000", false}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			got := byTicker(ProcessRedemption(goal, 2, 4, Options{Phase1Order: tt.order}))
+			for _, ticker := range []string{"A", "X", "Y", "Z"} {
+				want, sold := tt.want[ticker]
+				d, ok := got[ticker]
+				switch {
+				case !sold && ok && d.Value != "0.00":
+					t.Errorf("%s sold %s, want it untouched", ticker, d.Value)
+				case sold && (d.Value != want.value || d.Units != want.units || d.FullRedemption != want.full):
+					t.Errorf("%s = %s (%s units, full %v), want %s (%s units, full %v)",
+						ticker, d.Value, d.Units, d.FullRedemption, want.value, want.units, want.full)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// PostTrade returns result with PostTradeHoldings and PostTradeTotal set: the goal as it
+// would stand once its pending orders settle and result's trades are placed, per
+// projectTrades. Weights are each value's share of the total at weightDisplayPrec, with the
+// rounding residual given to the largest so that they sum to exactly 1; they are all 0 when
+// nothing is left.
+func PostTrade(goal models.Goal, result models.GoalResult, amountPrec, unitPrec int) models.GoalResult {
+	positions := projectTrades(goal, result, amountPrec, unitPrec)
+	total := decimal.Zero
+	for _, p := range positions {
+		total = total.Add(p.value)
+	}
+	weights := make([]decimal.Decimal, len(positions))
+	if total.IsPositive() {
+		sum, largest := decimal.Zero, 0
+		for i, p := range positions {
+			weights[i] = div(p.value, total).Round(weightDisplayPrec)
+			sum = sum.Add(weights[i])
+			if p.value.GreaterThan(positions[largest].value) {
+				largest = i
+			}
+		}
+		weights[largest] = weights[largest].Add(decimal.NewFromInt(1).Sub(sum))
+	}
+
+	out := make([]models.PostTradeHolding, len(positions))
+	for i, p := range positions {
+		out[i] = models.PostTradeHolding{
+			Ticker: p.ticker,
+			Value:  p.value.StringFixed(int32(amountPrec)),
+			Units:  p.units.StringFixed(int32(unitPrec)),
+			Weight: weights[i].StringFixed(weightDisplayPrec),
+		}
+		if p.cash || p.unknownUnits {
+			out[i].Units = ""
+		}
+	}
+	result.PostTradeHoldings = out
+	result.PostTradeTotal = total.StringFixed(int32(amountPrec))
+	return result
+}
+
+// projectedPosition is one product of a goal, or its cash, before and after its trades.
+type projectedPosition struct {
+	ticker       string
+	cash         bool
+	preValue     decimal.Decimal // once pending orders settle, before the trades
+	value, units decimal.Decimal // after the trades
+	unknownUnits bool
+}
+
+// projectTrades returns every model item and holding of goal, in model order and then
+// holding order, traded or not, with any cash balance last under CashTicker. Each starts
+// from its value and units once pending orders settle. A buy adds its gross value less the
+// product's transactionFee, and the units GetExpectedUnits gives for it; a sell takes off
+// its gross value and units; a cash drawdown takes off the cash. Values are truncated to
+// amountPrec and units to unitPrec, and floored at 0. A sell placed by value without units
+// leaves the product's units unknown.
+func projectTrades(goal models.Goal, result models.GoalResult, amountPrec, unitPrec int) []*projectedPosition {
+	var positions []*projectedPosition
+	byTicker := make(map[string]*projectedPosition)
+	add := func(ticker string) *projectedPosition {
+		if p, ok := byTicker[ticker]; ok {
+			return p
+		}
+		p := &projectedPosition{ticker: ticker}
+		positions = append(positions, p)
+		byTicker[ticker] = p
+		return p
+	}
+
+	modelMap := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		modelMap[mp.Ticker] = mp
+		add(mp.Ticker)
+	}
+	for _, h := range goal.GoalDetails {
+		p := add(h.Ticker)
+		p.value = p.value.Add(projectedValue(h))
+		p.units = p.units.Add(projectedUnits(h))
+	}
+	var cash *projectedPosition
+	if c := cashBalance(goal); c.IsPositive() {
+		cash = &projectedPosition{ticker: CashTicker, cash: true, value: c}
+	}
+	for _, p := range positions {
+		p.preValue = p.value
+	}
+	if cash != nil {
+		cash.preValue = cash.value
+	}
+
+	for _, d := range result.TransactionDetails {
+		value, _ := decimal.NewFromString(d.Value)
+		switch d.Direction {
+		case "BUY":
+			p, mp := add(d.Ticker), modelMap[d.Ticker]
+			fee, _ := decimal.NewFromString(mp.TransactionFee)
+			p.value = p.value.Add(value.Mul(decimal.NewFromInt(1).Sub(fee)))
+			p.units = p.units.Add(GetExpectedUnits(d.Ticker, value, mp, unitPrec))
+		case "SELL":
+			p := add(d.Ticker)
+			p.value = p.value.Sub(value)
+			if units, err := decimal.NewFromString(d.Units); err == nil {
+				p.units = p.units.Sub(units)
+			} else if value.IsPositive() {
+				p.unknownUnits = true
+			}
+		case DirCashOut:
+			if cash != nil {
+				cash.value = cash.value.Sub(value)
+			}
+		}
+	}
+	if cash != nil {
+		positions = append(positions, cash)
+	}
+
+	for _, p := range positions {
+		p.value = decimal.Max(p.value, decimal.Zero).Truncate(int32(amountPrec))
+		p.units = decimal.Max(p.units, decimal.Zero).Truncate(int32(unitPrec))
+	}
+	return positions
+}
@@ -0,0 +1,17 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// DivisionPrecision is the number of decimal places kept by intermediate divisions: weight
+// ratios, fee gross-ups and unit conversions. Amounts and units are still truncated to the
+// request's amountDecimalPrecision and unitDecimalPrecision when they are output. Raise it
+// for very large portfolios, where ratio rounding can otherwise leave a visible residual.
+// It is set from DIVISION_PRECISION at startup; the default matches decimal.DivisionPrecision.
+var DivisionPrecision = 16
+
+// div returns a / b rounded to DivisionPrecision decimal places.
+func div(a, b decimal.Decimal) decimal.Decimal {
+	return a.DivRound(b, int32(DivisionPrecision))
+}
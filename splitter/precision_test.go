@@ -0,0 +1,52 @@
+package splitter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// TestDivisionPrecisionOnLargeTotals checks that, for nine-digit totals, no division
+// precision leaves more than one smallest unit of the order unallocated or oversold.
+func TestDivisionPrecisionOnLargeTotals(t *testing.T) {
+	defer func(p int) { DivisionPrecision = p }(DivisionPrecision)
+
+	goal := func(orderType string) models.Goal {
+		return testhelpers.NewGoalBuilder("g1").WithOrderType(orderType).
+			WithOrderAmount("123456789.17").
+			WithTicker("A", "0.333333", "7.13").
+			WithTicker("B", "0.333333", "311.99").
+			WithTicker("C", "0.333334", "0.97").
+			WithHolding("A", "42075736.2104", "7.13").
+			WithHolding("B", "961569.2586", "311.99").
+			WithHolding("C", "309278350.5155", "0.97").
+			Build()
+	}
+	unit := decimal.New(1, -2)
+	for _, prec := range []int{6, 8, 12, 16, 24} {
+		DivisionPrecision = prec
+		for _, orderType := range []string{"Investment", "Redemption"} {
+			t.Run(fmt.Sprintf("%s/%d", orderType, prec), func(t *testing.T) {
+				g := goal(orderType)
+				var result models.GoalResult
+				if orderType == "Investment" {
+					result = ProcessInvestment(g, 2, 4, Options{})
+				} else {
+					result = ProcessRedemption(g, 2, 4, Options{})
+				}
+				order, _ := decimal.NewFromString(g.OrderAmount)
+				if residual := order.Sub(sumValues(result)).Abs(); residual.GreaterThan(unit) {
+					t.Errorf("Σ values %s is %s from the order %s, more than %s", sumValues(result), residual, order, unit)
+				}
+				for _, d := range result.TransactionDetails {
+					if d.Error != nil {
+						t.Errorf("%s flagged %s: %s", d.Ticker, d.Error.Code, d.Error.Message)
+					}
+				}
+			})
+		}
+	}
+}
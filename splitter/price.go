@@ -0,0 +1,56 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// unitPrice returns the marketPrice the units of a trade in direction are derived from:
+// the model item's for a buy, and for a sell the model item's when the product has a
+// positive model weight and the holding's otherwise.
+func unitPrice(direction string, mp models.ModelItem, inModel bool, h models.Holding) string {
+	if direction != "SELL" {
+		return mp.MarketPrice
+	}
+	if w, _ := decimal.NewFromString(mp.Weight); inModel && w.IsPositive() {
+		return mp.MarketPrice
+	}
+	return h.MarketPrice
+}
+
+// EchoOrder returns result with the goal's clientReference, modelPortfolioId, orderType
+// and orderAmount echoed as sent, so that a result archived apart from its request still
+// says what it was split for. The amount actually split, when it differs, is
+// EffectiveOrderAmount.
+func EchoOrder(goal models.Goal, result models.GoalResult) models.GoalResult {
+	result.ClientReference = goal.ClientReference
+	result.ModelPortfolioID = goal.ModelPortfolioID
+	result.OrderType = goal.OrderType
+	result.OrderAmount = goal.OrderAmount
+	return result
+}
+
+// EchoPrices returns result with Price set on every trade to the marketPrice string its
+// units were derived from, per unitPrice. A sell of the whole holding reports every unit
+// held rather than deriving them, but still echoes the price it would have used. Cash
+// drawdowns have no price.
+func EchoPrices(goal models.Goal, result models.GoalResult) models.GoalResult {
+	model := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		model[mp.Ticker] = mp
+	}
+	held := make(map[string]models.Holding)
+	for _, h := range goal.GoalDetails {
+		held[h.Ticker] = h
+	}
+	details := make([]models.TransactionDetail, len(result.TransactionDetails))
+	for i, d := range result.TransactionDetails {
+		if d.Direction != DirCashOut {
+			mp, inModel := model[d.Ticker]
+			d.Price = unitPrice(d.Direction, mp, inModel, held[d.Ticker])
+		}
+		details[i] = d
+	}
+	result.TransactionDetails = details
+	return result
+}
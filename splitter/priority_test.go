@@ -0,0 +1,81 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		goal    models.Goal
+		want    map[string]string
+		flagged string
+	}{
+		{
+			// An investment of 1000 into a goal holding 1000 of the overweight Z, so that
+			// every shortfall is scaled down and A and B each fall 25 short of their 150
+			// minimum. C, which cannot be zeroed, has 30 of safe slack: enough to fix one of
+			// them, not both.
+			name: "equal priority keeps model order",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.1").
+					WithMinInitialInvestment("150", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.1").
+					WithMinInitialInvestment("150", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("C").WithWeight("0.6").
+					WithMinInitialInvestment("720", "0").ProtectFromZeroOut().Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("Z").WithWeight("0.2").Build()).
+				WithHolding("Z", "1000", "1").
+				Build(),
+			want:    map[string]string{"A": "150.00", "B": "125.00", "C": "725.00"},
+			flagged: "B",
+		},
+		{
+			name: "higher priority is fixed first",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.1").
+					WithMinInitialInvestment("150", "0").WithPriority("0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.1").
+					WithMinInitialInvestment("150", "0").WithPriority("1").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("C").WithWeight("0.6").
+					WithMinInitialInvestment("720", "0").ProtectFromZeroOut().Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("Z").WithWeight("0.2").Build()).
+				WithHolding("Z", "1000", "1").
+				Build(),
+			want:    map[string]string{"A": "125.00", "B": "150.00", "C": "725.00"},
+			flagged: "A",
+		},
+		{
+			// A's minimum is above its model-weight cap of 200, so no priority can get it
+			// fixed, and C is never taken below its own minimum to fund it.
+			name: "never overrides minimums or cap",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.1").
+					WithMinInitialInvestment("250", "0").WithPriority("9").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("C").WithWeight("0.7").
+					WithMinInitialInvestment("700", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("Z").WithWeight("0.2").Build()).
+				WithHolding("Z", "1000", "1").
+				Build(),
+			want:    map[string]string{"A": "125.00", "C": "875.00"},
+			flagged: "A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := byTicker(ProcessInvestment(tt.goal, 2, 4, Options{}))
+			assertValues(t, got, tt.want)
+			for ticker := range tt.want {
+				code := errorCode(got[ticker])
+				if ticker == tt.flagged && code != models.CodeMinInvestmentViolation {
+					t.Errorf("%s flagged %q, want %s", ticker, code, models.CodeMinInvestmentViolation)
+				} else if ticker != tt.flagged && code != "" {
+					t.Errorf("%s flagged %s, want it fixed", ticker, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Proceeds modes accepted in Options.ProceedsMode.
+const (
+	// ProceedsGross sizes redemption sells so that their gross values sum to the order
+	// amount; transaction fees are ignored. This is the default.
+	ProceedsGross = "gross"
+	// ProceedsNet sizes redemption sells so that their proceeds after the transaction fee,
+	// gross × (1 − fee), sum to the order amount.
+	ProceedsNet = "net"
+	// ProceedsNetAfterTax sizes redemption sells so that their proceeds after the fee and
+	// withholding tax, gross × (1 − fee) × (1 − withholdingTaxRate), sum to the order amount.
+	ProceedsNetAfterTax = "net_after_tax"
+)
+
+// Minimum bases accepted in Options.RedemptionMinimumBasis.
+const (
+	// MinimumBasisGross checks minRedemptionAmt against the gross sell. This is the default.
+	MinimumBasisGross = "gross"
+	// MinimumBasisNet checks minRedemptionAmt against the sell's proceeds after the fee.
+	MinimumBasisNet = "net"
+)
+
+// ValidProceedsMode reports whether mode is empty or a known proceeds mode.
+func ValidProceedsMode(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", ProceedsGross, ProceedsNet, ProceedsNetAfterTax:
+		return true
+	}
+	return false
+}
+
+// ValidMinimumBasis reports whether basis is empty or a known minimum basis.
+func ValidMinimumBasis(basis string) bool {
+	switch strings.ToLower(strings.TrimSpace(basis)) {
+	case "", MinimumBasisGross, MinimumBasisNet:
+		return true
+	}
+	return false
+}
+
+// redemptionFees returns the redemption fee of every product in goal, keyed by ticker:
+// the model item's fee for model products and the holding's fee for the rest, the same
+// precedence as the redemption minimums.
+func redemptionFees(goal models.Goal) map[string]decimal.Decimal {
+	fees := make(map[string]decimal.Decimal)
+	for _, h := range goal.GoalDetails {
+		fees[h.Ticker], _ = decimal.NewFromString(redemptionFee(h.RedemptionFee, h.TransactionFee))
+	}
+	for _, mp := range goal.ModelPortfolioDetails {
+		fees[mp.Ticker], _ = decimal.NewFromString(redemptionFee(mp.RedemptionFee, mp.TransactionFee))
+	}
+	return fees
+}
+
+// withholdingTaxRates returns the withholding tax rate of every product in goal, keyed by
+// ticker, with the same precedence as redemptionFees.
+func withholdingTaxRates(goal models.Goal) map[string]decimal.Decimal {
+	rates := make(map[string]decimal.Decimal)
+	for _, h := range goal.GoalDetails {
+		rates[h.Ticker], _ = decimal.NewFromString(h.WithholdingTaxRate)
+	}
+	for _, mp := range goal.ModelPortfolioDetails {
+		rates[mp.Ticker], _ = decimal.NewFromString(mp.WithholdingTaxRate)
+	}
+	return rates
+}
+
+// proceedsShares returns, for every product in goal, the share of each gross amount sold
+// that opts.ProceedsMode sizes sells on: 1 − fee under ProceedsNet, (1 − fee) × (1 − tax)
+// under ProceedsNetAfterTax and 1 otherwise.
+func proceedsShares(goal models.Goal, opts Options) map[string]decimal.Decimal {
+	fees, rates := redemptionFees(goal), withholdingTaxRates(goal)
+	one := decimal.NewFromInt(1)
+	shares := make(map[string]decimal.Decimal, len(fees))
+	for ticker, fee := range fees {
+		shares[ticker] = one
+		switch opts.ProceedsMode {
+		case ProceedsNet:
+			shares[ticker] = one.Sub(fee)
+		case ProceedsNetAfterTax:
+			shares[ticker] = one.Sub(fee).Mul(one.Sub(rates[ticker]))
+		}
+	}
+	return shares
+}
+
+// redemptionFee returns the rate charged on a sell: redemptionFee when set, otherwise the
+// product's transactionFee, so requests that predate redemptionFee keep their behaviour.
+func redemptionFee(redemptionFee, transactionFee string) string {
+	if strings.TrimSpace(redemptionFee) != "" {
+		return redemptionFee
+	}
+	return transactionFee
+}
+
+// feeAdjustedGoal returns a copy of goal in which the splitter's usual gross arithmetic
+// produces the figures selected by opts.ProceedsMode and opts.RedemptionMinimumBasis.
+//
+// With ProceedsNet every amount is restated as proceeds: values, pending values and market
+// prices are scaled by (1 − fee), so a sell of n in the copy is a gross sell of n / (1 − fee)
+// of the original, of the same units. minHoldingAmt is scaled too, since what remains
+// behind is scaled with the value. ProceedsNetAfterTax does the same with the share
+// (1 − fee) × (1 − tax) left after withholding tax.
+//
+// minRedemptionAmt is stated on the gross sell under MinimumBasisGross and on its proceeds
+// after the fee under MinimumBasisNet. It is restated onto whichever figure the split works
+// in, e.g. to minRedemptionAmt / (1 − fee) with ProceedsGross and MinimumBasisNet, the gross
+// sell whose proceeds reach it.
+//
+// Restated minimums are rounded up to amountPrec.
+func feeAdjustedGoal(goal models.Goal, opts Options, amountPrec int) models.Goal {
+	net := opts.ProceedsMode == ProceedsNet || opts.ProceedsMode == ProceedsNetAfterTax
+	netBasis := opts.RedemptionMinimumBasis == MinimumBasisNet
+	if !net && !netBasis {
+		return goal
+	}
+	fees, shares := redemptionFees(goal), proceedsShares(goal, opts)
+	prec := int32(amountPrec)
+	one := decimal.NewFromInt(1)
+	scale := func(s string, factor decimal.Decimal, round bool) string {
+		d, err := decimal.NewFromString(strings.TrimSpace(s))
+		if err != nil || d.IsZero() {
+			return s
+		}
+		d = d.Mul(factor)
+		if round {
+			d = ceilToPrec(d, prec)
+		}
+		return d.String()
+	}
+	// minRedemption restates the minimum redemption amount of ticker from its basis onto
+	// the share of every gross unit sold that the split works in.
+	minRedemption := func(s, ticker string) string {
+		basis := one
+		if netBasis {
+			basis = one.Sub(fees[ticker])
+		}
+		ratio := div(shares[ticker], basis)
+		if ratio.Equal(one) {
+			return s
+		}
+		return scale(s, ratio, true)
+	}
+
+	holdings := make([]models.Holding, len(goal.GoalDetails))
+	for i, h := range goal.GoalDetails {
+		keep := shares[h.Ticker]
+		h.MinRedemptionAmt = minRedemption(h.MinRedemptionAmt, h.Ticker)
+		if net {
+			h.Value = scale(h.Value, keep, false)
+			h.PendingValue = scale(h.PendingValue, keep, false)
+			h.MarketPrice = scale(h.MarketPrice, keep, false)
+			h.MinHoldingAmt = scale(h.MinHoldingAmt, keep, true)
+		}
+		holdings[i] = h
+	}
+	items := make([]models.ModelItem, len(goal.ModelPortfolioDetails))
+	for i, mp := range goal.ModelPortfolioDetails {
+		keep := shares[mp.Ticker]
+		mp.MinRedemptionAmt = minRedemption(mp.MinRedemptionAmt, mp.Ticker)
+		if net {
+			mp.MarketPrice = scale(mp.MarketPrice, keep, false)
+			mp.MinHoldingAmt = scale(mp.MinHoldingAmt, keep, true)
+		}
+		items[i] = mp
+	}
+	goal.GoalDetails, goal.ModelPortfolioDetails = holdings, items
+	return goal
+}
+
+// netProceedsRedemption splits a redemption with opts.ProceedsMode ProceedsNet or
+// ProceedsNetAfterTax. The goal is split in proceeds (see feeAdjustedGoal), and each sell of
+// n is then reported as the gross value n / share, rounded up to amountPrec so that its
+// proceeds are never less than n, with n as its NetProceeds or NetAfterTax. A full
+// redemption sells the holding's exact value.
+func netProceedsRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	netGoal := feeAdjustedGoal(goal, opts, amountPrec)
+	splitOpts := opts
+	splitOpts.ProceedsMode, splitOpts.RedemptionMinimumBasis = "", ""
+	result := ProcessRedemption(netGoal, amountPrec, unitPrec, splitOpts)
+
+	prec := int32(amountPrec)
+	shares := proceedsShares(goal, opts)
+	values := make(map[string]decimal.Decimal)
+	vTotal := decimal.Zero
+	for _, h := range sellableHoldings(goal.GoalDetails) {
+		v, _ := decimal.NewFromString(h.Value)
+		values[h.Ticker] = v
+		if v.IsPositive() {
+			vTotal = vTotal.Add(v)
+		}
+	}
+	for i, d := range result.TransactionDetails {
+		n, _ := decimal.NewFromString(d.Value)
+		gross := decimal.Min(ceilToPrec(div(n, shares[d.Ticker]), prec), values[d.Ticker].Truncate(prec))
+		if d.FullRedemption {
+			gross = values[d.Ticker].Truncate(prec)
+		}
+		if opts.ProceedsMode == ProceedsNetAfterTax {
+			d.NetAfterTax = d.Value
+		} else {
+			d.NetProceeds = d.Value
+		}
+		d.Value = gross.StringFixed(prec)
+		if d.Trace != nil {
+			d.Trace.Final = d.Value
+		}
+		result.TransactionDetails[i] = d
+	}
+	result.TotalPortfolioValue = vTotal.StringFixed(prec)
+	return realizedGains(goal, withholdingTax(goal, result, amountPrec, opts), amountPrec, unitPrec, opts)
+}
+
+// withholdingTax estimates the tax withheld on every sell in result when any product in
+// goal has a withholdingTaxRate, or when opts.ProceedsMode is ProceedsNetAfterTax. The tax
+// on a sell is its proceeds after the fee (NetProceeds when set, else value × (1 − fee))
+// times the rate, rounded up to amountPrec; NetAfterTax is what is left, rounded down,
+// unless the sell was sized on it. The goal carries the totals of both.
+func withholdingTax(goal models.Goal, result models.GoalResult, amountPrec int, opts Options) models.GoalResult {
+	taxed := opts.ProceedsMode == ProceedsNetAfterTax
+	for _, h := range goal.GoalDetails {
+		taxed = taxed || strings.TrimSpace(h.WithholdingTaxRate) != ""
+	}
+	for _, mp := range goal.ModelPortfolioDetails {
+		taxed = taxed || strings.TrimSpace(mp.WithholdingTaxRate) != ""
+	}
+	if !taxed {
+		return result
+	}
+
+	prec := int32(amountPrec)
+	one := decimal.NewFromInt(1)
+	fees, rates := redemptionFees(goal), withholdingTaxRates(goal)
+	totalTax, totalNet := decimal.Zero, decimal.Zero
+	for i, d := range result.TransactionDetails {
+		if d.Direction != "SELL" {
+			continue
+		}
+		proceeds, err := decimal.NewFromString(d.NetProceeds)
+		if err != nil {
+			v, _ := decimal.NewFromString(d.Value)
+			proceeds = v.Mul(one.Sub(fees[d.Ticker]))
+		}
+		tax := ceilToPrec(proceeds.Mul(rates[d.Ticker]), prec)
+		net := proceeds.Sub(tax).Truncate(prec)
+		if opts.ProceedsMode == ProceedsNetAfterTax {
+			net, _ = decimal.NewFromString(d.NetAfterTax)
+		}
+		d.EstimatedTax, d.NetAfterTax = tax.StringFixed(prec), net.StringFixed(prec)
+		totalTax, totalNet = totalTax.Add(tax), totalNet.Add(net)
+		result.TransactionDetails[i] = d
+	}
+	result.EstimatedTax, result.NetAfterTax = totalTax.StringFixed(prec), totalNet.StringFixed(prec)
+	return result
+}
@@ -0,0 +1,71 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestZeroOutProtection(t *testing.T) {
+	// As in TestTieBreak, V falls 50 short of its minimum and M and K
+	// are the only zero-out candidates.
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithModelItem(testhelpers.NewModelItemBuilder("V").WithWeight("0.1").
+			WithMinInitialInvestment("150", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("M").WithWeight("0.2").
+			WithMinInitialInvestment("200", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("K").WithWeight("0.2").
+			WithMinInitialInvestment("200", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("W").WithWeight("0.5").
+			WithMinInitialInvestment("500", "0").ProtectFromZeroOut().Build()).
+		WithHolding("Z", "1000", "1")
+	unfixed := map[string]string{"V": "100.00", "M": "200.00", "K": "200.00", "W": "500.00"}
+	tests := []struct {
+		name    string
+		goal    models.Goal
+		opts    Options
+		want    map[string]string
+		flagged models.Code
+	}{
+		{
+			name: "protectFromZeroOut on every candidate",
+			goal: testhelpers.NewGoalBuilder("g1").
+				WithModelItem(testhelpers.NewModelItemBuilder("V").WithWeight("0.1").
+					WithMinInitialInvestment("150", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("M").WithWeight("0.2").
+					WithMinInitialInvestment("200", "0").ProtectFromZeroOut().Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("K").WithWeight("0.2").
+					WithMinInitialInvestment("200", "0").ProtectFromZeroOut().Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("W").WithWeight("0.5").
+					WithMinInitialInvestment("500", "0").ProtectFromZeroOut().Build()).
+				WithHolding("Z", "1000", "1").
+				Build(),
+			want:    unfixed,
+			flagged: models.CodeMinInvestmentViolation,
+		},
+		{
+			name:    "zeroOutMaxWeight below every candidate",
+			goal:    goal.Build(),
+			opts:    Options{ZeroOutMaxWeight: "0.15"},
+			want:    unfixed,
+			flagged: models.CodeMinInvestmentViolation,
+		},
+		{
+			// At 0.2 neither M nor K is above the threshold, so one of them is still zeroed.
+			name: "zeroOutMaxWeight only protects heavier products",
+			goal: goal.Build(),
+			opts: Options{ZeroOutMaxWeight: "0.2"},
+			want: map[string]string{"M": "0.00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := byTicker(ProcessInvestment(tt.goal, 2, 4, tt.opts))
+			assertValues(t, got, tt.want)
+			if code := errorCode(got["V"]); code != tt.flagged {
+				t.Errorf("V flagged %q, want %q", code, tt.flagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// rebuildBigRedemption splits a redemption with opts.RebuildBigRedemption. Outside the
+// Big Redemption band it is the usual split. Inside it, the goal is split in two legs:
+//
+//   - SELL: a redemption of orderAmount × (1 + volatilityBuffer), at most the goal value,
+//     so that the withdrawal is covered even if prices move against it.
+//   - BUY: an investment of whatever the sells raised beyond orderAmount, into the holdings
+//     left after the sells, so that the residual portfolio is rebuilt to model.
+//
+// Each leg runs its usual minimum checks. The net of the two legs is the withdrawal.
+func rebuildBigRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	opts.RebuildBigRedemption = false
+	plain := ProcessRedemption(goal, amountPrec, unitPrec, opts)
+	if plain.TransactionType != "Big Redemption" {
+		return plain
+	}
+
+	prec := int32(amountPrec)
+	orderAmount, _ := decimal.NewFromString(goal.OrderAmount)
+	if plain.EffectiveOrderAmount != "" {
+		orderAmount, _ = decimal.NewFromString(plain.EffectiveOrderAmount)
+	}
+	vTotal, _ := decimal.NewFromString(plain.TotalPortfolioValue)
+	buf, _ := decimal.NewFromString(opts.VolatilityBuffer)
+	gross := decimal.Min(orderAmount.Mul(decimal.NewFromInt(1).Add(buf)).Truncate(prec), vTotal)
+
+	// The cap and unit conversion already shaped orderAmount; the sell leg is sized from it.
+	sellGoal := goal
+	sellGoal.OrderAmount, sellGoal.OrderUnit, sellGoal.MaxRedemptionPct = gross.StringFixed(prec), "", ""
+	result := ProcessRedemption(sellGoal, amountPrec, unitPrec, opts)
+	result.TransactionType = plain.TransactionType
+	result.EffectiveOrderAmount = plain.EffectiveOrderAmount
+	result.UnfulfilledAmount = ""
+
+	sold := decimal.Zero
+	for _, d := range result.TransactionDetails {
+		v, _ := decimal.NewFromString(d.Value)
+		sold = sold.Add(v)
+	}
+	reinvest := sold.Sub(orderAmount)
+	if !reinvest.IsPositive() {
+		if reinvest.IsNegative() {
+			result.UnfulfilledAmount = reinvest.Neg().StringFixed(prec)
+		}
+		return result
+	}
+
+	buyGoal := goal
+	buyGoal.OrderType = "Investment"
+	buyGoal.OrderAmount, buyGoal.OrderUnit = reinvest.StringFixed(prec), ""
+	buyGoal.GoalDetails = holdingsAfterSells(goal.GoalDetails, result.TransactionDetails)
+	buyOpts := opts
+	buyOpts.WhatIfAmounts, buyOpts.IncludeEffectiveWeights = nil, false
+	buyOpts.IgnoreCurrentHoldings = false
+	buys := ProcessInvestment(buyGoal, amountPrec, unitPrec, buyOpts)
+	result.TransactionDetails = append(result.TransactionDetails, buys.TransactionDetails...)
+	if buys.Debug != nil {
+		result.Debug = buys.Debug
+	}
+	return result
+}
+
+// holdingsAfterSells returns a copy of holdings with the value and units of every SELL in
+// details taken off the holding it sold.
+func holdingsAfterSells(holdings []models.Holding, details []models.TransactionDetail) []models.Holding {
+	soldValue := make(map[string]decimal.Decimal)
+	soldUnits := make(map[string]decimal.Decimal)
+	for _, d := range details {
+		if d.Direction != "SELL" {
+			continue
+		}
+		v, _ := decimal.NewFromString(d.Value)
+		u, _ := decimal.NewFromString(d.Units)
+		soldValue[d.Ticker] = soldValue[d.Ticker].Add(v)
+		soldUnits[d.Ticker] = soldUnits[d.Ticker].Add(u)
+	}
+	out := make([]models.Holding, len(holdings))
+	for i, h := range holdings {
+		v, _ := decimal.NewFromString(h.Value)
+		u, _ := decimal.NewFromString(h.Units)
+		h.Value = decimal.Max(v.Sub(soldValue[h.Ticker]), decimal.Zero).String()
+		h.Units = decimal.Max(u.Sub(soldUnits[h.Ticker]), decimal.Zero).String()
+		out[i] = h
+	}
+	return out
+}
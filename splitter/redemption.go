@@ -2,6 +2,7 @@ package splitter
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/shopspring/decimal"
 	"github.com/valentinpj/smart-splitter/models"
@@ -12,12 +13,46 @@ import (
 //
 // Two-phase approach:
 //   Phase 1 — Zero-weight / absent products are fully redeemed first (highest priority),
-//             sorted ascending by value to maximise the count of full redemptions within budget.
+//             sorted ascending by value to maximise the count of full redemptions within budget
+//             (see Options.Phase1Order).
 //   Phase 2 — Remaining budget is distributed across model-portfolio products proportionally
 //             to how overweight each one is relative to its post-redemption model target.
-func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuffer string) models.GoalResult {
+func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, opts Options) models.GoalResult {
+	opts.VolatilityBuffer = volatilityBuffer(goal, opts)
+	if locked := lockedTickers(goal, opts.today()); len(locked) > 0 {
+		return lockedRedemption(goal, locked, amountPrec, unitPrec, opts)
+	}
+	if goal.CashOut {
+		return cashOutRedemption(goal, amountPrec, unitPrec, opts)
+	}
+	if cashBalance(goal).IsPositive() {
+		return cashRedemption(goal, amountPrec, unitPrec, opts)
+	}
+	if opts.AllowMissingPrice {
+		if noPrice := noPriceTickers(goal); len(noPrice) > 0 {
+			return missingPriceRedemption(goal, noPrice, amountPrec, unitPrec, opts)
+		}
+	}
+	if HasRedemptionPercents(goal) {
+		return percentRedemption(goal, amountPrec, unitPrec, opts)
+	}
+	if small := smallPositions(goal, opts); len(small) > 0 {
+		return consolidatedRedemption(goal, small, amountPrec, unitPrec, opts)
+	}
+	if opts.RebuildBigRedemption {
+		return rebuildBigRedemption(goal, amountPrec, unitPrec, opts)
+	}
+	if opts.ProceedsMode == ProceedsNet || opts.ProceedsMode == ProceedsNetAfterTax {
+		return netProceedsRedemption(goal, amountPrec, unitPrec, opts)
+	}
+	sent := goal
+	// Minimums the custodian enforces on proceeds are restated as thresholds on the gross sell.
+	goal = feeAdjustedGoal(goal, opts, amountPrec)
 	orderAmount, _ := decimal.NewFromString(goal.OrderAmount)
 
+	// Units committed to pending sells cannot be sold again.
+	goal.GoalDetails = sellableHoldings(goal.GoalDetails)
+
 	// Build holdings map: ticker -> Holding (only products with positive value)
 	holdingsMap := make(map[string]models.Holding)
 	vTotal := decimal.Zero
@@ -29,10 +64,37 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 		}
 	}
 
-	// Build model map: ticker -> ModelItem
+	// An order in units is redeemed as its cash equivalent at the goal's average unit price.
+	// Setting orderUnit is what selects it; there is no separate mode flag that could
+	// disagree with the field.
+	unitOrder := strings.TrimSpace(goal.OrderUnit) != ""
+	if unitOrder {
+		orderAmount = unitOrderAmount(goal.OrderUnit, holdingsMap, vTotal, amountPrec)
+	}
+
+	// Regulatory cap: never redeem more than maxRedemptionPct of the goal value. The order is
+	// reduced silently and the amount actually split is reported on the result.
+	capPct, capErr := decimal.NewFromString(strings.TrimSpace(goal.MaxRedemptionPct))
+	if capErr == nil {
+		if limit := vTotal.Mul(capPct).Truncate(int32(amountPrec)); orderAmount.GreaterThan(limit) {
+			orderAmount = limit
+		}
+	}
+
+	// An order within fullRedemptionTolerance of the goal value is an order for all of it,
+	// unless a cap forbids redeeming all of it.
+	requested := orderAmount
+	if capErr != nil && withinFullRedemptionTolerance(orderAmount, vTotal, opts.FullRedemptionTolerance) {
+		orderAmount = vTotal
+	}
+
+	// Build model maps: ticker -> ModelItem and ticker -> parsed (optionally normalised) weight
 	modelMap := make(map[string]models.ModelItem)
-	for _, mp := range goal.ModelPortfolioDetails {
+	weightMap := make(map[string]decimal.Decimal)
+	weights := modelWeights(goal, opts)
+	for j, mp := range goal.ModelPortfolioDetails {
 		modelMap[mp.Ticker] = mp
+		weightMap[mp.Ticker] = weights[j]
 	}
 
 	// -------------------------------------------------------------------------
@@ -41,25 +103,41 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 	type zwProduct struct {
 		holding models.Holding
 		value   decimal.Decimal
+		rank    int // position in model order: model items first, then absent products
+	}
+	modelRank := make(map[string]int)
+	for j, mp := range goal.ModelPortfolioDetails {
+		modelRank[mp.Ticker] = j
 	}
 	var zwProducts []zwProduct
-	for _, h := range goal.GoalDetails { // iterate GoalDetails to preserve deterministic order
+	// Kept holdings are not sold; their value still counts in vTotal.
+	kept := decimal.Zero
+	for i, h := range goal.GoalDetails { // iterate GoalDetails to preserve deterministic order
 		val, _ := decimal.NewFromString(h.Value)
 		if !val.IsPositive() {
 			continue
 		}
-		mp, inModel := modelMap[h.Ticker]
-		w := decimal.Zero
-		if inModel {
-			w, _ = decimal.NewFromString(mp.Weight)
-		}
-		if w.IsZero() {
-			zwProducts = append(zwProducts, zwProduct{h, val})
+		if weightMap[h.Ticker].IsZero() && (h.Keep || goal.KeepUnmodeled) {
+			kept = kept.Add(val)
+		} else if weightMap[h.Ticker].IsZero() {
+			rank, inModel := modelRank[h.Ticker]
+			if !inModel {
+				rank = len(goal.ModelPortfolioDetails) + i
+			}
+			zwProducts = append(zwProducts, zwProduct{h, val, rank})
 		}
 	}
-	// Sort ascending by value so we maximise the number of fully-redeemed positions.
-	sort.Slice(zwProducts, func(i, j int) bool {
-		return zwProducts[i].value.LessThan(zwProducts[j].value)
+	// By default sort ascending by value so we maximise the number of fully-redeemed
+	// positions; largest_first instead leaves the fewest partial positions behind.
+	sort.SliceStable(zwProducts, func(i, j int) bool {
+		a, b := zwProducts[i], zwProducts[j]
+		switch opts.Phase1Order {
+		case Phase1LargestFirst:
+			return a.value.GreaterThan(b.value)
+		case Phase1ModelOrder:
+			return a.rank < b.rank
+		}
+		return a.value.LessThan(b.value)
 	})
 
 	remaining := orderAmount
@@ -69,8 +147,13 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 		if remaining.IsZero() {
 			break
 		}
-		isFullRedemption := !zp.value.GreaterThan(remaining)
-		redeemAmt := zp.value
+		// Blocked units cannot be sold; selling everything else is as far as the sell goes.
+		free, freeUnits := freeHolding(zp.holding)
+		if !free.IsPositive() {
+			continue
+		}
+		isFullRedemption := !free.GreaterThan(remaining)
+		redeemAmt := free
 		if !isFullRedemption {
 			redeemAmt = remaining
 		}
@@ -78,8 +161,12 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 
 		price, _ := decimal.NewFromString(zp.holding.MarketPrice)
 		var units decimal.Decimal
-		if price.IsPositive() {
-			units = redeemAmt.Div(price).Truncate(int32(unitPrec))
+		if isFullRedemption {
+			// A full redemption closes the position: every unit held, not the units its
+			// value converts to, which would leave dust behind.
+			units = freeUnits
+		} else if price.IsPositive() {
+			units = div(redeemAmt, price).Truncate(int32(unitPrec))
 		}
 
 		// Use modelPortfolioDetails fields in priority; fall back to goalDetails
@@ -95,7 +182,7 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 			minHoldingUnits = mp.MinHoldingUnits
 		}
 
-		tradeErr := checkRedemptionMinimums(
+		tradeErrs := checkRedemptionMinimums(
 			redeemAmt, units,
 			isFullRedemption,
 			zp.holding.Value, zp.holding.Units,
@@ -104,13 +191,17 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 			amountPrec, unitPrec,
 		)
 
-		details = append(details, models.TransactionDetail{
-			Ticker:    zp.holding.Ticker,
-			Direction: "SELL",
-			Value:     redeemAmt.StringFixed(int32(amountPrec)),
-			Units:     units.StringFixed(int32(unitPrec)),
-			Error:     tradeErr,
-		})
+		detail := withErrors(models.TransactionDetail{
+			Ticker:         zp.holding.Ticker,
+			Direction:      "SELL",
+			Value:          redeemAmt.StringFixed(int32(amountPrec)),
+			Units:          units.StringFixed(int32(unitPrec)),
+			FullRedemption: isFullRedemption && !isBlocked(zp.holding),
+		}, tradeErrs...)
+		if opts.Explain {
+			detail.Trace = redemptionTrace("1", zp.value, redeemAmt, amountPrec)
+		}
+		details = append(details, detail)
 		remaining = remaining.Sub(redeemAmt)
 	}
 
@@ -119,9 +210,10 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 	//
 	// ideal_i = max(0, V_i - w_i * (V_total - orderAmount))
 	// This naturally sums to exactly `remaining` (proved in design doc), so we
-	// can always scale to match the budget without a fallback.
+	// can always scale to match the budget without a fallback. Kept holdings are left out
+	// of V_total: the model weights apply to what remains besides them.
 	// -------------------------------------------------------------------------
-	postTotal := vTotal.Sub(orderAmount)
+	postTotal := vTotal.Sub(kept).Sub(orderAmount)
 
 	type productAlloc struct {
 		mp      models.ModelItem
@@ -132,8 +224,8 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 	var allocs []productAlloc
 	totalIdeal := decimal.Zero
 
-	for _, mp := range goal.ModelPortfolioDetails {
-		w, _ := decimal.NewFromString(mp.Weight)
+	for j, mp := range goal.ModelPortfolioDetails {
+		w := weights[j]
 		if w.IsZero() {
 			continue // already handled in Phase 1
 		}
@@ -152,46 +244,409 @@ func ProcessRedemption(goal models.Goal, amountPrec, unitPrec int, volatilityBuf
 		totalIdeal = totalIdeal.Add(ideal)
 	}
 
+	// held and heldUnits are the most that can be sold of each product: its holding value
+	// truncated to amountPrec, and its units truncated to unitPrec, less any blocked units.
+	held := make([]decimal.Decimal, len(allocs))
+	heldUnits := make([]decimal.Decimal, len(allocs))
+	for i, a := range allocs {
+		if a.holding != nil {
+			v, u := freeHolding(*a.holding)
+			held[i], heldUnits[i] = v.Truncate(int32(amountPrec)), u.Truncate(int32(unitPrec))
+		}
+	}
+
+	blocked := false
 	for _, a := range allocs {
-		redeemAmt := decimal.Zero
-		if !totalIdeal.IsZero() && remaining.IsPositive() {
-			redeemAmt = a.ideal.Div(totalIdeal).Mul(remaining).Truncate(int32(amountPrec))
+		blocked = blocked || (a.holding != nil && isBlocked(*a.holding))
+	}
+	dropped := make(map[int][]models.TradeError)
+
+	// scaleIdeals distributes the remaining budget pro-rata to the current ideals. A sell
+	// the scaling would take above its holding value is clamped to it and the rest of the
+	// budget is rescaled over the other products, until no sell exceeds its holding. The
+	// untruncated shares are kept in scaled.
+	//
+	// Blocked units can clamp every overweight product before the budget is spent. What is
+	// left then goes to the other products that are not dropped, pro-rata to what they can
+	// sell, by the same rules.
+	scaled := make([]decimal.Decimal, len(allocs))
+	scaleIdeals := func() []decimal.Decimal {
+		amts := make([]decimal.Decimal, len(allocs))
+		clamped := make([]bool, len(allocs))
+		budget, ideals := remaining, totalIdeal
+		share := func(i int) decimal.Decimal { return allocs[i].ideal }
+		spill := false
+		for {
+			for i := range allocs {
+				if !clamped[i] && !ideals.IsZero() && budget.IsPositive() {
+					scaled[i] = div(share(i), ideals).Mul(budget)
+					amts[i] = scaled[i].Truncate(int32(amountPrec))
+				}
+			}
+			done := true
+			for i := range allocs {
+				if !clamped[i] && amts[i].GreaterThan(held[i]) {
+					amts[i], scaled[i], clamped[i] = held[i], held[i], true
+					budget, ideals = budget.Sub(held[i]), ideals.Sub(share(i))
+					done = false
+				}
+			}
+			if done && blocked && !spill && ideals.IsZero() && budget.IsPositive() {
+				spill, share = true, func(i int) decimal.Decimal {
+					if clamped[i] || dropped[i] != nil {
+						return decimal.Zero
+					}
+					return held[i]
+				}
+				for i := range allocs {
+					ideals = ideals.Add(share(i))
+				}
+				done = ideals.IsZero()
+			}
+			if done {
+				return amts
+			}
 		}
+	}
+	redeemAmts := scaleIdeals()
+	// An order for the whole goal, kept holdings aside, sells every position in full.
+	// Scaling the ideals would only reproduce the holding values up to division error, so
+	// they are taken as they are.
+	liquidate := orderAmount.GreaterThanOrEqual(vTotal.Sub(kept))
+	if liquidate {
+		copy(redeemAmts, held)
+	}
 
-		price, _ := decimal.NewFromString(a.mp.MarketPrice)
-		var units decimal.Decimal
-		if price.IsPositive() && redeemAmt.IsPositive() {
-			units = redeemAmt.Div(price).Truncate(int32(unitPrec))
+	// Explain mode reports the overweight amounts from before the minimum trade check below.
+	var overweight []decimal.Decimal
+	if opts.Explain {
+		overweight = make([]decimal.Decimal, len(allocs))
+		for i, a := range allocs {
+			overweight[i] = a.ideal
+		}
+	}
+
+	// Global minimum trade size: drop sells below minTradeAmt and rescale the survivors,
+	// repeating until every remaining sell clears the threshold or only one is left.
+	// Phase 1 sells are deliberate clean-ups and are exempt, as is a liquidation.
+	if minTrade := minTradeAmt(goal, opts); minTrade.IsPositive() && !liquidate {
+		for {
+			below := belowMinTrade(redeemAmts, minTrade)
+			if len(below) == 0 {
+				break
+			}
+			for _, i := range below {
+				totalIdeal = totalIdeal.Sub(allocs[i].ideal)
+				allocs[i].ideal = decimal.Zero
+				dropped[i] = []models.TradeError{{
+					Message: "Not traded because the allocation is below the minimum trade amount",
+					Code:    models.CodeBelowMinTrade,
+				}}
+			}
+			redeemAmts = scaleIdeals()
+		}
+	}
+
+	// isFull reports whether a sell of product i closes the position.
+	isFull := func(i int, amt decimal.Decimal) bool {
+		return allocs[i].holding != nil && amt.IsPositive() && amt.GreaterThanOrEqual(held[i])
+	}
+	// sellUnits converts a sell of product i into units at its model market price, never
+	// more than the units held. A full redemption sells every unit held.
+	sellUnits := func(i int, amt decimal.Decimal) decimal.Decimal {
+		if isFull(i, amt) {
+			return heldUnits[i]
+		}
+		price, _ := decimal.NewFromString(allocs[i].mp.MarketPrice)
+		if !price.IsPositive() || !amt.IsPositive() {
+			return decimal.Zero
+		}
+		units := div(amt, price).Truncate(int32(unitPrec))
+		if allocs[i].holding != nil {
+			units = decimal.Min(units, heldUnits[i])
+		}
+		return units
+	}
+	// sellError checks a sell of product i against its redemption and holding minimums.
+	sellError := func(i int, amt decimal.Decimal) []models.TradeError {
+		a := allocs[i]
+		if !amt.IsPositive() || a.holding == nil {
+			return nil
+		}
+		return checkRedemptionMinimums(
+			amt, sellUnits(i, amt),
+			isFull(i, amt),
+			a.holding.Value, a.holding.Units,
+			a.mp.MinRedemptionAmt, a.mp.MinRedemptionUnits,
+			a.mp.MinHoldingAmt, a.mp.MinHoldingUnits,
+			amountPrec, unitPrec,
+		)
+	}
+
+	// passes reports whether a sell of product i is allowed: not dropped, no more than the
+	// holding, and within its minimums.
+	passes := func(i int, amt decimal.Decimal) bool {
+		return dropped[i] == nil && !amt.GreaterThan(held[i]) && sellError(i, amt) == nil
+	}
+	maxPartial := make([]decimal.Decimal, len(allocs))
+	for i, a := range allocs {
+		maxPartial[i] = decimal.Min(maxPartialSell(a.mp, a.holding, amountPrec), held[i])
+	}
+
+	// Truncation residual: truncating each sell leaves Σ sells up to a smallest unit per
+	// product short of the Phase 2 budget, and more when sells were clamped to their
+	// holding. Hand the shortfall to the passing partial sells, never past what leaves their
+	// holding minimum, by opts.ResidualPolicy; the default favours the sells that truncation
+	// cut the most. What none of them can take is reported as unfulfilled, as is a budget
+	// left by Phase 1 that no model product can sell, e.g. because blocked units held a
+	// Phase 1 sell short.
+	unfulfilled := decimal.Zero
+	residual := remaining.Sub(sumDecimals(redeemAmts))
+	if residual.IsPositive() && !totalIdeal.IsPositive() && kept.IsPositive() {
+		// Too much of the goal is kept for the model products to cover the order.
+		unfulfilled = residual
+	} else if residual.IsPositive() {
+		var takers []int
+		for i, amt := range redeemAmts {
+			if amt.IsPositive() && amt.LessThan(held[i]) && passes(i, amt) {
+				takers = append(takers, i)
+			}
 		}
+		sort.SliceStable(takers, func(a, b int) bool {
+			ra, rb := scaled[takers[a]].Sub(redeemAmts[takers[a]]), scaled[takers[b]].Sub(redeemAmts[takers[b]])
+			return ra.GreaterThan(rb)
+		})
+		phase2Weights := make([]decimal.Decimal, len(allocs))
+		for i, a := range allocs {
+			phase2Weights[i] = weightMap[a.mp.Ticker]
+		}
+		unit := decimal.New(1, -int32(amountPrec))
+		unfulfilled = distributeResidual(redeemAmts, takers, residual, unit, phase2Weights, opts.ResidualPolicy,
+			func(i int) decimal.Decimal { return maxPartial[i] })
+	}
 
-		var tradeErr *models.TradeError
-		if redeemAmt.IsPositive() && a.holding != nil {
-			currentVal, _ := decimal.NewFromString(a.holding.Value)
-			isFullRedemption := redeemAmt.GreaterThanOrEqual(currentVal)
-			tradeErr = checkRedemptionMinimums(
-				redeemAmt, units,
-				isFullRedemption,
+	// Repair step: with the reduce policy, first trim sells that would breach the holding
+	// minimum and hand the rest to the other sells; then bump sells below their redemption
+	// minimum, funded by the other sells.
+	preRepair := redeemAmts
+	if opts.MinHoldingPolicy == MinHoldingReduce {
+		holdingError := func(i int, amt decimal.Decimal) []models.TradeError {
+			a := allocs[i]
+			if dropped[i] != nil || !amt.IsPositive() || a.holding == nil {
+				return nil
+			}
+			return checkRedemptionMinimums(
+				amt, sellUnits(i, amt),
+				isFull(i, amt),
 				a.holding.Value, a.holding.Units,
-				a.mp.MinRedemptionAmt, a.mp.MinRedemptionUnits,
+				"", "",
 				a.mp.MinHoldingAmt, a.mp.MinHoldingUnits,
 				amountPrec, unitPrec,
 			)
 		}
+		breaches := func(i int, amt decimal.Decimal) bool { return holdingError(i, amt) != nil }
+		var trimmed decimal.Decimal
+		redeemAmts, trimmed = reduceRedemptions(redeemAmts, maxPartial, breaches, passes, amountPrec)
+		unfulfilled = unfulfilled.Add(trimmed)
+		for i := range allocs {
+			// A position already at its minimum cannot be sold at all; the trade keeps the reason.
+			if redeemAmts[i].IsZero() && preRepair[i].IsPositive() {
+				dropped[i] = holdingError(i, preRepair[i])
+			}
+		}
+	}
+	reqSell := make([]decimal.Decimal, len(allocs))
+	priority := make([]int, len(allocs))
+	for i, a := range allocs {
+		reqSell[i] = requiredSell(a.mp, amountPrec)
+		priority[i] = parseOptionalInt(a.mp.Priority)
+	}
+	redeemAmts = repairRedemptionViolations(redeemAmts, reqSell, held, priority, opts.MinHoldingPolicy == MinHoldingSellAll, passes, amountPrec)
+
+	for i, a := range allocs {
+		redeemAmt := redeemAmts[i]
+		units := sellUnits(i, redeemAmt)
+
+		tradeErrs := dropped[i]
+		if tradeErrs == nil {
+			tradeErrs = sellError(i, redeemAmt)
+		}
 
-		details = append(details, models.TransactionDetail{
-			Ticker:    a.mp.Ticker,
-			Direction: "SELL",
-			Value:     redeemAmt.StringFixed(int32(amountPrec)),
-			Units:     units.StringFixed(int32(unitPrec)),
-			Error:     tradeErr,
-		})
+		detail := withErrors(models.TransactionDetail{
+			Ticker:         a.mp.Ticker,
+			Direction:      "SELL",
+			Value:          redeemAmt.StringFixed(int32(amountPrec)),
+			Units:          units.StringFixed(int32(unitPrec)),
+			FullRedemption: isFull(i, redeemAmt) && !isBlocked(*a.holding),
+		}, tradeErrs...)
+		if opts.Explain {
+			currentVal := decimal.Zero
+			if a.holding != nil {
+				currentVal, _ = decimal.NewFromString(a.holding.Value)
+			}
+			detail.Trace = redemptionTrace("2", currentVal, redeemAmt, amountPrec)
+			detail.Trace.Ideal = overweight[i].String()
+			setRepairAdjustment(detail.Trace, preRepair[i], redeemAmt, amountPrec)
+		}
+		details = append(details, detail)
 	}
 
-	return models.GoalResult{
-		GoalID:             goal.GoalID,
-		TransactionType:    redemptionType(orderAmount, vTotal, volatilityBuffer),
-		TransactionDetails: details,
+	result := models.GoalResult{
+		GoalID:              goal.GoalID,
+		TransactionType:     redemptionType(orderAmount, vTotal, opts.VolatilityBuffer),
+		TransactionDetails:  details,
+		TotalPortfolioValue: vTotal.StringFixed(int32(amountPrec)),
+	}
+	excess := orderAmount.Sub(requested)
+	if capErr == nil || unitOrder || excess.IsPositive() {
+		result.EffectiveOrderAmount = orderAmount.StringFixed(int32(amountPrec))
+	}
+	if excess.IsPositive() {
+		result.FullRedemptionExcess = excess.StringFixed(int32(amountPrec))
 	}
+	if unfulfilled.IsPositive() {
+		result.UnfulfilledAmount = unfulfilled.StringFixed(int32(amountPrec))
+		result = flagBlockedUnits(goal.GoalDetails, result, amountPrec, unitPrec, goal.KeepUnmodeled, weightMap)
+	}
+	return realizedGains(sent, withholdingTax(goal, result, amountPrec, opts), amountPrec, unitPrec, opts)
+}
+
+// repairRedemptionViolations is the redemption counterpart of repairViolations: it bumps
+// Phase 2 sells that fall short of their redemption minimum up to reqSell[i], funded by
+// reducing the other sells pro-rata to their slack above their own reqSell, so that
+// Σ sells is unchanged. passes(i, sell) reports whether selling sell of
+// product i meets all of its minimums.
+//
+// A violation is only bumped when reqSell[i] is no more than held[i] and selling it
+// passes, so the bump never sells more than the holding nor breaches the holding minimum.
+// As in the investment repair, violations are funded by priority[i], highest first, then
+// cheapest bump first (equal bumps keep model order) while donor slack lasts; the rest
+// stay flagged. Donors that are full redemptions are left alone, since reducing them would
+// turn them into partial sells subject to the holding minimum.
+//
+// held[i] is the sellable value of product i. With autoFull (MinHoldingSellAll), a
+// violating sell that cannot be fixed by bumping it to reqSell[i], typically because the
+// remainder would breach the holding minimum, is bumped to held[i], the whole position, if
+// that passes instead.
+//
+// The returned slice is a copy; sells is returned unchanged when nothing can be bumped or
+// the result would not keep every bumped and donor sell valid with the same total.
+func repairRedemptionViolations(sells, reqSell, held []decimal.Decimal, priority []int, autoFull bool, passes func(i int, sell decimal.Decimal) bool, amountPrec int) []decimal.Decimal {
+	var violations []violation
+	var donors []int
+	totalSlack := decimal.Zero
+	for i, sell := range sells {
+		if !sell.IsPositive() {
+			continue
+		}
+		if passes(i, sell) {
+			if sell.GreaterThan(reqSell[i]) && sell.LessThan(held[i]) && passes(i, reqSell[i]) {
+				donors = append(donors, i)
+				totalSlack = totalSlack.Add(sell.Sub(reqSell[i]))
+			}
+			continue
+		}
+		switch {
+		case reqSell[i].GreaterThan(sell) && !reqSell[i].GreaterThan(held[i]) && passes(i, reqSell[i]):
+			violations = append(violations, violation{idx: i, bump: reqSell[i].Sub(sell)})
+		case autoFull && held[i].GreaterThan(sell) && passes(i, held[i]):
+			violations = append(violations, violation{idx: i, bump: held[i].Sub(sell)})
+		}
+	}
+	if len(violations) == 0 || !totalSlack.IsPositive() {
+		return sells
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if pi, pj := priority[violations[i].idx], priority[violations[j].idx]; pi != pj {
+			return pi > pj
+		}
+		return violations[i].bump.LessThan(violations[j].bump)
+	})
+
+	result := make([]decimal.Decimal, len(sells))
+	copy(result, sells)
+	remaining := totalSlack
+	totalBump := decimal.Zero
+	var bumped []int
+	for _, v := range violations {
+		if v.bump.GreaterThan(remaining) {
+			break
+		}
+		result[v.idx] = sells[v.idx].Add(v.bump)
+		remaining = remaining.Sub(v.bump)
+		totalBump = totalBump.Add(v.bump)
+		bumped = append(bumped, v.idx)
+	}
+	if totalBump.IsZero() {
+		return sells
+	}
+
+	// Reduce donors pro-rata by their slack, then take the truncation residual a unit at a
+	// time in model order.
+	reduced := decimal.Zero
+	for _, d := range donors {
+		slack := sells[d].Sub(reqSell[d])
+		r := decimal.Min(div(slack, totalSlack).Mul(totalBump).Truncate(int32(amountPrec)), slack)
+		result[d] = result[d].Sub(r)
+		reduced = reduced.Add(r)
+	}
+	unit := decimal.New(1, -int32(amountPrec))
+	takeResidual(result, donors, func(i int) decimal.Decimal { return reqSell[i] }, totalBump.Sub(reduced), unit)
+
+	if !sumDecimals(result).Equal(sumDecimals(sells)) {
+		return sells
+	}
+	for _, i := range append(bumped, donors...) {
+		if result[i].IsPositive() && !passes(i, result[i]) {
+			return sells
+		}
+	}
+	return result
+}
+
+// unitOrderAmount converts an order of orderUnit units into an amount at the weighted
+// average price of holdings, Σ value / Σ units, truncated to amountPrec and never more than
+// vTotal, their total value. The units are multiplied before dividing, so that a whole
+// number of units at a repeating average price comes out exact.
+func unitOrderAmount(orderUnit string, holdings map[string]models.Holding, vTotal decimal.Decimal, amountPrec int) decimal.Decimal {
+	orderUnits, _ := decimal.NewFromString(strings.TrimSpace(orderUnit))
+	totalUnits := decimal.Zero
+	for _, h := range holdings {
+		u, _ := decimal.NewFromString(h.Units)
+		totalUnits = totalUnits.Add(u)
+	}
+	if !totalUnits.IsPositive() {
+		return decimal.Zero
+	}
+	if orderUnits.GreaterThanOrEqual(totalUnits) {
+		return vTotal
+	}
+	return decimal.Min(div(orderUnits.Mul(vTotal), totalUnits).Truncate(int32(amountPrec)), vTotal)
+}
+
+// requiredSell returns the smallest sell that clears mp's redemption minimums, in amount and
+// in units at mp's market price, rounded up to amountPrec; 0 if no minimum applies.
+func requiredSell(mp models.ModelItem, amountPrec int) decimal.Decimal {
+	minAmt, _ := decimal.NewFromString(mp.MinRedemptionAmt)
+	minUnits, _ := decimal.NewFromString(mp.MinRedemptionUnits)
+	price, _ := decimal.NewFromString(mp.MarketPrice)
+	return decimal.Max(minAmt, ceilToPrec(minUnits.Mul(price), int32(amountPrec)), decimal.Zero)
+}
+
+// maxPartialSell returns the largest sell of a holding h of mp that leaves mp's holding
+// minimums behind, in amount and in units at mp's market price, rounded down to amountPrec;
+// 0 if h is nil or already at or below its minimum.
+func maxPartialSell(mp models.ModelItem, h *models.Holding, amountPrec int) decimal.Decimal {
+	if h == nil {
+		return decimal.Zero
+	}
+	value, _ := decimal.NewFromString(h.Value)
+	units, _ := decimal.NewFromString(h.Units)
+	minAmt, _ := decimal.NewFromString(mp.MinHoldingAmt)
+	minUnits, _ := decimal.NewFromString(mp.MinHoldingUnits)
+	price, _ := decimal.NewFromString(mp.MarketPrice)
+	maxSell := decimal.Min(value.Sub(minAmt), units.Sub(minUnits).Mul(price))
+	return decimal.Max(maxSell.Truncate(int32(amountPrec)), decimal.Zero)
 }
 
 // redemptionType determines the redemption transaction type label based on the
@@ -220,8 +675,20 @@ func redemptionType(orderAmount, vTotal decimal.Decimal, volatilityBuffer string
 	return "Partial Redemption"
 }
 
+// withinFullRedemptionTolerance reports whether orderAmount is short of vTotal but no more
+// than tolerance allows: vTotal × tolerance ≤ orderAmount < vTotal. An empty tolerance
+// never applies.
+func withinFullRedemptionTolerance(orderAmount, vTotal decimal.Decimal, tolerance string) bool {
+	tol, err := decimal.NewFromString(strings.TrimSpace(tolerance))
+	if err != nil || !tol.IsPositive() {
+		return false
+	}
+	return orderAmount.LessThan(vTotal) && orderAmount.GreaterThanOrEqual(vTotal.Mul(tol))
+}
+
 // checkRedemptionMinimums validates both the minimum redemption size and the
-// minimum remaining holding after a partial redemption.
+// minimum remaining holding after a partial redemption. It returns every minimum the
+// sell breaches, amounts before units, or nil when it meets them all.
 // A full redemption (isFullRedemption=true) bypasses the min-holding check.
 func checkRedemptionMinimums(
 	redeemAmt, units decimal.Decimal,
@@ -230,16 +697,16 @@ func checkRedemptionMinimums(
 	minRedAmtStr, minRedUnitsStr string,
 	minHoldAmtStr, minHoldUnitsStr string,
 	amountPrec, unitPrec int,
-) *models.TradeError {
+) []models.TradeError {
+	check := minimumCheck{amountPrec: amountPrec, unitPrec: unitPrec}
+
 	// 1. Minimum redemption amount / units
 	minRedAmt, _ := decimal.NewFromString(minRedAmtStr)
 	minRedUnits, _ := decimal.NewFromString(minRedUnitsStr)
-	if redeemAmt.LessThan(minRedAmt) || units.LessThan(minRedUnits) {
-		return &models.TradeError{
-			Message: "Cannot trade this ticker because it breaches the minimum redemption amount",
-			Code:    "MIN_REDEMPTION_VIOLATION",
-		}
-	}
+	check.amount(redeemAmt, minRedAmt, models.CodeMinRedemptionViolation,
+		"Cannot trade this ticker because it breaches the minimum redemption amount")
+	check.units(units, minRedUnits, models.CodeMinRedemptionViolation,
+		"Cannot trade this ticker because it breaches the minimum redemption units")
 
 	// 2. Minimum holding after partial redemption (full redemption always allowed)
 	if !isFullRedemption {
@@ -249,12 +716,10 @@ func checkRedemptionMinimums(
 		remainingUnits := currentUnits.Sub(units)
 		minHoldAmt, _ := decimal.NewFromString(minHoldAmtStr)
 		minHoldUnits, _ := decimal.NewFromString(minHoldUnitsStr)
-		if remainingAmt.LessThan(minHoldAmt) || remainingUnits.LessThan(minHoldUnits) {
-			return &models.TradeError{
-				Message: "Cannot trade this ticker because the remaining holding would breach the minimum holding amount",
-				Code:    "MIN_HOLDING_VIOLATION",
-			}
-		}
+		check.amount(remainingAmt, minHoldAmt, models.CodeMinHoldingViolation,
+			"Cannot trade this ticker because the remaining holding would breach the minimum holding amount")
+		check.units(remainingUnits, minHoldUnits, models.CodeMinHoldingViolation,
+			"Cannot trade this ticker because the remaining holding would breach the minimum holding units")
 	}
-	return nil
+	return check.errs
 }
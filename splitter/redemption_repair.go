@@ -0,0 +1,83 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Min-holding policies accepted in Options.MinHoldingPolicy. They decide what the Phase 2
+// repair step does with a sell that would leave less than the holding minimum behind.
+const (
+	MinHoldingFlag    = "flag"     // keep the sell and flag it (default)
+	MinHoldingSellAll = "sell_all" // sell the whole position instead; see repairRedemptionViolations
+	MinHoldingReduce  = "reduce"   // sell only down to the minimum; see reduceRedemptions
+)
+
+// ValidMinHoldingPolicy reports whether name is an accepted min-holding policy (empty means default).
+func ValidMinHoldingPolicy(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", MinHoldingFlag, MinHoldingSellAll, MinHoldingReduce:
+		return true
+	}
+	return false
+}
+
+// reduceRedemptions trims each Phase 2 sell for which breaches(i, sell) reports a holding
+// minimum breach down to maxPartial[i], the largest sell that leaves the minimum behind,
+// and hands what it no longer sells to the other sells that pass(i, sell), pro-rata to
+// their headroom below their own maxPartial. The truncation residual goes a unit at a time
+// in model order. It returns the new sells and the part of the trimmed amount that no
+// other sell could take.
+//
+// A trimmed sell is not checked against its redemption minimum here; it is flagged later
+// if the trim takes it below that floor.
+func reduceRedemptions(sells, maxPartial []decimal.Decimal, breaches, passes func(i int, sell decimal.Decimal) bool, amountPrec int) ([]decimal.Decimal, decimal.Decimal) {
+	result := make([]decimal.Decimal, len(sells))
+	copy(result, sells)
+	surplus := decimal.Zero
+	trimmed := make(map[int]bool)
+	for i, sell := range sells {
+		if sell.IsPositive() && breaches(i, sell) && maxPartial[i].LessThan(sell) {
+			result[i] = maxPartial[i]
+			surplus = surplus.Add(sell.Sub(maxPartial[i]))
+			trimmed[i] = true
+		}
+	}
+	if !surplus.IsPositive() {
+		return sells, decimal.Zero
+	}
+
+	var recipients []int
+	headroom := make([]decimal.Decimal, len(sells))
+	totalHeadroom := decimal.Zero
+	for i, sell := range sells {
+		if trimmed[i] || !sell.IsPositive() || !passes(i, sell) || !maxPartial[i].GreaterThan(sell) {
+			continue
+		}
+		recipients = append(recipients, i)
+		headroom[i] = maxPartial[i].Sub(sell)
+		totalHeadroom = totalHeadroom.Add(headroom[i])
+	}
+	if !totalHeadroom.IsPositive() {
+		return result, surplus
+	}
+
+	give := decimal.Min(surplus, totalHeadroom)
+	given := decimal.Zero
+	for _, i := range recipients {
+		g := decimal.Min(div(headroom[i], totalHeadroom).Mul(give).Truncate(int32(amountPrec)), headroom[i])
+		result[i] = result[i].Add(g)
+		given = given.Add(g)
+	}
+	unit := decimal.New(1, -int32(amountPrec))
+	rooms := make([]decimal.Decimal, len(recipients))
+	for k, i := range recipients {
+		rooms[k] = maxPartial[i].Sub(result[i]).Div(unit).Floor()
+	}
+	for k, share := range cyclicShares(rooms, give.Sub(given).Div(unit).Floor()) {
+		result[recipients[k]] = result[recipients[k]].Add(share.Mul(unit))
+		given = given.Add(share.Mul(unit))
+	}
+	return result, surplus.Sub(given)
+}
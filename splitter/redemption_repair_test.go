@@ -0,0 +1,159 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestRedemptionRepairViolations(t *testing.T) {
+	// Redeeming 400 from A, B and C, held at their model weights, sells 100, 100 and 200.
+	// A needs 30 more to reach its minimum and B 40, but C can only give up the 40 above
+	// its own minimum, so only one of them can be fixed.
+	tests := []struct {
+		name    string
+		goal    models.Goal
+		want    map[string]string
+		flagged string
+	}{
+		{
+			name: "cheapest bump first",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithOrderAmount("400").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.25").
+					WithMinRedemption("130", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.25").
+					WithMinRedemption("140", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("C").WithWeight("0.5").
+					WithMinRedemption("160", "0").Build()).
+				WithHolding("A", "1000", "1").
+				WithHolding("B", "1000", "1").
+				WithHolding("C", "2000", "1").
+				Build(),
+			want:    map[string]string{"A": "130.00", "B": "100.00", "C": "170.00"},
+			flagged: "B",
+		},
+		{
+			name: "priority first",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithOrderAmount("400").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.25").
+					WithMinRedemption("130", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.25").
+					WithMinRedemption("140", "0").WithPriority("1").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("C").WithWeight("0.5").
+					WithMinRedemption("160", "0").Build()).
+				WithHolding("A", "1000", "1").
+				WithHolding("B", "1000", "1").
+				WithHolding("C", "2000", "1").
+				Build(),
+			want:    map[string]string{"A": "100.00", "B": "140.00", "C": "160.00"},
+			flagged: "A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessRedemption(tt.goal, 2, 4, Options{})
+			got := byTicker(result)
+			assertValues(t, got, tt.want)
+			for ticker := range tt.want {
+				code := errorCode(got[ticker])
+				if ticker == tt.flagged && code != models.CodeMinRedemptionViolation {
+					t.Errorf("%s flagged %q, want %s", ticker, code, models.CodeMinRedemptionViolation)
+				} else if ticker != tt.flagged && code != "" {
+					t.Errorf("%s flagged %s, want it fixed", ticker, code)
+				}
+			}
+			if total := sumValues(result); total.String() != "400" {
+				t.Errorf("Σ sells = %s, want 400", total)
+			}
+		})
+	}
+}
+
+func TestRedemptionRepairNeverSellsMoreThanHeld(t *testing.T) {
+	// B's 130 sell is short of its 300 minimum, but only 200 of B is held: bumping it would
+	// sell more than the position, so the sell is kept and flagged instead.
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.9").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.1").
+			WithMarketPrice("10").WithMinRedemption("300", "0").Build()).
+		WithHolding("A", "1500", "1").
+		WithHolding("B", "20", "10").
+		Build()
+	result := ProcessRedemption(goal, 2, 4, Options{})
+	got := byTicker(result)
+	if got["A"].Value != "870.00" || got["B"].Value != "130.00" {
+		t.Errorf("A = %s, B = %s, want 870.00 and 130.00", got["A"].Value, got["B"].Value)
+	}
+	if code := errorCode(got["B"]); code != models.CodeMinRedemptionViolation {
+		t.Errorf("B flagged %q, want %s", code, models.CodeMinRedemptionViolation)
+	}
+	if check := VerifyResult(goal, result, 2, 4, Options{}); !check.Passed {
+		t.Errorf("self-check failed: %+v", check.Violations)
+	}
+}
+
+func TestAutoFullRedemptionKeepsDonorsAboveTheirMinimum(t *testing.T) {
+	// Redeeming 400 sells 120 of A and 280 of B. A's sell leaves 180, below its 250 holding
+	// minimum, so autoFullRedemption sells all 300 of A, funding the extra 180 from B. That
+	// is only done while B still sells its own minimum: at 150, B could give up only 130.
+	tests := []struct {
+		name    string
+		goal    models.Goal
+		want    map[string]string
+		flagged models.Code
+	}{
+		{
+			name: "B can fund the full redemption",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithOrderAmount("400").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.3").
+					WithMinHolding("250", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.7").
+					WithMinRedemption("50", "0").Build()).
+				WithHolding("A", "300", "1").
+				WithHolding("B", "700", "1").
+				Build(),
+			want: map[string]string{"A": "300.00", "B": "100.00"},
+		},
+		{
+			name: "B would fall below its minimum",
+			goal: testhelpers.NewGoalBuilder("g1").AsRedemption().
+				WithOrderAmount("400").
+				WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.3").
+					WithMinHolding("250", "0").Build()).
+				WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.7").
+					WithMinRedemption("150", "0").Build()).
+				WithHolding("A", "300", "1").
+				WithHolding("B", "700", "1").
+				Build(),
+			want:    map[string]string{"A": "120.00", "B": "280.00"},
+			flagged: models.CodeMinHoldingViolation,
+		},
+	}
+	opts := RequestOptions(models.SplitRequest{AutoFullRedemption: true})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ProcessRedemption(tt.goal, 2, 4, opts)
+			got := byTicker(result)
+			assertValues(t, got, tt.want)
+			if code := errorCode(got["A"]); code != tt.flagged {
+				t.Errorf("A flagged %q, want %q", code, tt.flagged)
+			}
+			if code := errorCode(got["B"]); code != "" {
+				t.Errorf("B flagged %s, want it valid", code)
+			}
+			if got["A"].FullRedemption != (tt.flagged == "") {
+				t.Errorf("A fullRedemption = %v, want %v", got["A"].FullRedemption, tt.flagged == "")
+			}
+			if total := sumValues(result); total.String() != "400" {
+				t.Errorf("Σ sells = %s, want 400", total)
+			}
+			if check := VerifyResult(tt.goal, result, 2, 4, opts); !check.Passed {
+				t.Errorf("self-check failed: %+v", check.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package splitter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// randomRedemption returns a redemption from n products with random weights summing to 1,
+// each held at a random price and number of units, and an order of 1% to 90% of what the
+// goal holds, so that the holdings always suffice. About half the products have a random
+// redemption minimum and half a random holding minimum, so that the repair step has
+// violations to fix, some of them larger than the holding.
+func randomRedemption(rng *rand.Rand, n int) models.Goal {
+	b := testhelpers.NewGoalBuilder("g").AsRedemption()
+	left := decimal.NewFromInt(1)
+	held := decimal.Zero
+	for i := 0; i < n; i++ {
+		weight := decimal.New(1+rng.Int63n(int64(1000000/n)), -6)
+		if i == n-1 {
+			weight = left
+		}
+		left = left.Sub(weight)
+		ticker := fmt.Sprintf("P%02d", i)
+		price := randomAmount(rng, 200) + "7"
+		units := decimal.New(1+rng.Int63n(1000000), -4).String()
+		u, _ := decimal.NewFromString(units)
+		p, _ := decimal.NewFromString(price)
+		value := u.Mul(p).Truncate(2)
+		mp := testhelpers.NewModelItemBuilder(ticker).WithWeight(weight.String()).WithMarketPrice(price)
+		if rng.Intn(2) == 0 {
+			mp.WithMinRedemption(value.Mul(decimal.New(rng.Int63n(150), -2)).Truncate(2).String(), "0")
+		}
+		if rng.Intn(2) == 0 {
+			mp.WithMinHolding(value.Mul(decimal.New(rng.Int63n(50), -2)).Truncate(2).String(), "0")
+		}
+		b.WithModelItem(mp.Build())
+		b.WithHolding(ticker, units, price)
+		held = held.Add(value)
+	}
+	order := held.Mul(decimal.New(1+rng.Int63n(90), -2)).Truncate(2)
+	return b.WithOrderAmount(order.String()).Build()
+}
+
+func TestRedemptionSellsSumToOrderAmount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1339))
+	unit := decimal.New(1, -2)
+	for run := 0; run < 300; run++ {
+		goal := randomRedemption(rng, 1+rng.Intn(10))
+		order, _ := decimal.NewFromString(goal.OrderAmount)
+		for _, policy := range []string{ResidualLargestRemainder, ResidualRoundRobin, ResidualHighestWeight} {
+			for _, minHolding := range []string{MinHoldingFlag, MinHoldingSellAll} {
+				opts := Options{ResidualPolicy: policy, MinHoldingPolicy: minHolding}
+				result := ProcessRedemption(goal, 2, 4, opts)
+				// Sells flagged with a violation take no part of the truncation residual, so a
+				// residual none of the passing sells has room for is reported as unfulfilled.
+				unfulfilled, _ := decimal.NewFromString(result.UnfulfilledAmount)
+				if n := decimal.NewFromInt(int64(len(result.TransactionDetails))); unfulfilled.GreaterThan(unit.Mul(n)) {
+					t.Errorf("run %d, %s, %s: unfulfilledAmount = %s, more than the truncation residual", run, policy, minHolding, unfulfilled)
+				}
+				if diff := order.Sub(sumValues(result)).Sub(unfulfilled).Abs(); !diff.LessThan(unit) {
+					t.Fatalf("run %d, %s, %s: Σ sells %s and unfulfilled %s are %s away from order %s",
+						run, policy, minHolding, sumValues(result), unfulfilled, diff, order)
+				}
+				assertSellsWithinHoldings(t, goal, result)
+				if check := VerifyResult(goal, result, 2, 4, opts); !check.Passed {
+					t.Errorf("run %d, %s, %s: self-check failed: %+v", run, policy, minHolding, check.Violations)
+				}
+			}
+		}
+	}
+}
+
+// assertSellsWithinHoldings fails t if any sell in result is larger, in value or units,
+// than the holding it sells from.
+func assertSellsWithinHoldings(t *testing.T, goal models.Goal, result models.GoalResult) {
+	t.Helper()
+	held := make(map[string]models.Holding)
+	for _, h := range goal.GoalDetails {
+		held[h.Ticker] = h
+	}
+	for _, d := range result.TransactionDetails {
+		h := held[d.Ticker]
+		value, _ := decimal.NewFromString(d.Value)
+		units, _ := decimal.NewFromString(d.Units)
+		heldValue, _ := decimal.NewFromString(h.Value)
+		heldUnits, _ := decimal.NewFromString(h.Units)
+		if value.GreaterThan(heldValue) || units.GreaterThan(heldUnits) {
+			t.Errorf("%s sells %s (%s units) of a holding of %s (%s units)", d.Ticker, d.Value, d.Units, h.Value, h.Units)
+		}
+	}
+}
+
+func TestRedemptionClampsScaledSellsToTheHolding(t *testing.T) {
+	// The weights sum to a little over 1, so Phase 2 scales the ideals up to the 600 budget
+	// and would sell 500.04 of A. A holds 500.009, more precisely than amountPrec: it is
+	// sold whole, as 500.00 and every unit at unitPrec, and B takes the rest.
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithOrderAmount("600").
+		WithTicker("A", "0.00001", "1").
+		WithTicker("B", "1.0001", "1").
+		WithHoldingValue("A", "500.009", "1", "500.009").
+		WithHoldingValue("B", "500.009", "1", "500.009").
+		Build()
+	tests := []struct {
+		unitPrec int
+		unitsA   string
+	}{
+		{2, "500.00"},
+		{4, "500.0090"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.unitPrec), func(t *testing.T) {
+			result := ProcessRedemption(goal, 2, tt.unitPrec, Options{})
+			got := byTicker(result)
+			if a := got["A"]; a.Value != "500.00" || a.Units != tt.unitsA || !a.FullRedemption {
+				t.Errorf("A = %s (%s units, full %v), want 500.00 (%s units, full)", a.Value, a.Units, a.FullRedemption, tt.unitsA)
+			}
+			if b := got["B"]; b.Value != "100.00" || b.FullRedemption {
+				t.Errorf("B = %s (full %v), want a partial 100.00", b.Value, b.FullRedemption)
+			}
+			if check := VerifyResult(goal, result, 2, tt.unitPrec, Options{}); !check.Passed {
+				t.Errorf("self-check failed: %+v", check.Violations)
+			}
+		})
+	}
+}
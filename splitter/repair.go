@@ -0,0 +1,443 @@
+package splitter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Repair strategies accepted in Options.RepairStrategy. They decide how the investment
+// repair step takes the bumps back from the products funding them.
+const (
+	RepairStrategyGreedy       = "greedy"       // pro-rata to each donor's safe slack (default)
+	RepairStrategyProportional = "proportional" // the same share of every donor's gross
+)
+
+// ValidRepairStrategy reports whether name is an accepted repair strategy (empty means default).
+func ValidRepairStrategy(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", RepairStrategyGreedy, RepairStrategyProportional:
+		return true
+	}
+	return false
+}
+
+// repairer runs the investment repair step for one goal. allocs is shared with
+// ProcessInvestment, so products dropped and rescaled between passes are seen by later ones.
+type repairer struct {
+	allocs     []productAlloc
+	amountPrec int
+	opts       Options
+	strategy   repairStrategy
+}
+
+// newRepairer returns a repairer using the strategy selected by opts.RepairStrategy.
+func newRepairer(allocs []productAlloc, amountPrec int, opts Options) *repairer {
+	var strategy repairStrategy = greedyRepairer{}
+	if opts.RepairStrategy == RepairStrategyProportional {
+		strategy = proportionalRepairer{}
+	}
+	return &repairer{allocs: allocs, amountPrec: amountPrec, opts: opts, strategy: strategy}
+}
+
+// repairStrategy funds the bumps of one repair pass. The repairer identifies and orders the
+// violations, hands back over-zeroed excess and enforces the sum invariant; a strategy only
+// decides where the money for the bumps comes from.
+type repairStrategy interface {
+	// selectDonors returns the non-violating products with a positive gross, each with the
+	// safe slack it can give up, and the Tier 2 zero-out candidates among them in the
+	// order they are zeroed.
+	selectDonors(r *repairer, items []repairItem, violating map[int]bool) (donors, zeroable []donor)
+
+	// computeBumps raises the violations it can fund to their required gross in result and
+	// zeroes the candidates it gives up to do so. It returns the total bump and the zeroed
+	// products.
+	computeBumps(r *repairer, items []repairItem, result []decimal.Decimal, violations []violation, attempt map[int]bool, donors, zeroable []donor) (decimal.Decimal, map[int]bool)
+
+	// redistribute takes needed from donors in result, never taking a donor below its
+	// required gross.
+	redistribute(r *repairer, items []repairItem, result []decimal.Decimal, donors []donor, needed decimal.Decimal)
+}
+
+// repairItem is a product's gross allocation and the minimum gross that clears its checks.
+type repairItem struct {
+	gross    decimal.Decimal
+	reqGross decimal.Decimal // minimum gross to pass all checks; 0 if no minimum applies
+	cap      decimal.Decimal // model-weight cap on gross; see initialGross
+}
+
+// givebackCeiling is the most the repair step hands back to a product out of over-zeroed
+// funds: its pre-repair gross or its model-weight cap, whichever is higher.
+func (it repairItem) givebackCeiling() decimal.Decimal {
+	return decimal.Max(it.gross, it.cap)
+}
+
+// violation is a fixable minimum violation and the bump needed to clear it.
+type violation struct {
+	idx  int
+	bump decimal.Decimal
+}
+
+// donor is a non-violating product that can help fund the bumps.
+type donor struct {
+	idx       int
+	safeSlack decimal.Decimal // gross − reqGross; can always be taken without creating a new violation
+	reqGross  decimal.Decimal // additional slack available only if the product is zeroed entirely
+}
+
+// repairViolations attempts to clear minimum-requirement violations by bumping each
+// violating product's gross allocation up to its required minimum.
+//
+// Two funding tiers, applied in order for each violation (highest priority first, then
+// cheapest bump first):
+//
+//  1. Safe slack: reduce non-violating products from their current gross down to their
+//     own minimum floor (gross_j − reqGross_j). Never creates a new violation.
+//
+//  2. Zero-out: if safe slack alone is insufficient, additionally zero out non-violating
+//     products entirely (smallest reqGross first), gaining their reqGross as extra slack.
+//     A gross of 0 is always valid — it simply means no trade for that product.
+//     Protected products (see protectedFromZeroOut) are never zeroed.
+//     With Options.MinProgressGuard, a product is never zeroed if that would move it further
+//     from its model weight; the violation is left unfixed instead.
+//
+// After deciding which violations to fix, the non-zeroed products fund the bumps as the
+// repair strategy directs, keeping Σ gross == orderAmount exactly, less any over-zeroed
+// excess no product has room for (see giveBack). Should the remaining
+// slack be exhausted before the bumps are funded, the repair is refused and the original
+// allocation returned, so Σ gross never exceeds the order.
+//
+// This is the default "most_fixed" objective. Options.RepairObjective can instead pick the
+// set of violations to fix by maximising the invested amount or minimising drift; see
+// bestRepair. It returns the repaired amounts and the objective actually applied ("" when
+// there was nothing to repair).
+//
+// Product priority only steers these discretionary choices (which violation to fix first,
+// which product to zero first, who absorbs rounding units); it never overrides the
+// model-weight cap or a product's own minimum.
+func (r *repairer) repairViolations(grossAmounts, grossCaps []decimal.Decimal) ([]decimal.Decimal, string) {
+	items := make([]repairItem, len(r.allocs))
+	for i, a := range r.allocs {
+		items[i] = repairItem{gross: grossAmounts[i], reqGross: requiredGross(a, r.amountPrec), cap: grossCaps[i]}
+	}
+
+	// Identify violations: positive gross allocation that falls below reqGross.
+	// Skip violations where reqGross exceeds the model-weight cap — bumping to the
+	// minimum would overshoot the target weight, so the violation is left unfixed.
+	var violations []violation
+	for i, it := range items {
+		if it.gross.IsZero() || it.reqGross.IsZero() {
+			continue
+		}
+		if it.gross.LessThan(it.reqGross) {
+			if it.reqGross.GreaterThan(grossCaps[i]) {
+				continue // cannot fix without overshooting model weight
+			}
+			violations = append(violations, violation{idx: i, bump: it.reqGross.Sub(it.gross)})
+		}
+	}
+	if len(violations) == 0 {
+		return grossAmounts, ""
+	}
+
+	// Sort violations by priority (highest first), then cheapest-first to maximise the
+	// number fixed when resources are limited. Equal bumps keep model order.
+	allocs := r.allocs
+	sort.SliceStable(violations, func(i, j int) bool {
+		pi, pj := allocs[violations[i].idx].priority, allocs[violations[j].idx].priority
+		if pi != pj {
+			return pi > pj
+		}
+		if !violations[i].bump.Equal(violations[j].bump) {
+			return violations[i].bump.LessThan(violations[j].bump)
+		}
+		return violations[i].idx < violations[j].idx
+	})
+
+	switch r.opts.RepairObjective {
+	case RepairMaxInvested, RepairMinDrift:
+		if len(violations) <= maxExhaustiveViolations {
+			return r.bestRepair(items, grossAmounts, violations), r.opts.RepairObjective
+		}
+	}
+	return r.apply(items, grossAmounts, violations, nil), RepairMostFixed
+}
+
+// apply funds the bumps for violations in order, as described on repairViolations.
+// When attempt is non-nil only the violations it contains are bumped; the others stay
+// flagged but are still excluded from donating slack.
+func (r *repairer) apply(items []repairItem, grossAmounts []decimal.Decimal, violations []violation, attempt map[int]bool) []decimal.Decimal {
+	violatingSet := make(map[int]bool)
+	for _, v := range violations {
+		violatingSet[v.idx] = true
+	}
+	donors, zeroable := r.strategy.selectDonors(r, items, violatingSet)
+	if len(donors) == 0 {
+		return grossAmounts
+	}
+
+	result := make([]decimal.Decimal, len(grossAmounts))
+	copy(result, grossAmounts)
+	totalBumpUsed, zeroedSet := r.strategy.computeBumps(r, items, result, violations, attempt, donors, zeroable)
+	if totalBumpUsed.IsZero() {
+		return grossAmounts
+	}
+
+	// Compute the net reduction still required from non-zeroed non-violating products.
+	// (Zeroed products already contribute their full gross to balancing the sum.)
+	zeroedContribution := decimal.Zero
+	for idx := range zeroedSet {
+		zeroedContribution = zeroedContribution.Add(items[idx].gross)
+	}
+	stillNeeded := totalBumpUsed.Sub(zeroedContribution)
+
+	var redistItems []donor
+	for _, d := range donors {
+		if !zeroedSet[d.idx] {
+			redistItems = append(redistItems, d)
+		}
+	}
+
+	if stillNeeded.IsPositive() {
+		// If the strategy cannot take it all, the invariant check below refuses the repair.
+		r.strategy.redistribute(r, items, result, redistItems, stillNeeded)
+	} else if stillNeeded.IsNegative() {
+		r.giveBack(items, result, violations, redistItems, stillNeeded.Neg())
+	}
+
+	// Invariant: the repair only moves money between products, so Σ result must not exceed
+	// Σ grossAmounts (and hence orderAmount). If the bumps could not be fully funded, refuse
+	// the repair and leave the violations flagged rather than over-allocate the order.
+	if sumDecimals(result).GreaterThan(sumDecimals(grossAmounts)) {
+		return grossAmounts
+	}
+	return result
+}
+
+// giveBack hands the excess freed by over-zeroing (the last zeroed product's reqGross
+// exceeded what was strictly needed) back to the fixed-violation products according to the
+// residual policy, but never past their giveback ceiling, so that a bumped product does not
+// drift above its model weight. violations is already ordered highest priority first. What
+// they have no room for goes to the untouched products up to the same ceiling. Anything
+// still left is not placed: Σ result falls short of the order by it, and ProcessInvestment
+// reports it as unfulfilled.
+func (r *repairer) giveBack(items []repairItem, result []decimal.Decimal, violations []violation, untouched []donor, excess decimal.Decimal) {
+	unit := decimal.New(1, -int32(r.amountPrec))
+	var fixedIdxs []int
+	for _, v := range violations {
+		if result[v.idx].Equal(items[v.idx].reqGross) {
+			fixedIdxs = append(fixedIdxs, v.idx)
+		}
+	}
+	weights := make([]decimal.Decimal, len(r.allocs))
+	for i, a := range r.allocs {
+		weights[i] = a.weight
+	}
+	ceiling := func(i int) decimal.Decimal { return items[i].givebackCeiling() }
+	excess = distributeResidual(result, fixedIdxs, excess, unit, weights, r.opts.ResidualPolicy, ceiling)
+	redistIdxs := make([]int, len(untouched))
+	for i, d := range untouched {
+		redistIdxs[i] = d.idx
+	}
+	distributeResidual(result, redistIdxs, excess, unit, weights, r.opts.ResidualPolicy, ceiling)
+}
+
+// takeRounding takes the truncation residual left by a strategy's reductions one unit at a
+// time, from the lowest-priority donors first, until it is covered or no donor has headroom.
+func (r *repairer) takeRounding(items []repairItem, result []decimal.Decimal, donors []donor, residual decimal.Decimal) {
+	byPriority := make([]int, len(donors))
+	for i, d := range donors {
+		byPriority[i] = d.idx
+	}
+	sort.SliceStable(byPriority, func(i, j int) bool {
+		return r.allocs[byPriority[i]].priority < r.allocs[byPriority[j]].priority
+	})
+	unit := decimal.New(1, -int32(r.amountPrec))
+	takeResidual(result, byPriority, func(i int) decimal.Decimal { return items[i].reqGross }, residual, unit)
+}
+
+// greedyRepairer funds the bumps from safe slack first and zeroes products only when that
+// runs out, then reduces the remaining donors pro-rata by their safe slack. This is the
+// default.
+type greedyRepairer struct{}
+
+func (greedyRepairer) selectDonors(r *repairer, items []repairItem, violating map[int]bool) (donors, zeroable []donor) {
+	allocs, opts := r.allocs, r.opts
+	for i, it := range items {
+		if violating[i] || it.gross.IsZero() {
+			continue
+		}
+		donors = append(donors, donor{idx: i, safeSlack: it.gross.Sub(it.reqGross), reqGross: it.reqGross}) // >= 0 since non-violating
+	}
+
+	// Zero-out candidates sorted by priority (lowest first), then reqGross ascending:
+	// prefer zeroing products with the smallest minimum so that we sacrifice as little as possible.
+	// Remaining ties are settled by opts.TieBreak.
+	sorted := make([]donor, len(donors))
+	copy(sorted, donors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, pj := allocs[sorted[i].idx].priority, allocs[sorted[j].idx].priority
+		if pi != pj {
+			return pi < pj
+		}
+		if !sorted[i].reqGross.Equal(sorted[j].reqGross) {
+			return sorted[i].reqGross.LessThan(sorted[j].reqGross)
+		}
+		return tieBreakLess(allocs, sorted[i].idx, sorted[j].idx, opts.TieBreak)
+	})
+
+	// Only candidates with a minimum to give up can bridge a gap.
+	for _, d := range sorted {
+		if d.reqGross.IsZero() {
+			continue
+		}
+		if opts.MinProgressGuard && zeroingRegresses(allocs[d.idx], items[d.idx].gross) {
+			continue
+		}
+		if protectedFromZeroOut(allocs[d.idx], opts) {
+			continue
+		}
+		zeroable = append(zeroable, d)
+	}
+	return donors, zeroable
+}
+
+func (greedyRepairer) computeBumps(_ *repairer, items []repairItem, result []decimal.Decimal, violations []violation, attempt map[int]bool, donors, zeroable []donor) (decimal.Decimal, map[int]bool) {
+	remainingSlack := decimal.Zero // tracks available pool across iterations
+	for _, d := range donors {
+		remainingSlack = remainingSlack.Add(d.safeSlack)
+	}
+
+	// Products are always zeroed from the front of zeroable, so the zeroed set is a prefix of
+	// it and each Tier 2 step is a binary search over the prefix sums of reqGross.
+	zeroablePrefix := make([]decimal.Decimal, len(zeroable)+1)
+	for k, d := range zeroable {
+		zeroablePrefix[k+1] = zeroablePrefix[k].Add(d.reqGross)
+	}
+	nextZeroable := 0 // zeroable[:nextZeroable] have been zeroed
+
+	zeroedSet := make(map[int]bool)
+	totalBumpUsed := decimal.Zero
+
+	for _, v := range violations {
+		if attempt != nil && !attempt[v.idx] {
+			continue
+		}
+		if v.bump.LessThanOrEqual(remainingSlack) {
+			// Tier 1: safe slack is sufficient.
+			result[v.idx] = items[v.idx].reqGross
+			remainingSlack = remainingSlack.Sub(v.bump)
+			totalBumpUsed = totalBumpUsed.Add(v.bump)
+		} else {
+			// Tier 2: try to bridge the gap by zeroing the next non-violating products.
+			extraNeeded := v.bump.Sub(remainingSlack)
+			base := zeroablePrefix[nextZeroable]
+			if zeroablePrefix[len(zeroable)].Sub(base).GreaterThanOrEqual(extraNeeded) {
+				// Shortest run zeroable[nextZeroable:end] whose reqGross covers the gap.
+				end := nextZeroable + sort.Search(len(zeroable)-nextZeroable, func(m int) bool {
+					return zeroablePrefix[nextZeroable+m+1].Sub(base).GreaterThanOrEqual(extraNeeded)
+				}) + 1
+				extraGained := zeroablePrefix[end].Sub(base)
+				result[v.idx] = items[v.idx].reqGross
+				for _, d := range zeroable[nextZeroable:end] {
+					result[d.idx] = decimal.Zero
+					zeroedSet[d.idx] = true
+				}
+				nextZeroable = end
+				// The zeroed products' reqGross values bridge the gap; update the pool.
+				remainingSlack = remainingSlack.Add(extraGained).Sub(v.bump)
+				totalBumpUsed = totalBumpUsed.Add(v.bump)
+			}
+			// else: insufficient resources even with zeroing — leave this violation unfixed.
+		}
+	}
+	return totalBumpUsed, zeroedSet
+}
+
+func (greedyRepairer) redistribute(r *repairer, items []repairItem, result []decimal.Decimal, donors []donor, needed decimal.Decimal) {
+	totalSlack := decimal.Zero
+	for _, d := range donors {
+		totalSlack = totalSlack.Add(d.safeSlack)
+	}
+	if !totalSlack.IsPositive() {
+		return
+	}
+	reduced := decimal.Zero
+	for _, d := range donors {
+		cut := decimal.Min(div(d.safeSlack, totalSlack).Mul(needed).Truncate(int32(r.amountPrec)), d.safeSlack)
+		result[d.idx] = result[d.idx].Sub(cut)
+		reduced = reduced.Add(cut)
+	}
+	r.takeRounding(items, result, donors, needed.Sub(reduced))
+}
+
+// proportionalRepairer chooses donors and bumps like greedyRepairer but takes the same
+// share of every donor's gross, so the donors keep their relative sizes. A donor whose
+// share would exceed its safe slack gives up only its slack, and the rest of its share is
+// spread over the others the same way.
+type proportionalRepairer struct {
+	greedyRepairer
+}
+
+func (proportionalRepairer) redistribute(r *repairer, items []repairItem, result []decimal.Decimal, donors []donor, needed decimal.Decimal) {
+	cuts := make([]decimal.Decimal, len(donors))
+	active := make([]int, len(donors))
+	for k := range donors {
+		active[k] = k
+	}
+	remaining := needed
+	for len(active) > 0 && remaining.IsPositive() {
+		base := decimal.Zero
+		for _, k := range active {
+			base = base.Add(items[donors[k].idx].gross)
+		}
+		share := div(remaining, base)
+		var next []int
+		for _, k := range active {
+			if cut := items[donors[k].idx].gross.Mul(share); cut.GreaterThanOrEqual(donors[k].safeSlack) {
+				cuts[k] = donors[k].safeSlack
+				remaining = remaining.Sub(cuts[k])
+			} else {
+				next = append(next, k)
+			}
+		}
+		if len(next) == len(active) {
+			// No donor hit its slack: the share covers the rest.
+			for _, k := range active {
+				cuts[k] = items[donors[k].idx].gross.Mul(share)
+			}
+			break
+		}
+		active = next
+	}
+
+	reduced := decimal.Zero
+	for k, d := range donors {
+		cut := cuts[k].Truncate(int32(r.amountPrec))
+		result[d.idx] = result[d.idx].Sub(cut)
+		reduced = reduced.Add(cut)
+	}
+	r.takeRounding(items, result, donors, needed.Sub(reduced))
+}
+
+// protectedFromZeroOut reports whether the repair step must not zero a product: it is
+// flagged protectFromZeroOut, or its model weight exceeds Options.ZeroOutMaxWeight.
+// Protected products still give up their safe slack.
+func protectedFromZeroOut(a productAlloc, opts Options) bool {
+	if a.mp.ProtectFromZeroOut {
+		return true
+	}
+	maxWeight, err := decimal.NewFromString(strings.TrimSpace(opts.ZeroOutMaxWeight))
+	return err == nil && a.weight.GreaterThan(maxWeight)
+}
+
+// zeroingRegresses reports whether dropping a product's gross allocation to zero would leave
+// it further from its model weight than trading it would.
+func zeroingRegresses(a productAlloc, gross decimal.Decimal) bool {
+	fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+	net := gross.Mul(decimal.NewFromInt(1).Sub(fee))
+	traded := a.current.Add(net).Sub(a.target).Abs()
+	zeroed := a.current.Sub(a.target).Abs()
+	return zeroed.GreaterThan(traded)
+}
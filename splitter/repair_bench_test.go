@@ -0,0 +1,68 @@
+package splitter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// benchmarkSizes are the model sizes the repair benchmarks run at.
+var benchmarkSizes = []int{1000, 5000, 10000}
+
+// benchmarkGoal is an investment of 1,000,000 into n equally weighted products, against a
+// holding outside the model worth as much again, so that every product gets half its ideal.
+// Every fifth product's minimum is 1.5× its gross, a violation the repair can fix within
+// the model-weight cap; the others need half their gross and have the rest as slack.
+func benchmarkGoal(n int) models.Goal {
+	order := decimal.NewFromInt(1000000)
+	gross := order.Div(decimal.NewFromInt(int64(n)))
+	weight := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(n)))
+	b := testhelpers.NewGoalBuilder("bench").WithOrderAmount(order.String())
+	for i := 0; i < n; i++ {
+		minimum := gross.Div(decimal.NewFromInt(2))
+		if i%5 == 0 {
+			minimum = gross.Mul(decimal.NewFromFloat(1.5))
+		}
+		b.WithModelItem(testhelpers.NewModelItemBuilder(fmt.Sprintf("P%05d", i)).
+			WithWeight(weight.String()).
+			WithMinInitialInvestment(minimum.StringFixed(2), "0").
+			Build())
+	}
+	return b.WithHolding("Z", order.String(), "1").Build()
+}
+
+func BenchmarkRepairViolations(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("products=%d", n), func(b *testing.B) {
+			goal := benchmarkGoal(n)
+			order, _ := decimal.NewFromString(goal.OrderAmount)
+			postTotal := order.Mul(decimal.NewFromInt(2))
+			allocs := make([]productAlloc, n)
+			for i, mp := range goal.ModelPortfolioDetails {
+				weight, _ := decimal.NewFromString(mp.Weight)
+				allocs[i] = productAlloc{mp: mp, weight: weight, target: weight.Mul(postTotal), ideal: weight.Mul(postTotal)}
+			}
+			gross, caps := initialGross(allocs, order, 2)
+			repair := newRepairer(allocs, 2, Options{})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				repair.repairViolations(gross, caps)
+			}
+		})
+	}
+}
+
+func BenchmarkProcessInvestment(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("products=%d", n), func(b *testing.B) {
+			goal := benchmarkGoal(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ProcessInvestment(goal, 2, 4, Options{})
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package splitter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// randomAmount returns a random amount in [0, max) with 2 decimal places.
+func randomAmount(rng *rand.Rand, max int) string {
+	return decimal.New(rng.Int63n(int64(max)*100), -2).StringFixed(2)
+}
+
+// randomInvestment returns an investment into n products with random weights summing to 1,
+// random holdings in some of them and random minimums and fees, so that the repair step
+// has violations to fix and often too little slack to fix them all.
+func randomInvestment(rng *rand.Rand, n int) models.Goal {
+	b := testhelpers.NewGoalBuilder("g").WithOrderAmount(randomAmount(rng, 5000))
+	raw := make([]int64, n)
+	var total int64
+	for i := range raw {
+		raw[i] = 1 + rng.Int63n(100)
+		total += raw[i]
+	}
+	left := decimal.NewFromInt(1)
+	for i := range raw {
+		weight := decimal.New(raw[i]*1000000/total, -6)
+		if i == n-1 {
+			weight = left
+		}
+		left = left.Sub(weight)
+		ticker := fmt.Sprintf("P%02d", i)
+		mp := testhelpers.NewModelItemBuilder(ticker).
+			WithWeight(weight.String()).
+			WithMarketPrice(randomAmount(rng, 200)+"1").
+			WithMinInitialInvestment(randomAmount(rng, 800), "0").
+			WithMinTopup(randomAmount(rng, 300), "0").
+			WithTransactionFee(decimal.New(rng.Int63n(300), -4).String())
+		if rng.Intn(4) == 0 {
+			mp.ProtectFromZeroOut()
+		}
+		b.WithModelItem(mp.Build())
+		if rng.Intn(2) == 0 {
+			b.WithHolding(ticker, randomAmount(rng, 50), "10")
+		}
+	}
+	return b.Build()
+}
+
+func TestRepairNeverExceedsOrderAmount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1325))
+	optionSets := []Options{
+		{},
+		{RepairStrategy: RepairStrategyProportional},
+		{RepairObjective: RepairMaxInvested},
+		{RepairObjective: RepairMinDrift},
+		{MinProgressGuard: true},
+		{ZeroOnMinViolation: true},
+		{AllocationStrategy: StrategyProRata},
+		{AllocationStrategy: StrategyEqual},
+		{AllocationStrategy: StrategyEqual, RepairStrategy: RepairStrategyProportional},
+	}
+	for run := 0; run < 300; run++ {
+		goal := randomInvestment(rng, 2+rng.Intn(10))
+		order, _ := decimal.NewFromString(goal.OrderAmount)
+		for _, opts := range optionSets {
+			result := ProcessInvestment(goal, 2, 4, opts)
+			if total := sumValues(result); total.GreaterThan(order) {
+				t.Fatalf("run %d, options %+v: Σ gross %s exceeds order %s", run, opts, total, order)
+			}
+			for _, d := range result.TransactionDetails {
+				if v, _ := decimal.NewFromString(d.Value); v.IsNegative() {
+					t.Fatalf("run %d, options %+v: %s has negative value %s", run, opts, d.Ticker, d.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestRepairRefusesBumpWhenSlackIsExhausted(t *testing.T) {
+	// Z is overweight, so A and B share the order below their ideals: A gets 100 of a 150
+	// minimum, B 900 of its own 900 minimum. B has no safe slack and cannot be zeroed, so
+	// A's bump cannot be funded and must stay flagged rather than push Σ gross past 1000.
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.1").
+			WithMinInitialInvestment("150", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.9").
+			WithMinInitialInvestment("900", "0").ProtectFromZeroOut().Build()).
+		WithHolding("Z", "1000", "1").
+		Build()
+	result := ProcessInvestment(goal, 2, 4, Options{})
+	got := byTicker(result)
+	if d := got["A"]; d.Value != "100.00" || errorCode(d) != models.CodeMinInvestmentViolation {
+		t.Errorf("A = %s (%s), want 100.00 flagged %s", d.Value, errorCode(d), models.CodeMinInvestmentViolation)
+	}
+	if d := got["B"]; d.Value != "900.00" || d.Error != nil {
+		t.Errorf("B = %s (%s), want 900.00 with no error", d.Value, errorCode(d))
+	}
+	if total := sumValues(result); !total.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Σ gross = %s, want 1000", total)
+	}
+}
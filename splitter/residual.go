@@ -0,0 +1,128 @@
+package splitter
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Residual policies accepted in Options.ResidualPolicy.
+const (
+	ResidualLargestRemainder = "largest_remainder"
+	ResidualRoundRobin       = "round_robin"
+	ResidualHighestWeight    = "highest_weight"
+)
+
+// distributeResidual adds residual to amounts[i] for i in idxs, in steps of unit, never
+// raising a product above ceiling(i), according to policy:
+//
+//   - largest_remainder (default): one unit at a time, cycling through idxs in the
+//     order given (the repair step passes them highest priority, cheapest bump first;
+//     redemptions pass them largest truncation remainder first).
+//   - round_robin: one unit at a time, cycling through idxs in ascending model weight.
+//   - highest_weight: as much as possible to the product with the largest model weight,
+//     then the next largest, and so on.
+//
+// weights[i] is the model weight of product i. It returns the part of residual that no
+// product had room for.
+func distributeResidual(amounts []decimal.Decimal, idxs []int, residual, unit decimal.Decimal, weights []decimal.Decimal, policy string, ceiling func(i int) decimal.Decimal) decimal.Decimal {
+	if len(idxs) == 0 || !residual.IsPositive() {
+		return decimal.Max(residual, decimal.Zero)
+	}
+	weight := func(i int) decimal.Decimal { return weights[i] }
+	units := residual.Div(unit).Ceil()
+	headroom := func(i int) decimal.Decimal {
+		return decimal.Max(ceiling(i).Sub(amounts[i]).Div(unit).Floor(), decimal.Zero)
+	}
+
+	sorted := make([]int, len(idxs))
+	copy(sorted, idxs)
+	switch policy {
+	case ResidualHighestWeight:
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return weight(sorted[a]).GreaterThan(weight(sorted[b]))
+		})
+		for _, i := range sorted {
+			give := decimal.Min(headroom(i), units)
+			amounts[i] = amounts[i].Add(give.Mul(unit))
+			units = units.Sub(give)
+		}
+		return units.Mul(unit)
+	case ResidualRoundRobin:
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return weight(sorted[a]).LessThan(weight(sorted[b]))
+		})
+	}
+
+	rooms := make([]decimal.Decimal, len(sorted))
+	for k, i := range sorted {
+		rooms[k] = headroom(i)
+	}
+	given := decimal.Zero
+	for k, share := range cyclicShares(rooms, units) {
+		amounts[sorted[k]] = amounts[sorted[k]].Add(share.Mul(unit))
+		given = given.Add(share)
+	}
+	return units.Sub(given).Mul(unit)
+}
+
+// takeResidual removes residual from amounts[i] for i in idxs, in steps of unit: one unit
+// from each product per round, in the order given, never taking a product below floor(i).
+// It stops once the residual is covered or no product has a unit of headroom left.
+func takeResidual(amounts []decimal.Decimal, idxs []int, floor func(i int) decimal.Decimal, residual, unit decimal.Decimal) {
+	if len(idxs) == 0 || !residual.IsPositive() {
+		return
+	}
+	rooms := make([]decimal.Decimal, len(idxs))
+	for k, i := range idxs {
+		rooms[k] = decimal.Max(amounts[i].Sub(floor(i)).Div(unit).Floor(), decimal.Zero)
+	}
+	for k, share := range cyclicShares(rooms, residual.Div(unit).Ceil()) {
+		amounts[idxs[k]] = amounts[idxs[k]].Sub(share.Mul(unit))
+	}
+}
+
+// cyclicShares hands out units one at a time, cycling through the products in order and
+// skipping any whose headroom (in whole units) is used up, and returns how many units each
+// product received. It stops once every unit is handed out or no product has headroom left.
+//
+// The number of full rounds is found by binary search rather than by cycling, so it runs
+// in O(n log units).
+func cyclicShares(headroom []decimal.Decimal, units decimal.Decimal) []decimal.Decimal {
+	capped := make([]decimal.Decimal, len(headroom))
+	for k, h := range headroom {
+		capped[k] = decimal.Min(h, units)
+	}
+	takenAfter := func(rounds decimal.Decimal) decimal.Decimal {
+		total := decimal.Zero
+		for _, h := range capped {
+			total = total.Add(decimal.Min(h, rounds))
+		}
+		return total
+	}
+
+	// Largest number of full rounds whose total does not exceed the units available.
+	lo, hi := decimal.Zero, units
+	two := decimal.NewFromInt(2)
+	for lo.LessThan(hi) {
+		mid := lo.Add(hi).Add(decimal.NewFromInt(1)).Div(two).Floor()
+		if takenAfter(mid).LessThanOrEqual(units) {
+			lo = mid
+		} else {
+			hi = mid.Sub(decimal.NewFromInt(1))
+		}
+	}
+	rounds := lo
+
+	// The partial round: one more unit to each product that still has headroom, in order.
+	shares := make([]decimal.Decimal, len(capped))
+	left := units.Sub(takenAfter(rounds))
+	for k, h := range capped {
+		shares[k] = decimal.Min(h, rounds)
+		if left.IsPositive() && h.GreaterThan(rounds) {
+			shares[k] = shares[k].Add(decimal.NewFromInt(1))
+			left = left.Sub(decimal.NewFromInt(1))
+		}
+	}
+	return shares
+}
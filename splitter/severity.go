@@ -0,0 +1,73 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Severities of a trade error.
+const (
+	// SeverityError marks a hard block, such as a custodian's minimum: strict mode fails the
+	// request on it. It is the default for every code.
+	SeverityError = "error"
+	// SeverityWarning marks a soft rule the trade may be placed in spite of.
+	SeverityWarning = "warning"
+)
+
+// ValidSeverity reports whether s, ignoring case and surrounding space, is a severity.
+func ValidSeverity(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case SeverityError, SeverityWarning:
+		return true
+	}
+	return false
+}
+
+// ApplySeverities returns result with Severity set on every trade error: the severity
+// overrides gives its code, with codes matched ignoring case, or SeverityError.
+func ApplySeverities(result models.GoalResult, overrides map[string]string) models.GoalResult {
+	severities := make(map[models.Code]string, len(overrides))
+	for code, s := range overrides {
+		severities[models.Code(strings.ToUpper(strings.TrimSpace(code)))] = strings.ToLower(strings.TrimSpace(s))
+	}
+	classify := func(e models.TradeError) models.TradeError {
+		e.Severity = SeverityError
+		if s, ok := severities[e.Code]; ok {
+			e.Severity = s
+		}
+		return e
+	}
+
+	details := make([]models.TransactionDetail, len(result.TransactionDetails))
+	for i, d := range result.TransactionDetails {
+		if d.Error != nil {
+			e := classify(*d.Error)
+			d.Error = &e
+		}
+		if d.Errors != nil {
+			errs := make([]models.TradeError, len(d.Errors))
+			for j, e := range d.Errors {
+				errs[j] = classify(e)
+			}
+			d.Errors = errs
+		}
+		details[i] = d
+	}
+	result.TransactionDetails = details
+	return result
+}
+
+// BlockingError returns the first error of d with SeverityError, or nil when d has none.
+func BlockingError(d models.TransactionDetail) *models.TradeError {
+	errs := d.Errors
+	if len(errs) == 0 && d.Error != nil {
+		errs = []models.TradeError{*d.Error}
+	}
+	for _, e := range errs {
+		if e.Severity != SeverityWarning {
+			return &e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Reasons SkippedProducts gives for a product with no transaction detail at all.
+const (
+	SkipZeroWeight = "ZERO_WEIGHT"  // investment: the model item has a zero weight
+	SkipNotInModel = "NOT_IN_MODEL" // investment: the holding is outside the model
+	SkipNotHeld    = "NOT_HELD"     // redemption: the model item has nothing held to sell
+	SkipKept       = "KEPT"         // redemption: the holding is kept instead of sold off
+)
+
+// SkippedProducts returns every model item and holding of goal that result gives no trade
+// for a structural reason, in model order and then holding order, once per ticker. A
+// product reported as a zero trade with an error is skipped for that error; one with no
+// transaction detail at all is skipped with SkipZeroWeight or SkipNotInModel on an
+// investment, and SkipNotHeld or SkipKept on a redemption. Zero trades without an error were
+// computed as zero, and are not skipped.
+func SkippedProducts(goal models.Goal, result models.GoalResult) []models.SkippedProduct {
+	redemption := strings.EqualFold(strings.TrimSpace(goal.OrderType), "redemption")
+	traded := make(map[string]bool)
+	dropped := make(map[string]*models.TradeError)
+	for _, d := range result.TransactionDetails {
+		if d.Direction == DirCashOut {
+			continue
+		}
+		if value, _ := decimal.NewFromString(d.Value); value.IsZero() && d.Error != nil {
+			if _, ok := dropped[d.Ticker]; !ok {
+				dropped[d.Ticker] = d.Error
+			}
+			continue
+		}
+		traded[d.Ticker] = true
+	}
+	weights := make(map[string]decimal.Decimal)
+	for _, mp := range goal.ModelPortfolioDetails {
+		weights[mp.Ticker], _ = decimal.NewFromString(mp.Weight)
+	}
+	held, kept := make(map[string]bool), make(map[string]bool)
+	for _, h := range goal.GoalDetails {
+		if value, _ := decimal.NewFromString(h.Value); value.IsPositive() {
+			held[h.Ticker] = true
+		}
+		kept[h.Ticker] = kept[h.Ticker] || h.Keep || goal.KeepUnmodeled
+	}
+
+	var out []models.SkippedProduct
+	seen := make(map[string]bool)
+	skip := func(ticker, code, message string) {
+		seen[ticker] = true
+		out = append(out, models.SkippedProduct{Ticker: ticker, Code: code, Message: message})
+	}
+	check := func(ticker string) {
+		if seen[ticker] || traded[ticker] {
+			return
+		}
+		_, inModel := weights[ticker]
+		switch {
+		case dropped[ticker] != nil:
+			skip(ticker, string(dropped[ticker].Code), dropped[ticker].Message)
+		case !redemption && inModel && weights[ticker].IsZero():
+			skip(ticker, SkipZeroWeight, "Not traded because its model weight is zero")
+		case !redemption && !inModel:
+			skip(ticker, SkipNotInModel, "Not traded because it is not in the model portfolio")
+		case redemption && !held[ticker]:
+			skip(ticker, SkipNotHeld, "Not sold because nothing of it is held")
+		case redemption && kept[ticker] && weights[ticker].IsZero() && !goal.CashOut:
+			skip(ticker, SkipKept, "Not sold because the holding is kept")
+		}
+	}
+	for _, mp := range goal.ModelPortfolioDetails {
+		check(mp.Ticker)
+	}
+	for _, h := range goal.GoalDetails {
+		check(h.Ticker)
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// FlagStalePrices flags every trade in result whose model item has a priceDate more than
+// maxAgeDays days before today with models.CodeStalePrice. The trade is kept as allocated, like a
+// minimum violation, and a trade already flagged keeps its error, with the flag added after
+// it in Errors. Dates are compared as calendar days in UTC; priceDate must already have
+// been validated as YYYY-MM-DD.
+func FlagStalePrices(goal models.Goal, result models.GoalResult, maxAgeDays int, today time.Time) models.GoalResult {
+	today = today.UTC().Truncate(24 * time.Hour)
+	stale := make(map[string]string)
+	for _, mp := range goal.ModelPortfolioDetails {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(mp.PriceDate))
+		if err != nil {
+			continue
+		}
+		if int(today.Sub(date).Hours()/24) > maxAgeDays {
+			stale[mp.Ticker] = mp.PriceDate
+		}
+	}
+	if len(stale) == 0 {
+		return result
+	}
+	details := make([]models.TransactionDetail, len(result.TransactionDetails))
+	for i, d := range result.TransactionDetails {
+		if date, ok := stale[d.Ticker]; ok {
+			d = withErrors(d, models.TradeError{
+				Message: fmt.Sprintf("Price dated %s is older than maxPriceAgeDays (%d)", date, maxAgeDays),
+				Code:    models.CodeStalePrice,
+			})
+		}
+		details[i] = d
+	}
+	result.TransactionDetails = details
+	return result
+}
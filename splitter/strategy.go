@@ -0,0 +1,110 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Allocation strategies accepted in Options.AllocationStrategy and Goal.AllocationStrategy.
+const (
+	StrategyShortfall = "shortfall"
+	StrategyProRata   = "pro_rata"
+	StrategyEqual     = "equal"
+)
+
+// allocationStrategy computes the ideal net amount each product should receive.
+// ProcessInvestment feeds the ideals through the shared fee adjustment, capping,
+// minimum-trade and repair machinery, so a strategy only decides the target split.
+type allocationStrategy interface {
+	ideals(allocs []productAlloc, orderAmount, postTotal decimal.Decimal) []decimal.Decimal
+}
+
+// shortfallStrategy favours the products furthest below their model weight:
+// ideal_i = max(0, w_i × postTotal − current_i). Suited to rebalancing deposits: every
+// deposit moves the goal toward the model, at the cost of concentrating small deposits in
+// a few products, which makes minimum violations more likely.
+type shortfallStrategy struct{}
+
+func (shortfallStrategy) ideals(allocs []productAlloc, _, postTotal decimal.Decimal) []decimal.Decimal {
+	out := make([]decimal.Decimal, len(allocs))
+	for i, a := range allocs {
+		out[i] = decimal.Max(a.weight.Mul(postTotal).Sub(a.current), decimal.Zero)
+	}
+	return out
+}
+
+// proRataStrategy ignores current holdings: ideal_i = w_i / Σw × orderAmount.
+// Suited to funding a brand-new goal, and to callers that want every deposit split the
+// same way. The trade-off is that drift is never corrected: a goal that is off-model stays
+// off-model by the same amount. Holdings are still read, since they decide whether a
+// product's initial or top-up minimums apply.
+type proRataStrategy struct{}
+
+func (proRataStrategy) ideals(allocs []productAlloc, orderAmount, _ decimal.Decimal) []decimal.Decimal {
+	totalWeight := decimal.Zero
+	for _, a := range allocs {
+		totalWeight = totalWeight.Add(a.weight)
+	}
+	out := make([]decimal.Decimal, len(allocs))
+	if !totalWeight.IsPositive() {
+		return out
+	}
+	for i, a := range allocs {
+		out[i] = div(a.weight, totalWeight).Mul(orderAmount)
+	}
+	return out
+}
+
+// equalStrategy splits the order equally across every product with weight > 0:
+// ideal_i = orderAmount / N. Used for promotions.
+type equalStrategy struct{}
+
+func (equalStrategy) ideals(allocs []productAlloc, orderAmount, _ decimal.Decimal) []decimal.Decimal {
+	out := make([]decimal.Decimal, len(allocs))
+	if len(allocs) == 0 {
+		return out
+	}
+	share := div(orderAmount, decimal.NewFromInt(int64(len(allocs))))
+	for i := range out {
+		out[i] = share
+	}
+	return out
+}
+
+// strategyFor resolves the allocation strategy for a goal: the goal-level value when
+// present, otherwise the request-level one, defaulting to shortfall.
+func strategyFor(goal models.Goal, opts Options) allocationStrategy {
+	name := opts.AllocationStrategy
+	if strings.TrimSpace(goal.AllocationStrategy) != "" {
+		name = goal.AllocationStrategy
+	}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case StrategyProRata:
+		return proRataStrategy{}
+	case StrategyEqual:
+		return equalStrategy{}
+	default:
+		return shortfallStrategy{}
+	}
+}
+
+// ValidStrategy reports whether name is an accepted allocation strategy (empty means default).
+func ValidStrategy(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", StrategyShortfall, StrategyProRata, StrategyEqual:
+		return true
+	}
+	return false
+}
+
+// ValidAllocationMode reports whether name is an accepted allocation mode, the alias of
+// an allocation strategy that offers only shortfall and pro_rata (empty means default).
+func ValidAllocationMode(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", StrategyShortfall, StrategyProRata:
+		return true
+	}
+	return false
+}
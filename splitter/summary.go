@@ -0,0 +1,91 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// CountTrades returns how many of details place a trade (a non-zero value other than a cash
+// drawdown) and how many are flagged with an error.
+func CountTrades(details []models.TransactionDetail) (trades, errors int) {
+	for _, d := range details {
+		if v, _ := decimal.NewFromString(d.Value); !v.IsZero() && d.Direction != DirCashOut {
+			trades++
+		}
+		if d.Error != nil {
+			errors++
+		}
+	}
+	return trades, errors
+}
+
+// Summarize totals result's trades on the goal's own decimals, at amountPrec. The
+// estimated fee on a trade is value × its fee, rounded up: transactionFee on a BUY and
+// redemptionFee, or transactionFee without one, on a SELL, whose fee is value − NetProceeds
+// when the sell was sized on those. TotalGrossValue and TotalEstimatedFees cover every BUY
+// and SELL, and TotalNetValue is the one less the other.
+//
+// UnallocatedAmount is what the trades leave of the order, never negative: for an
+// investment, orderAmount less the BUYs; for a redemption, orderAmount, or
+// effectiveOrderAmount when it was not sent, less the proceeds on the basis the order was
+// given in (gross, NetProceeds or NetAfterTax), cash drawn included. Redemptions also report
+// TotalGrossProceeds, their SELLs plus any cash drawn, and TotalNetProceeds, those less the
+// fees on the SELLs.
+func Summarize(goal models.Goal, result models.GoalResult, amountPrec int, opts Options) *models.GoalSummary {
+	prec := int32(amountPrec)
+	buyFees, sellFees := make(map[string]decimal.Decimal), redemptionFees(goal)
+	for _, mp := range goal.ModelPortfolioDetails {
+		buyFees[mp.Ticker], _ = decimal.NewFromString(mp.TransactionFee)
+	}
+	redemption := strings.EqualFold(strings.TrimSpace(goal.OrderType), "redemption")
+
+	gross, fees, sold, sellFeeTotal, cash, placed := decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
+	for _, d := range result.TransactionDetails {
+		value, _ := decimal.NewFromString(d.Value)
+		switch d.Direction {
+		case "BUY":
+			fee := decimal.Min(ceilToPrec(value.Mul(buyFees[d.Ticker]), prec), value)
+			gross, fees = gross.Add(value), fees.Add(fee)
+			if !redemption {
+				placed = placed.Add(value)
+			}
+		case "SELL":
+			fee := decimal.Min(ceilToPrec(value.Mul(sellFees[d.Ticker]), prec), value)
+			if net, err := decimal.NewFromString(d.NetProceeds); err == nil {
+				fee = value.Sub(net)
+			}
+			gross, fees = gross.Add(value), fees.Add(fee)
+			sold, sellFeeTotal = sold.Add(value), sellFeeTotal.Add(fee)
+			switch opts.ProceedsMode {
+			case ProceedsNet:
+				value, _ = decimal.NewFromString(d.NetProceeds)
+			case ProceedsNetAfterTax:
+				value, _ = decimal.NewFromString(d.NetAfterTax)
+			}
+			placed = placed.Add(value)
+		case DirCashOut:
+			cash = cash.Add(value)
+			placed = placed.Add(value)
+		}
+	}
+
+	order, err := decimal.NewFromString(strings.TrimSpace(goal.OrderAmount))
+	if err != nil {
+		order, _ = decimal.NewFromString(result.EffectiveOrderAmount)
+	}
+	summary := &models.GoalSummary{
+		TotalGrossValue:    gross.StringFixed(prec),
+		TotalNetValue:      gross.Sub(fees).StringFixed(prec),
+		TotalEstimatedFees: fees.StringFixed(prec),
+		UnallocatedAmount:  decimal.Max(order.Sub(placed), decimal.Zero).StringFixed(prec),
+		TradeCount:         result.TradeCount,
+		ViolationCount:     len(result.ViolationSummary),
+	}
+	if redemption {
+		summary.TotalGrossProceeds = sold.Add(cash).StringFixed(prec)
+		summary.TotalNetProceeds = sold.Sub(sellFeeTotal).Add(cash).StringFixed(prec)
+	}
+	return summary
+}
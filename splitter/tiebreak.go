@@ -0,0 +1,39 @@
+package splitter
+
+import (
+	"strings"
+)
+
+// Tie-break orders accepted in Options.TieBreak. They settle ties between repair-step
+// zero-out candidates that share the same priority and required gross.
+const (
+	TieBreakModelOrder    = "model_order"    // earlier in modelPortfolioDetails first (default)
+	TieBreakTicker        = "ticker"         // ascending ticker, then model order
+	TieBreakLargestWeight = "largest_weight" // larger model weight first, then model order
+)
+
+// ValidTieBreak reports whether name is an accepted tie-break order (empty means default).
+func ValidTieBreak(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", TieBreakModelOrder, TieBreakTicker, TieBreakLargestWeight:
+		return true
+	}
+	return false
+}
+
+// tieBreakLess orders alloc indices i and j when every primary sort key is equal. The
+// final key is always the model order index, so the result never depends on the sort
+// algorithm and identical requests always repair identically.
+func tieBreakLess(allocs []productAlloc, i, j int, policy string) bool {
+	switch policy {
+	case TieBreakTicker:
+		if ti, tj := allocs[i].mp.Ticker, allocs[j].mp.Ticker; ti != tj {
+			return ti < tj
+		}
+	case TieBreakLargestWeight:
+		if !allocs[i].weight.Equal(allocs[j].weight) {
+			return allocs[i].weight.GreaterThan(allocs[j].weight)
+		}
+	}
+	return i < j
+}
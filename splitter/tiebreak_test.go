@@ -0,0 +1,57 @@
+package splitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestTieBreak(t *testing.T) {
+	// V falls 50 short of its minimum and the only way to fund it is to zero one of M and
+	// K, which tie on priority and required gross. W cannot be zeroed and has no slack.
+	goal := testhelpers.NewGoalBuilder("g1").
+		WithModelItem(testhelpers.NewModelItemBuilder("V").WithWeight("0.1").
+			WithMinInitialInvestment("150", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("M").WithWeight("0.2").
+			WithMinInitialInvestment("200", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("K").WithWeight("0.2").
+			WithMinInitialInvestment("200", "0").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("W").WithWeight("0.5").
+			WithMinInitialInvestment("500", "0").ProtectFromZeroOut().Build()).
+		WithHolding("Z", "1000", "1").
+		Build()
+	tests := []struct {
+		tieBreak string
+		zeroed   string
+	}{
+		{"", "M"},
+		{TieBreakModelOrder, "M"},
+		{TieBreakTicker, "K"},
+		{TieBreakLargestWeight, "M"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tieBreak, func(t *testing.T) {
+			opts := Options{TieBreak: tt.tieBreak}
+			result := ProcessInvestment(goal, 2, 4, opts)
+			got := byTicker(result)
+			if d := got[tt.zeroed]; d.Value != "0.00" {
+				t.Errorf("%s = %s, want it zeroed", tt.zeroed, d.Value)
+			}
+			if d := got["V"]; d.Error != nil {
+				t.Errorf("V flagged %s, want it fixed", errorCode(d))
+			}
+			want, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for run := 1; run < 100; run++ {
+				again, _ := json.Marshal(ProcessInvestment(goal, 2, 4, opts))
+				if !bytes.Equal(again, want) {
+					t.Fatalf("run %d differs:\n got %s\nwant %s", run, again, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// explainSnapshot copies grossAmounts so that the figures handed to the repair step can
+// be reported after it runs. It returns nil, and allocates nothing, unless opts.Explain.
+func explainSnapshot(grossAmounts []decimal.Decimal, opts Options) []decimal.Decimal {
+	if !opts.Explain {
+		return nil
+	}
+	return append([]decimal.Decimal(nil), grossAmounts...)
+}
+
+// investmentTrace reports how a's final gross was derived: its ideal, the fee-adjusted
+// gross before scaling, the truncated gross handed to the repair step, and what the
+// repair step changed.
+func investmentTrace(a productAlloc, preRepair, final decimal.Decimal, amountPrec int) *models.Trace {
+	fee, _ := decimal.NewFromString(a.mp.TransactionFee)
+	prec := int32(amountPrec)
+	t := &models.Trace{
+		CurrentValue:     a.current.StringFixed(prec),
+		Ideal:            a.ideal.String(),
+		FeeAdjustedGross: div(a.ideal, decimal.NewFromInt(1).Sub(fee)).String(),
+		Final:            final.StringFixed(prec),
+	}
+	setRepairAdjustment(t, preRepair, final, amountPrec)
+	return t
+}
+
+// setRepairAdjustment records the amount handed to the repair step and what the step did
+// to it.
+func setRepairAdjustment(t *models.Trace, preRepair, final decimal.Decimal, amountPrec int) {
+	prec := int32(amountPrec)
+	t.GrossBeforeRepair = preRepair.StringFixed(prec)
+	delta := final.Sub(preRepair)
+	switch {
+	case delta.IsZero():
+		return
+	case final.IsZero():
+		t.RepairAction = "zeroed"
+	case delta.IsPositive():
+		t.RepairAction = "bumped"
+	default:
+		t.RepairAction = "reduced"
+	}
+	t.RepairAdjustment = delta.StringFixed(prec)
+}
+
+// redemptionTrace reports a redemption trade sold in the given phase ("1" or "2").
+// Phase 2 callers add the overweight amount and the repair figures themselves.
+func redemptionTrace(phase string, current, final decimal.Decimal, amountPrec int) *models.Trace {
+	prec := int32(amountPrec)
+	return &models.Trace{
+		Phase:        phase,
+		CurrentValue: current.StringFixed(prec),
+		Final:        final.StringFixed(prec),
+	}
+}
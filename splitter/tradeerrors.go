@@ -0,0 +1,55 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// withErrors returns d flagged with errs after any errors it already has, in the order the
+// rules were checked. Error is always the first of Errors.
+func withErrors(d models.TransactionDetail, errs ...models.TradeError) models.TransactionDetail {
+	if len(errs) == 0 {
+		return d
+	}
+	d.Errors = append(append([]models.TradeError(nil), d.Errors...), errs...)
+	first := d.Errors[0]
+	d.Error = &first
+	return d
+}
+
+// minimumCheck collects the minimums a trade breaches, each with the figures it was checked
+// on.
+type minimumCheck struct {
+	amountPrec, unitPrec int
+	errs                 []models.TradeError
+}
+
+// amount records a breach with code and message when actual is below required.
+func (c *minimumCheck) amount(actual, required decimal.Decimal, code models.Code, message string) {
+	if !actual.LessThan(required) {
+		return
+	}
+	prec := int32(c.amountPrec)
+	c.errs = append(c.errs, models.TradeError{
+		Message:        message,
+		Code:           code,
+		RequiredAmount: required.StringFixed(prec),
+		ActualAmount:   actual.Truncate(prec).StringFixed(prec),
+		Shortfall:      ceilToPrec(required.Sub(actual), prec).StringFixed(prec),
+	})
+}
+
+// units records a breach with code and message when actual units are below required.
+func (c *minimumCheck) units(actual, required decimal.Decimal, code models.Code, message string) {
+	if !actual.LessThan(required) {
+		return
+	}
+	prec := int32(c.unitPrec)
+	c.errs = append(c.errs, models.TradeError{
+		Message:       message,
+		Code:          code,
+		RequiredUnits: required.StringFixed(prec),
+		ActualUnits:   actual.Truncate(prec).StringFixed(prec),
+		Shortfall:     ceilToPrec(required.Sub(actual), prec).StringFixed(prec),
+	})
+}
@@ -0,0 +1,66 @@
+package splitter
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// DefaultWeightPrec is the number of decimal places of the weights AddTradeWeights reports
+// when the request does not set weightDecimalPrecision.
+const DefaultWeightPrec = weightDisplayPrec
+
+// AddTradeWeights returns result with ModelWeight, PreTradeWeight, PostTradeWeight and
+// Drift set on every transaction detail, rounded to weightPrec decimal places.
+//
+// The model weight is the product's weight as the goal was split on it, after
+// normalizeWeights or shrinkageCoefficient, and 0 for a product outside the model or for
+// cash. The pre- and post-trade weights are the product's share of the goal once pending
+// orders settle, before and after result's trades, cash included (see projectTrades); they
+// are 0 when the goal holds nothing. Drift is the post-trade weight less the model weight.
+func AddTradeWeights(goal models.Goal, result models.GoalResult, amountPrec, unitPrec, weightPrec int, opts Options) models.GoalResult {
+	model := make(map[string]decimal.Decimal)
+	for i, w := range modelWeights(goal, opts) {
+		model[goal.ModelPortfolioDetails[i].Ticker] = w
+	}
+	positions := projectTrades(goal, result, amountPrec, unitPrec)
+	pre, post := make(map[string]decimal.Decimal), make(map[string]decimal.Decimal)
+	preTotal, postTotal := decimal.Zero, decimal.Zero
+	for _, p := range positions {
+		preTotal, postTotal = preTotal.Add(p.preValue), postTotal.Add(p.value)
+	}
+	// Cash is kept apart from any product that happens to be named CashTicker.
+	key := func(ticker string, cash bool) string {
+		if cash {
+			return "\x00" + ticker
+		}
+		return ticker
+	}
+	for _, p := range positions {
+		k := key(p.ticker, p.cash)
+		pre[k], post[k] = decimal.Zero, decimal.Zero
+		if preTotal.IsPositive() {
+			pre[k] = div(p.preValue, preTotal)
+		}
+		if postTotal.IsPositive() {
+			post[k] = div(p.value, postTotal)
+		}
+	}
+
+	prec := int32(weightPrec)
+	details := make([]models.TransactionDetail, len(result.TransactionDetails))
+	for i, d := range result.TransactionDetails {
+		cash := d.Direction == DirCashOut
+		modelWeight := model[d.Ticker]
+		if cash {
+			modelWeight = decimal.Zero
+		}
+		k := key(d.Ticker, cash)
+		d.ModelWeight = modelWeight.StringFixed(prec)
+		d.PreTradeWeight = pre[k].StringFixed(prec)
+		d.PostTradeWeight = post[k].StringFixed(prec)
+		d.Drift = post[k].Sub(modelWeight).StringFixed(prec)
+		details[i] = d
+	}
+	result.TransactionDetails = details
+	return result
+}
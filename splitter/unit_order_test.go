@@ -0,0 +1,56 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+func TestUnitOrderAmount(t *testing.T) {
+	// 30 units worth 1000 in all: an average of 33.333… a unit.
+	holdings := map[string]models.Holding{
+		"A": {Ticker: "A", Units: "10", Value: "200"},
+		"B": {Ticker: "B", Units: "20", Value: "800"},
+	}
+	vTotal := decimal.NewFromInt(1000)
+	tests := []struct {
+		name      string
+		orderUnit string
+		holdings  map[string]models.Holding
+		want      string
+	}{
+		{"at the average price", "3", holdings, "100"},
+		{"truncated to amountPrec", "1", holdings, "33.33"},
+		{"every unit held", "30", holdings, "1000"},
+		{"more than held", "45", holdings, "1000"},
+		{"fractional units", "0.015", holdings, "0.5"},
+		{"no units held", "5", map[string]models.Holding{"A": {Ticker: "A", Units: "0", Value: "0"}}, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unitOrderAmount(tt.orderUnit, tt.holdings, vTotal, 2)
+			if want, _ := decimal.NewFromString(tt.want); !got.Equal(want) {
+				t.Errorf("unitOrderAmount(%s) = %s, want %s", tt.orderUnit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedemptionByUnits(t *testing.T) {
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithOrderUnit("3").
+		WithTicker("A", "0.2", "20").
+		WithTicker("B", "0.8", "40").
+		WithHolding("A", "10", "20").
+		WithHolding("B", "20", "40").
+		Build()
+	result := ProcessRedemption(goal, 2, 4, Options{})
+	if result.EffectiveOrderAmount != "100.00" {
+		t.Errorf("effectiveOrderAmount = %q, want 100.00", result.EffectiveOrderAmount)
+	}
+	if total := sumValues(result); total.String() != "100" {
+		t.Errorf("Σ sells = %s, want 100", total)
+	}
+}
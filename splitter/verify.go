@@ -0,0 +1,194 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// Invariant codes reported by VerifyResult.
+const (
+	InvariantTotal          = "TOTAL_MISMATCH"
+	InvariantNegative       = "NEGATIVE_AMOUNT"
+	InvariantUnitsValue     = "UNITS_EXCEED_VALUE"
+	InvariantValueUnits     = "VALUE_EXCEEDS_UNITS"
+	InvariantOversell       = "OVERSELL"
+	InvariantFalseViolation = "FALSE_VIOLATION"
+	InvariantMissedMinimum  = "MISSED_VIOLATION"
+)
+
+// VerifyResult checks result, as produced for goal by ProcessInvestment or
+// ProcessRedemption, against the splitter's post-conditions:
+//
+//   - Σ value never exceeds the order amount (effectiveOrderAmount for capped redemptions,
+//     less any unfulfilledAmount) and falls short of it by at most one smallest unit per trade, the most that
+//     truncating each trade can lose. Skipped when the goal has an error, or when nothing
+//     was placed and the trades are flagged with the reason. For a rebuilt Big Redemption,
+//     the SELL legs may exceed the order and the BUY legs must not exceed that excess.
+//   - No value or units is negative.
+//   - units × marketPrice does not exceed value (in local currency) by more than one
+//     smallest unit, nor fall short of it by more than the price of one smallest unit of
+//     units plus one smallest unit, except on full redemptions, which sell every unit held.
+//   - No sell exceeds the value or the units that can be sold of its holding.
+//   - Every trade flagged with a minimum violation really breaches that minimum and every
+//     unflagged trade meets its minimums. Flagged trades with a zero value were dropped
+//     from the allocation and are not rechecked, and trades flagged models.CodeStalePrice or
+//     models.CodeBlockedUnitsLimit may meet every minimum.
+//
+// goal must be the goal as sent, before pending orders are applied, and opts the options
+// it was split with. Redemptions split in net proceeds are checked on their NetProceeds, or
+// their NetAfterTax under ProceedsNetAfterTax.
+func VerifyResult(goal models.Goal, result models.GoalResult, amountPrec, unitPrec int, opts Options) models.SelfCheck {
+	var v []models.InvariantViolation
+	report := func(invariant, ticker, format string, args ...any) {
+		v = append(v, models.InvariantViolation{Invariant: invariant, Ticker: ticker, Message: fmt.Sprintf(format, args...)})
+	}
+	unit := decimal.New(1, -int32(amountPrec))
+	unitStep := decimal.New(1, -int32(unitPrec))
+	redemption := strings.EqualFold(goal.OrderType, "redemption")
+	if redemption {
+		goal = feeAdjustedGoal(goal, opts, amountPrec)
+		if opts.ProceedsMode == ProceedsNet || opts.ProceedsMode == ProceedsNetAfterTax {
+			details := make([]models.TransactionDetail, len(result.TransactionDetails))
+			for i, d := range result.TransactionDetails {
+				if d.Direction == DirCashOut {
+					details[i] = d
+					continue
+				}
+				d.Value = d.NetProceeds
+				if opts.ProceedsMode == ProceedsNetAfterTax {
+					d.Value = d.NetAfterTax
+				}
+				details[i] = d
+			}
+			result.TransactionDetails = details
+		}
+	}
+
+	orderAmount, _ := decimal.NewFromString(goal.OrderAmount)
+	if redemption && result.EffectiveOrderAmount != "" {
+		orderAmount, _ = decimal.NewFromString(result.EffectiveOrderAmount)
+	}
+	if unfulfilled, err := decimal.NewFromString(result.UnfulfilledAmount); err == nil {
+		orderAmount = orderAmount.Sub(unfulfilled)
+	}
+	total, bought := decimal.Zero, decimal.Zero
+	flagged, rebuilt := false, false
+	for _, d := range result.TransactionDetails {
+		value, _ := decimal.NewFromString(d.Value)
+		if redemption && d.Direction == "BUY" {
+			bought, rebuilt = bought.Add(value), true
+			continue
+		}
+		total = total.Add(value)
+		flagged = flagged || d.Error != nil
+	}
+	if result.Error == nil && !(total.IsZero() && flagged) {
+		tolerance := unit.Mul(decimal.NewFromInt(int64(len(result.TransactionDetails))))
+		// A rebuilt Big Redemption sells more than the order on purpose; only the shortfall
+		// is checked on the sells, and the buys must fit in what they raised beyond the order.
+		if (total.GreaterThan(orderAmount) && !rebuilt) || orderAmount.Sub(total).GreaterThan(tolerance) {
+			report(InvariantTotal, "", "sum of values %s does not match order amount %s", total.String(), orderAmount.String())
+		} else if bought.GreaterThan(total.Sub(orderAmount).Add(tolerance)) {
+			report(InvariantTotal, "", "buys of %s exceed the %s sold beyond order amount %s", bought.String(), total.Sub(orderAmount).String(), orderAmount.String())
+		}
+	}
+
+	modelMap := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		modelMap[mp.Ticker] = mp
+	}
+	holdings := goal.GoalDetails
+	if redemption {
+		holdings = sellableHoldings(holdings)
+	}
+	held := make(map[string]models.Holding)
+	for _, h := range holdings {
+		held[h.Ticker] = h
+	}
+	// The BUY leg of a rebuilt Big Redemption buys into the holdings left after the sells.
+	if rebuilt {
+		holdings = holdingsAfterSells(goal.GoalDetails, result.TransactionDetails)
+	}
+
+	for _, d := range result.TransactionDetails {
+		value, _ := decimal.NewFromString(d.Value)
+		units, _ := decimal.NewFromString(d.Units)
+		if value.IsNegative() || units.IsNegative() {
+			report(InvariantNegative, d.Ticker, "value %s or units %s is negative", d.Value, d.Units)
+			continue
+		}
+		// Cash drawn from the goal has no price or minimums to check.
+		if d.Direction == DirCashOut {
+			continue
+		}
+
+		mp, inModel := modelMap[d.Ticker]
+		h := held[d.Ticker]
+		sell := redemption && d.Direction == "SELL"
+		// Redemptions price model products (Phase 2) from the model and everything else
+		// (Phase 1) from the holding; investments always use the model.
+		priceStr, fx := unitPrice(d.Direction, mp, inModel, h), exchangeRate(mp)
+		if sell {
+			fx = decimal.NewFromInt(1)
+		}
+		// A sell of all the free units of a blocked holding sells them whole, like a full redemption.
+		free, freeUnits := freeHolding(h)
+		whole := sell && isBlocked(h) && value.GreaterThanOrEqual(free.Truncate(int32(amountPrec)))
+		if price, _ := decimal.NewFromString(priceStr); !d.FullRedemption && !whole && price.IsPositive() {
+			// Units are truncated, so they may fall short of value by up to one smallest unit
+			// of units at price; value is itself rounded by up to one smallest unit.
+			worth, local := units.Mul(price), value.Mul(fx)
+			if worth.GreaterThan(local.Add(unit)) {
+				report(InvariantUnitsValue, d.Ticker, "units %s × price %s exceeds value %s", d.Units, priceStr, d.Value)
+			} else if local.Sub(worth).GreaterThan(price.Mul(unitStep).Add(unit)) {
+				report(InvariantValueUnits, d.Ticker, "value %s exceeds units %s × price %s", d.Value, d.Units, priceStr)
+			}
+		}
+		if sell {
+			maxValue, maxUnits := free.Truncate(int32(amountPrec)), freeUnits.Truncate(int32(unitPrec))
+			if value.GreaterThan(maxValue) || units.GreaterThan(maxUnits) {
+				report(InvariantOversell, d.Ticker, "sells %s (%s units) but only %s (%s units) can be sold", d.Value, d.Units, maxValue, maxUnits)
+			}
+		}
+
+		if d.Error != nil && !value.IsPositive() {
+			continue
+		}
+		var want []models.TradeError
+		if sell {
+			minimums := models.ModelItem{
+				MinRedemptionAmt: h.MinRedemptionAmt, MinRedemptionUnits: h.MinRedemptionUnits,
+				MinHoldingAmt: h.MinHoldingAmt, MinHoldingUnits: h.MinHoldingUnits,
+			}
+			if inModel {
+				minimums = mp
+			}
+			if value.IsPositive() {
+				want = checkRedemptionMinimums(value, units, value.GreaterThanOrEqual(free.Truncate(int32(amountPrec))),
+					h.Value, h.Units,
+					minimums.MinRedemptionAmt, minimums.MinRedemptionUnits,
+					minimums.MinHoldingAmt, minimums.MinHoldingUnits,
+					amountPrec, unitPrec)
+			}
+		} else {
+			a := productAlloc{mp: mp}
+			for _, gh := range holdings {
+				if gh.Ticker == d.Ticker && (redemption || !opts.IgnoreCurrentHoldings) {
+					a.current, a.currentUnits = projectedValue(gh), projectedUnits(gh)
+					a.newPosition = a.newPosition || gh.ForceInitialMinimums
+				}
+			}
+			want = checkInvestmentMinimums(a, value, amountPrec, unitPrec)
+		}
+		switch {
+		case d.Error != nil && want == nil && d.Error.Code != models.CodeStalePrice && d.Error.Code != models.CodeBlockedUnitsLimit && (!sell || d.Error.Code != models.CodeConsolidated):
+			report(InvariantFalseViolation, d.Ticker, "flagged %s but value %s meets every minimum", d.Error.Code, d.Value)
+		case d.Error == nil && want != nil:
+			report(InvariantMissedMinimum, d.Ticker, "not flagged but value %s breaches %s", d.Value, want[0].Code)
+		}
+	}
+	return models.SelfCheck{Passed: len(v) == 0, Violations: v}
+}
@@ -0,0 +1,51 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/valentinpj/smart-splitter/models"
+	"github.com/valentinpj/smart-splitter/testhelpers"
+)
+
+// invariants returns the invariants VerifyResult reports for result.
+func invariants(goal models.Goal, result models.GoalResult) []string {
+	var got []string
+	for _, v := range VerifyResult(goal, result, 2, 4, Options{}).Violations {
+		got = append(got, v.Invariant)
+	}
+	return got
+}
+
+func TestVerifyResultCatchesOversellsAndUnitMismatches(t *testing.T) {
+	// A redemption of 300 from A (1500 held) and B (200 held, 20 units at 10).
+	goal := testhelpers.NewGoalBuilder("g1").AsRedemption().
+		WithOrderAmount("300").
+		WithModelItem(testhelpers.NewModelItemBuilder("A").WithWeight("0.9").Build()).
+		WithModelItem(testhelpers.NewModelItemBuilder("B").WithWeight("0.1").WithMarketPrice("10").Build()).
+		WithHolding("A", "1500", "1").
+		WithHolding("B", "20", "10").
+		Build()
+	sell := func(ticker, value, units string) models.TransactionDetail {
+		return models.TransactionDetail{Ticker: ticker, Direction: "SELL", Value: value, Units: units}
+	}
+	tests := []struct {
+		name string
+		a, b models.TransactionDetail
+		want string
+	}{
+		{"within the holding", sell("A", "100.00", "100.0000"), sell("B", "200.00", "20.0000"), ""},
+		{"value above the holding", sell("A", "99.99", "99.9900"), sell("B", "200.01", "20.0000"), InvariantOversell},
+		{"units above the holding", sell("A", "100.00", "100.0000"), sell("B", "200.00", "20.0001"), InvariantOversell},
+		{"units worth more than value", sell("A", "150.00", "150.0000"), sell("B", "150.00", "16.0000"), InvariantUnitsValue},
+		{"units worth less than value", sell("A", "150.00", "150.0000"), sell("B", "150.00", "14.0000"), InvariantValueUnits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := models.GoalResult{TransactionDetails: []models.TransactionDetail{tt.a, tt.b}}
+			got := invariants(goal, result)
+			if tt.want == "" && len(got) > 0 || tt.want != "" && (len(got) != 1 || got[0] != tt.want) {
+				t.Errorf("invariants = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// SummarizeViolations returns one record per transaction detail of result flagged with a
+// minimum violation (MIN_INVESTMENT_VIOLATION, MIN_TOPUP_VIOLATION, MIN_HOLDING_VIOLATION,
+// MIN_REDEMPTION_VIOLATION or BELOW_MIN_TRADE), in detail order. RequiredAmt is the minimum
+// amount the trade was checked against, as sent: the model item's, or for a holding outside
+// the model the holding's, and the goal's or request's minTradeAmt for BELOW_MIN_TRADE. It is
+// empty when the minimum was given in units only. AllocatedAmt is the trade's value.
+func SummarizeViolations(goal models.Goal, result models.GoalResult, opts Options) []models.ViolationRecord {
+	modelMap := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		modelMap[mp.Ticker] = mp
+	}
+	holdingMap := make(map[string]models.Holding)
+	for _, h := range goal.GoalDetails {
+		holdingMap[h.Ticker] = h
+	}
+
+	var out []models.ViolationRecord
+	for _, d := range result.TransactionDetails {
+		if d.Error == nil {
+			continue
+		}
+		mp, inModel := modelMap[d.Ticker]
+		if h, held := holdingMap[d.Ticker]; !inModel && held {
+			mp = models.ModelItem{
+				MinRedemptionAmt: h.MinRedemptionAmt, MinRedemptionUnits: h.MinRedemptionUnits,
+				MinHoldingAmt: h.MinHoldingAmt, MinHoldingUnits: h.MinHoldingUnits,
+			}
+		}
+		var required string
+		switch d.Error.Code {
+		case models.CodeMinInvestmentViolation:
+			required = mp.MinInitialInvestmentAmt
+		case models.CodeMinTopupViolation:
+			required = mp.MinTopupAmt
+		case models.CodeMinHoldingViolation:
+			required = mp.MinHoldingAmt
+		case models.CodeMinRedemptionViolation:
+			required = mp.MinRedemptionAmt
+		case models.CodeBelowMinTrade:
+			required = minTradeAmt(goal, opts).String()
+		default:
+			continue
+		}
+		out = append(out, models.ViolationRecord{
+			Ticker:       d.Ticker,
+			ErrorCode:    d.Error.Code,
+			RequiredAmt:  strings.TrimSpace(required),
+			AllocatedAmt: d.Value,
+		})
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// StalePriceWarningDays is how many days old a priceDate may be before TradeWarnings warns
+// about it.
+const StalePriceWarningDays = 7
+
+// highFeeWarningRate is the fee rate above which TradeWarnings warns about a trade.
+var highFeeWarningRate = decimal.New(5, -2)
+
+// TradeWarnings returns the warnings on result's trades of a non-zero value, in detail order:
+// models.CodeStalePrice for a trade whose model item has a priceDate more than staleDays
+// days before today, as calendar days in UTC, and models.CodeHighFee for one whose fee rate
+// exceeds 5%: transactionFee on a BUY, and redemptionFee or else transactionFee on a SELL.
+// A negative staleDays skips the price check. Neither changes the trades.
+func TradeWarnings(goal models.Goal, result models.GoalResult, staleDays int, today time.Time) []models.Warning {
+	model := make(map[string]models.ModelItem)
+	for _, mp := range goal.ModelPortfolioDetails {
+		model[mp.Ticker] = mp
+	}
+	sellFees := redemptionFees(goal)
+	today = today.UTC().Truncate(24 * time.Hour)
+
+	var out []models.Warning
+	for _, d := range result.TransactionDetails {
+		value, _ := decimal.NewFromString(d.Value)
+		if value.IsZero() || (d.Direction != "BUY" && d.Direction != "SELL") {
+			continue
+		}
+		mp := model[d.Ticker]
+		if date, err := time.Parse("2006-01-02", strings.TrimSpace(mp.PriceDate)); err == nil && staleDays >= 0 {
+			if days := int(today.Sub(date).Hours() / 24); days > staleDays {
+				out = append(out, models.Warning{
+					Code:    models.CodeStalePrice,
+					Message: fmt.Sprintf("price dated %s is %d days old", mp.PriceDate, days),
+					Ticker:  d.Ticker,
+				})
+			}
+		}
+		fee := sellFees[d.Ticker]
+		if d.Direction == "BUY" {
+			fee, _ = decimal.NewFromString(mp.TransactionFee)
+		}
+		if fee.GreaterThan(highFeeWarningRate) {
+			out = append(out, models.Warning{
+				Code:    models.CodeHighFee,
+				Message: fmt.Sprintf("fee of %s takes more than 5%% of the trade", fee.String()),
+				Ticker:  d.Ticker,
+			})
+		}
+	}
+	return out
+}
+
+// goalWarnings runs the soft checks on goal, as it is split: problems that validation lets
+// through but the caller should know about. A cash-out goal, which is split without its
+// model, is not checked against it, and neither are kept holdings.
+func goalWarnings(goal models.Goal, opts Options) []models.Warning {
+	if goal.CashOut {
+		return nil
+	}
+	var out []models.Warning
+	total := decimal.Zero
+	inModel := make(map[string]bool)
+	for _, mp := range goal.ModelPortfolioDetails {
+		w, _ := decimal.NewFromString(strings.TrimSpace(mp.Weight))
+		total = total.Add(w)
+		inModel[mp.Ticker] = true
+	}
+	if !opts.NormalizeWeights && total.IsPositive() && !total.Equal(decimal.NewFromInt(1)) {
+		out = append(out, models.Warning{
+			Code:    models.CodeWeightSum,
+			Message: fmt.Sprintf("model weights sum to %s, not 1", total.String()),
+		})
+	}
+	for _, h := range goal.GoalDetails {
+		if inModel[h.Ticker] || h.Keep || goal.KeepUnmodeled {
+			continue
+		}
+		if value, _ := decimal.NewFromString(h.Value); value.IsPositive() {
+			out = append(out, models.Warning{
+				Code:    models.CodeHoldingNotInModel,
+				Message: fmt.Sprintf("holding %s is not in the model portfolio", h.Ticker),
+				Ticker:  h.Ticker,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,39 @@
+package splitter
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// whatIf re-runs the investment split for goal at each of amounts and summarises the
+// outcome, so callers can see how the order size affects minimum violations. The main
+// result is not affected.
+func whatIf(goal models.Goal, amounts []string, amountPrec, unitPrec int, opts Options) []models.WhatIfResult {
+	opts.WhatIfAmounts = nil
+	opts.IncludeEffectiveWeights = true
+	opts.Explain = false
+
+	out := make([]models.WhatIfResult, 0, len(amounts))
+	for _, s := range amounts {
+		s = strings.TrimSpace(s)
+		amount, _ := decimal.NewFromString(s)
+		goal.OrderAmount = s
+		r := ProcessInvestment(goal, amountPrec, unitPrec, opts)
+
+		invested := decimal.Zero
+		for _, d := range r.TransactionDetails {
+			v, _ := decimal.NewFromString(d.Value)
+			invested = invested.Add(v)
+		}
+		_, violations := CountTrades(r.TransactionDetails)
+		out = append(out, models.WhatIfResult{
+			OrderAmount:      amount.StringFixed(int32(amountPrec)),
+			Violations:       violations,
+			Unallocated:      amount.Sub(invested).StringFixed(int32(amountPrec)),
+			EffectiveWeights: r.EffectiveWeights,
+		})
+	}
+	return out
+}
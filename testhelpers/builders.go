@@ -0,0 +1,244 @@
+// Package testhelpers builds valid models values for tests. Every field a builder does not
+// set gets a default that passes the API's validation, so a test only spells out the
+// figures it is about:
+//
+//	goal := testhelpers.NewGoalBuilder("g1").
+//		WithOrderAmount("1000").
+//		WithTicker("AAPL", "0.5", "150.00").
+//		WithTicker("MSFT", "0.5", "300.00").
+//		Build()
+package testhelpers
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/valentinpj/smart-splitter/models"
+)
+
+// GoalBuilder builds a models.Goal. The zero value is not usable; start from NewGoalBuilder.
+type GoalBuilder struct {
+	goal models.Goal
+}
+
+// NewGoalBuilder returns a builder for an investment of 1000 into an empty model portfolio
+// "mp-<goalID>". Add at least one product with WithTicker or WithModelItem before Build.
+func NewGoalBuilder(goalID string) *GoalBuilder {
+	return &GoalBuilder{goal: models.Goal{
+		GoalID:           goalID,
+		OrderAmount:      "1000",
+		OrderType:        "Investment",
+		ModelPortfolioID: "mp-" + goalID,
+	}}
+}
+
+// WithOrderAmount sets the order amount.
+func (b *GoalBuilder) WithOrderAmount(amount string) *GoalBuilder {
+	b.goal.OrderAmount = amount
+	return b
+}
+
+// WithOrderUnit makes the goal a redemption of units instead of an amount, clearing the
+// order amount.
+func (b *GoalBuilder) WithOrderUnit(units string) *GoalBuilder {
+	b.goal.OrderAmount, b.goal.OrderUnit = "", units
+	return b
+}
+
+// WithOrderType sets the order type, "Investment" or "Redemption".
+func (b *GoalBuilder) WithOrderType(orderType string) *GoalBuilder {
+	b.goal.OrderType = orderType
+	return b
+}
+
+// AsRedemption makes the goal a redemption. Redemptions need holdings; add them with
+// WithHolding.
+func (b *GoalBuilder) AsRedemption() *GoalBuilder {
+	return b.WithOrderType("Redemption")
+}
+
+// WithModelPortfolioID sets the model portfolio id.
+func (b *GoalBuilder) WithModelPortfolioID(id string) *GoalBuilder {
+	b.goal.ModelPortfolioID = id
+	return b
+}
+
+// WithTicker adds a model portfolio product with the given weight and market price and no
+// minimums or fee.
+func (b *GoalBuilder) WithTicker(ticker, weight, price string) *GoalBuilder {
+	return b.WithModelItem(NewModelItemBuilder(ticker).WithWeight(weight).WithMarketPrice(price).Build())
+}
+
+// WithModelItem adds a model portfolio product, e.g. one built with ModelItemBuilder.
+func (b *GoalBuilder) WithModelItem(mp models.ModelItem) *GoalBuilder {
+	b.goal.ModelPortfolioDetails = append(b.goal.ModelPortfolioDetails, mp)
+	return b
+}
+
+// WithHolding adds a holding of units at price, with no minimums or fee. Its value is
+// units × price truncated to 2 decimal places, the default amountDecimalPrecision.
+func (b *GoalBuilder) WithHolding(ticker, units, price string) *GoalBuilder {
+	u, _ := decimal.NewFromString(units)
+	p, _ := decimal.NewFromString(price)
+	return b.WithHoldingValue(ticker, units, price, u.Mul(p).Truncate(2).String())
+}
+
+// WithHoldingValue adds a holding with an explicit value, for tests that need a value
+// which is not units × price.
+func (b *GoalBuilder) WithHoldingValue(ticker, units, price, value string) *GoalBuilder {
+	b.goal.GoalDetails = append(b.goal.GoalDetails, models.Holding{
+		Ticker:                    ticker,
+		Units:                     units,
+		MarketPrice:               price,
+		Value:                     value,
+		MinInitialInvestmentAmt:   "0",
+		MinInitialInvestmentUnits: "0",
+		MinTopupAmt:               "0",
+		MinTopupUnits:             "0",
+		MinRedemptionAmt:          "0",
+		MinRedemptionUnits:        "0",
+		MinHoldingAmt:             "0",
+		MinHoldingUnits:           "0",
+		TransactionFee:            "0",
+	})
+	return b
+}
+
+// WithBlockedUnits marks units of the holding of ticker, added earlier, as blocked from
+// sale.
+func (b *GoalBuilder) WithBlockedUnits(ticker, units string) *GoalBuilder {
+	for i := range b.goal.GoalDetails {
+		if b.goal.GoalDetails[i].Ticker == ticker {
+			b.goal.GoalDetails[i].BlockedUnits = units
+		}
+	}
+	return b
+}
+
+// WithMinTradeAmt sets the goal's minimum trade amount.
+func (b *GoalBuilder) WithMinTradeAmt(amount string) *GoalBuilder {
+	b.goal.MinTradeAmt = amount
+	return b
+}
+
+// WithMaxTrades sets the goal's maximum number of trades.
+func (b *GoalBuilder) WithMaxTrades(n string) *GoalBuilder {
+	b.goal.MaxTrades = n
+	return b
+}
+
+// WithAllocationStrategy sets the goal's allocation strategy.
+func (b *GoalBuilder) WithAllocationStrategy(strategy string) *GoalBuilder {
+	b.goal.AllocationStrategy = strategy
+	return b
+}
+
+// WithVolatilityBuffer sets the goal's volatility buffer, overriding the request's.
+func (b *GoalBuilder) WithVolatilityBuffer(buffer string) *GoalBuilder {
+	b.goal.VolatilityBuffer = buffer
+	return b
+}
+
+// Build returns the goal. The builder can keep being used; later calls do not change
+// goals already built.
+func (b *GoalBuilder) Build() models.Goal {
+	g := b.goal
+	g.GoalDetails = append([]models.Holding(nil), b.goal.GoalDetails...)
+	g.ModelPortfolioDetails = append([]models.ModelItem(nil), b.goal.ModelPortfolioDetails...)
+	return g
+}
+
+// ModelItemBuilder builds a models.ModelItem. The zero value is not usable; start from
+// NewModelItemBuilder.
+type ModelItemBuilder struct {
+	mp models.ModelItem
+}
+
+// NewModelItemBuilder returns a builder for a product with weight 1, market price 1 and no
+// minimums or fee.
+func NewModelItemBuilder(ticker string) *ModelItemBuilder {
+	return &ModelItemBuilder{mp: models.ModelItem{
+		Ticker:                    ticker,
+		Weight:                    "1",
+		MarketPrice:               "1",
+		MinInitialInvestmentAmt:   "0",
+		MinInitialInvestmentUnits: "0",
+		MinTopupAmt:               "0",
+		MinTopupUnits:             "0",
+		MinRedemptionAmt:          "0",
+		MinRedemptionUnits:        "0",
+		MinHoldingAmt:             "0",
+		MinHoldingUnits:           "0",
+		TransactionFee:            "0",
+	}}
+}
+
+// WithWeight sets the model weight.
+func (b *ModelItemBuilder) WithWeight(weight string) *ModelItemBuilder {
+	b.mp.Weight = weight
+	return b
+}
+
+// WithMarketPrice sets the market price.
+func (b *ModelItemBuilder) WithMarketPrice(price string) *ModelItemBuilder {
+	b.mp.MarketPrice = price
+	return b
+}
+
+// WithMinInitialInvestment sets the minimum first purchase, by amount and by units.
+func (b *ModelItemBuilder) WithMinInitialInvestment(amt, units string) *ModelItemBuilder {
+	b.mp.MinInitialInvestmentAmt, b.mp.MinInitialInvestmentUnits = amt, units
+	return b
+}
+
+// WithMinTopup sets the minimum purchase into an existing position, by amount and by units.
+func (b *ModelItemBuilder) WithMinTopup(amt, units string) *ModelItemBuilder {
+	b.mp.MinTopupAmt, b.mp.MinTopupUnits = amt, units
+	return b
+}
+
+// WithMinRedemption sets the minimum sell, by amount and by units.
+func (b *ModelItemBuilder) WithMinRedemption(amt, units string) *ModelItemBuilder {
+	b.mp.MinRedemptionAmt, b.mp.MinRedemptionUnits = amt, units
+	return b
+}
+
+// WithMinHolding sets the minimum a partial sell must leave behind, by amount and by units.
+func (b *ModelItemBuilder) WithMinHolding(amt, units string) *ModelItemBuilder {
+	b.mp.MinHoldingAmt, b.mp.MinHoldingUnits = amt, units
+	return b
+}
+
+// WithTransactionFee sets the fee rate, in [0, 1).
+func (b *ModelItemBuilder) WithTransactionFee(fee string) *ModelItemBuilder {
+	b.mp.TransactionFee = fee
+	return b
+}
+
+// WithRedemptionFee sets the fee rate charged on sells, in [0, 1).
+func (b *ModelItemBuilder) WithRedemptionFee(fee string) *ModelItemBuilder {
+	b.mp.RedemptionFee = fee
+	return b
+}
+
+// WithPriority sets the repair priority.
+func (b *ModelItemBuilder) WithPriority(priority string) *ModelItemBuilder {
+	b.mp.Priority = priority
+	return b
+}
+
+// WithAllocationFloor sets the minimum share of the order the product receives.
+func (b *ModelItemBuilder) WithAllocationFloor(floor string) *ModelItemBuilder {
+	b.mp.AllocationFloor = floor
+	return b
+}
+
+// ProtectFromZeroOut keeps the repair step from zeroing the product.
+func (b *ModelItemBuilder) ProtectFromZeroOut() *ModelItemBuilder {
+	b.mp.ProtectFromZeroOut = true
+	return b
+}
+
+// Build returns the model item.
+func (b *ModelItemBuilder) Build() models.ModelItem {
+	return b.mp
+}